@@ -0,0 +1,162 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+const (
+	// ChallengeVersion is the version of the authch messages this package
+	// issues and answers.
+	ChallengeVersion int32 = 1
+
+	// ChallengeSize is the number of random bytes in a challenge.
+	ChallengeSize = 32
+
+	// maxSignAttempts is the number of client nonces Sign tries to obtain a
+	// signature of the size the wire protocol requires.
+	maxSignAttempts = 16
+)
+
+var (
+	// ErrInvalidChallenge is returned when an authch message does not
+	// carry a challenge of ChallengeSize bytes at ChallengeVersion.
+	ErrInvalidChallenge = errors.New("invalid challenge")
+
+	// ErrInvalidPublicKey is returned when the public key of an authresp
+	// message is not a compressed secp256k1 public key.
+	ErrInvalidPublicKey = errors.New("invalid public key")
+
+	// ErrInvalidSignature is returned when the signature of an authresp
+	// message is not a DER encoded ECDSA signature of the expected size.
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// NewChallenge returns a new authch message with a random challenge of
+// ChallengeSize bytes.
+func NewChallenge() (*wire.MsgAuthch, error) {
+	challenge := make([]byte, ChallengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, err
+	}
+
+	return &wire.MsgAuthch{
+		Version:   ChallengeVersion,
+		Length:    ChallengeSize,
+		Challenge: challenge,
+	}, nil
+}
+
+// checkChallenge returns an error wrapping ErrInvalidChallenge unless msg
+// carries a challenge this package issues.
+func checkChallenge(msg *wire.MsgAuthch) error {
+	switch {
+	case msg == nil:
+		return fmt.Errorf("%w: no challenge", ErrInvalidChallenge)
+
+	case msg.Version != ChallengeVersion:
+		return fmt.Errorf("%w: version %d", ErrInvalidChallenge, msg.Version)
+
+	case len(msg.Challenge) != ChallengeSize || msg.Length != ChallengeSize:
+		return fmt.Errorf("%w: %d bytes with length %d, want %d", ErrInvalidChallenge,
+			len(msg.Challenge), msg.Length, ChallengeSize)
+	}
+
+	return nil
+}
+
+// digest returns the hash signed to answer challenge with clientNonce.
+func digest(challenge []byte, clientNonce uint64) []byte {
+	h := sha256.New()
+	h.Write(challenge)
+	_ = binary.Write(h, binary.LittleEndian, clientNonce)
+
+	first := h.Sum(nil)
+	second := sha256.Sum256(first)
+
+	return second[:]
+}
+
+// Sign answers the challenge carried by msg with an authresp message signed
+// by key.
+func Sign(key *ec.PrivateKey, msg *wire.MsgAuthch) (*wire.MsgAuthresp, error) {
+	if key == nil {
+		return nil, errors.New("nil private key")
+	}
+
+	if err := checkChallenge(msg); err != nil {
+		return nil, err
+	}
+
+	pub := key.PubKey().Compressed()
+
+	for range maxSignAttempts {
+		nonce, err := wire.RandomUint64()
+		if err != nil {
+			return nil, err
+		}
+
+		sig, err := key.Sign(digest(msg.Challenge, nonce))
+		if err != nil {
+			return nil, err
+		}
+
+		// Signatures with short R or S values are shorter than the wire
+		// protocol allows, in which case another nonce is tried.
+		der := sig.Serialize()
+		if len(der) < wire.SECP256K1_DER_SIGN_MIN_SIZE_IN_BYTES {
+			continue
+		}
+
+		return &wire.MsgAuthresp{
+			PublicKeyLength: uint32(len(pub)),
+			PublicKey:       pub,
+			ClientNonce:     nonce,
+			SignatureLength: uint32(len(der)),
+			Signature:       der,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no signature of at least %d bytes in %d attempts",
+		wire.SECP256K1_DER_SIGN_MIN_SIZE_IN_BYTES, maxSignAttempts)
+}
+
+// parseResponse returns the public key and signature of msg.
+func parseResponse(msg *wire.MsgAuthresp) (*ec.PublicKey, *ec.Signature, error) {
+	if len(msg.PublicKey) != wire.SECP256K1_COMP_PUB_KEY_SIZE_IN_BYTES ||
+		msg.PublicKeyLength != uint32(len(msg.PublicKey)) {
+		return nil, nil, fmt.Errorf("%w: %d bytes with length %d, want %d", ErrInvalidPublicKey,
+			len(msg.PublicKey), msg.PublicKeyLength, wire.SECP256K1_COMP_PUB_KEY_SIZE_IN_BYTES)
+	}
+
+	key, err := ec.ParsePubKey(msg.PublicKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+	}
+
+	if len(msg.Signature) < wire.SECP256K1_DER_SIGN_MIN_SIZE_IN_BYTES ||
+		len(msg.Signature) > wire.SECP256K1_DER_SIGN_MAX_SIZE_IN_BYTES ||
+		msg.SignatureLength != uint32(len(msg.Signature)) {
+		return nil, nil, fmt.Errorf("%w: %d bytes with length %d, want %d to %d",
+			ErrInvalidSignature, len(msg.Signature), msg.SignatureLength,
+			wire.SECP256K1_DER_SIGN_MIN_SIZE_IN_BYTES, wire.SECP256K1_DER_SIGN_MAX_SIZE_IN_BYTES)
+	}
+
+	sig, err := ec.ParseDERSignature(msg.Signature)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	return key, sig, nil
+}
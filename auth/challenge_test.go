@@ -0,0 +1,137 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"testing"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+// newKey returns a new private key.
+func newKey(t *testing.T) *ec.PrivateKey {
+	t.Helper()
+
+	key, err := ec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return key
+}
+
+// TestNewChallenge ensures challenges are random and well-formed.
+func TestNewChallenge(t *testing.T) {
+	msg, err := NewChallenge()
+	require.NoError(t, err)
+	require.NoError(t, checkChallenge(msg))
+	assert.Equal(t, ChallengeVersion, msg.Version)
+	assert.Equal(t, uint32(ChallengeSize), msg.Length)
+	assert.Len(t, msg.Challenge, ChallengeSize)
+	assert.LessOrEqual(t, uint64(msg.PayloadSize(wire.ProtocolVersion)),
+		msg.MaxPayloadLength(wire.ProtocolVersion))
+
+	other, err := NewChallenge()
+	require.NoError(t, err)
+	assert.NotEqual(t, msg.Challenge, other.Challenge)
+}
+
+// TestSign ensures responses carry the compressed public key and a signature
+// of the challenge and client nonce.
+func TestSign(t *testing.T) {
+	key := newKey(t)
+
+	msg, err := NewChallenge()
+	require.NoError(t, err)
+
+	resp, err := Sign(key, msg)
+	require.NoError(t, err)
+
+	assert.Equal(t, key.PubKey().Compressed(), resp.PublicKey)
+	assert.Equal(t, uint32(wire.SECP256K1_COMP_PUB_KEY_SIZE_IN_BYTES), resp.PublicKeyLength)
+	assert.Equal(t, uint32(len(resp.Signature)), resp.SignatureLength)
+
+	pub, sig, err := parseResponse(resp)
+	require.NoError(t, err)
+	assert.True(t, pub.IsEqual(key.PubKey()))
+	assert.True(t, sig.Verify(digest(msg.Challenge, resp.ClientNonce), pub))
+	assert.False(t, sig.Verify(digest(msg.Challenge, resp.ClientNonce+1), pub))
+
+	// Every response uses a new client nonce.
+	other, err := Sign(key, msg)
+	require.NoError(t, err)
+	assert.NotEqual(t, resp.ClientNonce, other.ClientNonce)
+}
+
+// TestSignErrors ensures only well-formed challenges are signed.
+func TestSignErrors(t *testing.T) {
+	key := newKey(t)
+
+	valid, err := NewChallenge()
+	require.NoError(t, err)
+
+	_, err = Sign(nil, valid)
+	require.Error(t, err)
+
+	tests := []struct {
+		name string
+		msg  *wire.MsgAuthch
+	}{
+		{"nil", nil},
+		{"version", &wire.MsgAuthch{Version: 2, Length: ChallengeSize, Challenge: valid.Challenge}},
+		{"length", &wire.MsgAuthch{Version: 1, Length: ChallengeSize - 1, Challenge: valid.Challenge}},
+		{"short", wire.NewMsgAuthch("short")},
+		{"long", &wire.MsgAuthch{Version: 1, Length: ChallengeSize + 1, Challenge: make([]byte, ChallengeSize+1)}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Sign(key, test.msg)
+			require.ErrorIs(t, err, ErrInvalidChallenge)
+		})
+	}
+}
+
+// TestParseResponseErrors ensures malformed responses are told apart.
+func TestParseResponseErrors(t *testing.T) {
+	msg, err := NewChallenge()
+	require.NoError(t, err)
+
+	resp, err := Sign(newKey(t), msg)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(r *wire.MsgAuthresp)
+		want   error
+	}{
+		{"public key length", func(r *wire.MsgAuthresp) { r.PublicKeyLength++ }, ErrInvalidPublicKey},
+		{"uncompressed public key", func(r *wire.MsgAuthresp) {
+			r.PublicKey = append(r.PublicKey, make([]byte, 32)...)
+			r.PublicKeyLength = 65
+		}, ErrInvalidPublicKey},
+		{"public key prefix", func(r *wire.MsgAuthresp) { r.PublicKey[0] = 0x05 }, ErrInvalidPublicKey},
+		{"signature length", func(r *wire.MsgAuthresp) { r.SignatureLength-- }, ErrInvalidSignature},
+		{"short signature", func(r *wire.MsgAuthresp) {
+			r.Signature = r.Signature[:wire.SECP256K1_DER_SIGN_MIN_SIZE_IN_BYTES-1]
+			r.SignatureLength = uint32(len(r.Signature))
+		}, ErrInvalidSignature},
+		{"signature encoding", func(r *wire.MsgAuthresp) { r.Signature[0] = 0x31 }, ErrInvalidSignature},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := *resp
+			r.PublicKey = append([]byte(nil), resp.PublicKey...)
+			r.Signature = append([]byte(nil), resp.Signature...)
+			test.modify(&r)
+
+			_, _, err := parseResponse(&r)
+			require.ErrorIs(t, err, test.want)
+		})
+	}
+}
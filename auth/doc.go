@@ -0,0 +1,61 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package auth implements the challenge-response authentication carried by the
+authch (wire.MsgAuthch) and authresp (wire.MsgAuthresp) messages, which lets
+private links between nodes, such as those between miners, only admit peers
+holding one of a set of secp256k1 keys.
+
+# Protocol
+
+The verifying side sends an authch message with a random challenge of
+ChallengeSize bytes.  The authenticating side picks a random client nonce and
+answers with an authresp message holding its compressed public key, the nonce
+and a DER encoded ECDSA signature of
+
+	SHA256(SHA256(challenge || client nonce as 8 little-endian bytes))
+
+The verifying side accepts the response when the public key is on its
+allow-list and the signature is valid for the challenge it sent.
+
+# Usage
+
+The verifying side issues a Challenge per connection with a Verifier and
+verifies the response against it:
+
+	v := auth.NewVerifier(auth.NewAllowList(minerKeys...), 0)
+	c, err := v.NewChallenge()
+	if err != nil {
+		return err
+	}
+	// Send c.Msg() and read the authresp message resp.
+	key, err := c.Verify(resp)
+	if err != nil {
+		return err
+	}
+
+The authenticating side answers the challenge it receives with Sign:
+
+	resp, err := auth.Sign(privateKey, authch)
+
+# Replay Protection
+
+A signature only verifies against the challenge it was made for, which is
+random for every connection.  Each challenge is accepted at most once, any
+attempt at verifying a response using it up, and expires after the timeout of
+its verifier.  A captured response is therefore useless on any other
+connection, and a response cannot be retried on the same one.  The client
+nonce in turn keeps the authenticating side from signing data chosen
+entirely by the verifying side.
+
+# Errors
+
+Failures wrap one of the sentinel errors ErrInvalidChallenge,
+ErrInvalidPublicKey, ErrInvalidSignature, ErrKeyNotAllowed, ErrBadSignature,
+ErrChallengeExpired and ErrChallengeUsed, which can be tested with errors.Is.
+The first three report malformed messages, while the others report responses
+which are well-formed but are not accepted.
+*/
+package auth
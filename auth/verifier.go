@@ -0,0 +1,185 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	ec "github.com/bsv-blockchain/go-sdk/primitives/ec"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+// DefaultChallengeTimeout is the time a challenge may be answered in unless
+// configured otherwise.
+const DefaultChallengeTimeout = 30 * time.Second
+
+var (
+	// ErrKeyNotAllowed is returned when an authresp message is signed with
+	// a public key which is not on the allow-list.
+	ErrKeyNotAllowed = errors.New("public key not allowed")
+
+	// ErrBadSignature is returned when the signature of an authresp message
+	// does not sign the challenge with its public key.
+	ErrBadSignature = errors.New("signature verification failed")
+
+	// ErrChallengeExpired is returned when an authresp message answers a
+	// challenge after its timeout.
+	ErrChallengeExpired = errors.New("challenge expired")
+
+	// ErrChallengeUsed is returned when a response is verified against a
+	// challenge which has already been used to verify one.
+	ErrChallengeUsed = errors.New("challenge already used")
+)
+
+// AllowList is a set of the public keys which may authenticate.
+//
+// All methods are safe for concurrent access.
+type AllowList struct {
+	mtx sync.RWMutex
+	// keys holds the compressed form of the public keys.
+	keys map[string]struct{}
+}
+
+// NewAllowList returns a new AllowList holding keys.
+func NewAllowList(keys ...*ec.PublicKey) *AllowList {
+	l := &AllowList{keys: make(map[string]struct{}, len(keys))}
+	for _, key := range keys {
+		l.Add(key)
+	}
+
+	return l
+}
+
+// Add adds key to the allow-list.
+func (l *AllowList) Add(key *ec.PublicKey) {
+	l.mtx.Lock()
+	l.keys[string(key.Compressed())] = struct{}{}
+	l.mtx.Unlock()
+}
+
+// Remove removes key from the allow-list and reports whether it was on it.
+func (l *AllowList) Remove(key *ec.PublicKey) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	k := string(key.Compressed())
+	_, ok := l.keys[k]
+	delete(l.keys, k)
+
+	return ok
+}
+
+// Contains returns whether key is on the allow-list.
+func (l *AllowList) Contains(key *ec.PublicKey) bool {
+	l.mtx.RLock()
+	_, ok := l.keys[string(key.Compressed())]
+	l.mtx.RUnlock()
+
+	return ok
+}
+
+// Len returns the number of keys on the allow-list.
+func (l *AllowList) Len() int {
+	l.mtx.RLock()
+	defer l.mtx.RUnlock()
+
+	return len(l.keys)
+}
+
+// Verifier issues challenges and verifies the responses to them against an
+// allow-list.
+//
+// All methods are safe for concurrent access.
+type Verifier struct {
+	allow   *AllowList
+	timeout time.Duration
+}
+
+// NewVerifier returns a new Verifier which accepts the keys on allow, which
+// may change while the verifier is in use, in response to challenges
+// answered within timeout.  Zero timeout means DefaultChallengeTimeout.
+func NewVerifier(allow *AllowList, timeout time.Duration) *Verifier {
+	if timeout <= 0 {
+		timeout = DefaultChallengeTimeout
+	}
+
+	return &Verifier{allow: allow, timeout: timeout}
+}
+
+// NewChallenge returns a new challenge to send to a peer.
+func (v *Verifier) NewChallenge() (*Challenge, error) {
+	msg, err := NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Challenge{
+		verifier: v,
+		msg:      msg,
+		expires:  time.Now().Add(v.timeout),
+	}, nil
+}
+
+// Challenge is a challenge issued by a Verifier, which accepts one response.
+//
+// All methods are safe for concurrent access.
+type Challenge struct {
+	verifier *Verifier
+	msg      *wire.MsgAuthch
+	expires  time.Time
+
+	mtx  sync.Mutex
+	used bool
+}
+
+// Msg returns the authch message carrying the challenge.  It must not be
+// modified.
+func (c *Challenge) Msg() *wire.MsgAuthch {
+	return c.msg
+}
+
+// Expires returns the time after which the challenge is no longer accepted.
+func (c *Challenge) Expires() time.Time {
+	return c.expires
+}
+
+// Verify returns the public key resp is signed with when it answers the
+// challenge with a valid signature made with a key on the allow-list.  The
+// challenge is used up by the first call, whether it succeeds or not, so
+// further calls fail with ErrChallengeUsed.
+func (c *Challenge) Verify(resp *wire.MsgAuthresp) (*ec.PublicKey, error) {
+	c.mtx.Lock()
+	used := c.used
+	c.used = true
+	c.mtx.Unlock()
+
+	if used {
+		return nil, ErrChallengeUsed
+	}
+
+	if time.Now().After(c.expires) {
+		return nil, fmt.Errorf("%w: answered after %v", ErrChallengeExpired,
+			c.verifier.timeout)
+	}
+
+	key, sig, err := parseResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.verifier.allow.Contains(key) {
+		return nil, fmt.Errorf("%w: %x", ErrKeyNotAllowed, resp.PublicKey)
+	}
+
+	if !sig.Verify(digest(c.msg.Challenge, resp.ClientNonce), key) {
+		return nil, fmt.Errorf("%w: key %x", ErrBadSignature, resp.PublicKey)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+// TestAllowList ensures keys can be added and removed.
+func TestAllowList(t *testing.T) {
+	a, b := newKey(t).PubKey(), newKey(t).PubKey()
+
+	l := NewAllowList(a, a)
+	assert.Equal(t, 1, l.Len())
+	assert.True(t, l.Contains(a))
+	assert.False(t, l.Contains(b))
+
+	l.Add(b)
+	assert.True(t, l.Contains(b))
+
+	assert.True(t, l.Remove(a))
+	assert.False(t, l.Remove(a))
+	assert.False(t, l.Contains(a))
+	assert.Equal(t, 1, l.Len())
+}
+
+// TestVerifier ensures responses signed with an allowed key are accepted
+// once.
+func TestVerifier(t *testing.T) {
+	key := newKey(t)
+	v := NewVerifier(NewAllowList(key.PubKey()), 0)
+
+	c, err := v.NewChallenge()
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(DefaultChallengeTimeout), c.Expires(), time.Second)
+
+	resp, err := Sign(key, c.Msg())
+	require.NoError(t, err)
+
+	pub, err := c.Verify(resp)
+	require.NoError(t, err)
+	assert.True(t, pub.IsEqual(key.PubKey()))
+
+	// The same response cannot be replayed against the challenge.
+	_, err = c.Verify(resp)
+	require.ErrorIs(t, err, ErrChallengeUsed)
+
+	// Nor against any other challenge.
+	other, err := v.NewChallenge()
+	require.NoError(t, err)
+
+	_, err = other.Verify(resp)
+	require.ErrorIs(t, err, ErrBadSignature)
+}
+
+// TestVerifierWire ensures challenges and responses survive the wire
+// encoding on their way between the peers.
+func TestVerifierWire(t *testing.T) {
+	key := newKey(t)
+	v := NewVerifier(NewAllowList(key.PubKey()), 0)
+
+	c, err := v.NewChallenge()
+	require.NoError(t, err)
+
+	// roundTrip writes msg and returns the message read back.
+	roundTrip := func(msg wire.Message) wire.Message {
+		var buf bytes.Buffer
+		require.NoError(t, wire.WriteMessage(&buf, msg, wire.ProtocolVersion, wire.MainNet))
+
+		read, _, err := wire.ReadMessage(&buf, wire.ProtocolVersion, wire.MainNet)
+		require.NoError(t, err)
+		assert.Zero(t, buf.Len())
+
+		return read
+	}
+
+	authch, ok := roundTrip(c.Msg()).(*wire.MsgAuthch)
+	require.True(t, ok)
+	assert.Equal(t, c.Msg(), authch)
+
+	resp, err := Sign(key, authch)
+	require.NoError(t, err)
+
+	authresp, ok := roundTrip(resp).(*wire.MsgAuthresp)
+	require.True(t, ok)
+	assert.Equal(t, resp, authresp)
+
+	pub, err := c.Verify(authresp)
+	require.NoError(t, err)
+	assert.True(t, pub.IsEqual(key.PubKey()))
+}
+
+// TestVerifierErrors ensures each reason to refuse a response is reported.
+func TestVerifierErrors(t *testing.T) {
+	allowed, stranger := newKey(t), newKey(t)
+	v := NewVerifier(NewAllowList(allowed.PubKey()), 0)
+
+	tests := []struct {
+		name    string
+		respond func(c *Challenge) *wire.MsgAuthresp
+		want    error
+	}{
+		{"unknown key", func(c *Challenge) *wire.MsgAuthresp {
+			resp, err := Sign(stranger, c.Msg())
+			require.NoError(t, err)
+
+			return resp
+		}, ErrKeyNotAllowed},
+		{"tampered nonce", func(c *Challenge) *wire.MsgAuthresp {
+			resp, err := Sign(allowed, c.Msg())
+			require.NoError(t, err)
+
+			resp.ClientNonce++
+
+			return resp
+		}, ErrBadSignature},
+		{"borrowed key", func(c *Challenge) *wire.MsgAuthresp {
+			resp, err := Sign(stranger, c.Msg())
+			require.NoError(t, err)
+
+			resp.PublicKey = allowed.PubKey().Compressed()
+
+			return resp
+		}, ErrBadSignature},
+		{"malformed", func(c *Challenge) *wire.MsgAuthresp {
+			resp, err := Sign(allowed, c.Msg())
+			require.NoError(t, err)
+
+			resp.Signature = resp.Signature[:10]
+
+			return resp
+		}, ErrInvalidSignature},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := v.NewChallenge()
+			require.NoError(t, err)
+
+			_, err = c.Verify(test.respond(c))
+			require.ErrorIs(t, err, test.want)
+
+			// A failed attempt uses the challenge up too.
+			resp, err := Sign(allowed, c.Msg())
+			require.NoError(t, err)
+
+			_, err = c.Verify(resp)
+			require.ErrorIs(t, err, ErrChallengeUsed)
+		})
+	}
+}
+
+// TestVerifierExpiry ensures challenges are only accepted within the timeout
+// and keys removed from the allow-list are refused.
+func TestVerifierExpiry(t *testing.T) {
+	key := newKey(t)
+	allow := NewAllowList(key.PubKey())
+	v := NewVerifier(allow, 10*time.Millisecond)
+
+	c, err := v.NewChallenge()
+	require.NoError(t, err)
+
+	resp, err := Sign(key, c.Msg())
+	require.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	_, err = c.Verify(resp)
+	require.ErrorIs(t, err, ErrChallengeExpired)
+
+	allow.Remove(key.PubKey())
+
+	c, err = v.NewChallenge()
+	require.NoError(t, err)
+
+	resp, err = Sign(key, c.Msg())
+	require.NoError(t, err)
+
+	_, err = c.Verify(resp)
+	require.ErrorIs(t, err, ErrKeyNotAllowed)
+}
@@ -4,6 +4,8 @@
 
 package wire
 
+import "io"
+
 // blockArenaChunkSize is the maximum size in bytes of any standard chunk
 // allocated by blockArena. 4 MiB matches a typical OS huge-page boundary and
 // is large enough to hold dozens of standard scripts in a single allocation.
@@ -111,3 +113,24 @@ func (a *blockArena) Alloc(n int) []byte {
 	a.offset = n
 	return a.active[0:n:n]
 }
+
+// readScript reads a variable length byte array that represents a transaction
+// script into a slice allocated from the arena.  Its length is validated
+// exactly like the readScript function.  This is part of the scriptSource
+// interface implementation.
+func (a *blockArena) readScript(r io.Reader, pver uint32, maxAllowed uint64, fieldName string) ([]byte, error) {
+	count, err := readScriptLength(r, pver, maxAllowed, fieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Alloc returns nil for count == 0, which is valid (empty script).
+	s := a.Alloc(int(count))
+	if count > 0 {
+		if _, err = io.ReadFull(r, s); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
@@ -6,7 +6,11 @@ package wire
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
 	"io"
+	"strconv"
 	"time"
 
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
@@ -72,6 +76,13 @@ func (h *BlockHeader) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) err
 	return writeBlockHeader(w, pver, h)
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (h *BlockHeader) AppendBsvEncode(dst []byte, _ uint32, _ MessageEncoding) ([]byte, error) {
+	return appendBlockHeader(dst, h), nil
+}
+
 // Deserialize decodes a block header from r into the receiver using a format
 // that is suitable for long-term storage such as a database while respecting
 // the Version field.
@@ -92,6 +103,106 @@ func (h *BlockHeader) Serialize(w io.Writer) error {
 	return writeBlockHeader(w, 0, h)
 }
 
+// AppendSerialize appends the encoding of the block header, using the format
+// of Serialize, to dst and returns the extended buffer.
+func (h *BlockHeader) AppendSerialize(dst []byte) []byte {
+	return appendBlockHeader(dst, h)
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the block header at
+// the latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (h *BlockHeader) MarshalBinary() ([]byte, error) {
+	return h.AppendBsvEncode(nil, ProtocolVersion, LatestEncoding)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a block header, into the receiver.  This is part of the
+// encoding.BinaryUnmarshaler interface implementation.
+func (h *BlockHeader) UnmarshalBinary(data []byte) error {
+	return decodeExact(data, "block header", func(r io.Reader) error {
+		return readBlockHeader(r, ProtocolVersion, h)
+	})
+}
+
+// WriteTo writes the bitcoin protocol encoding of the block header to w and
+// returns the number of bytes written.  This is part of the io.WriterTo
+// interface implementation.
+func (h *BlockHeader) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(h, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding of a block
+// header read from r until EOF and returns the number of bytes read.  This is
+// part of the io.ReaderFrom interface implementation.
+func (h *BlockHeader) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(h, r, blockHeaderLen)
+}
+
+// blockHeaderJSON is the JSON representation of a block header.  It follows
+// the getblockheader RPC of bitcoind and is embedded in the representation of
+// the messages carrying a header.
+type blockHeaderJSON struct {
+	Hash       jsonHash `json:"hash"`
+	Version    int32    `json:"version"`
+	PrevBlock  jsonHash `json:"previousblockhash"`
+	MerkleRoot jsonHash `json:"merkleroot"`
+	Time       uint32   `json:"time"`
+	Bits       string   `json:"bits"`
+	Nonce      uint32   `json:"nonce"`
+}
+
+// toJSON returns the JSON representation of the block header.
+func (h *BlockHeader) toJSON() blockHeaderJSON {
+	return blockHeaderJSON{
+		Hash:       jsonHash(h.BlockHash()),
+		Version:    h.Version,
+		PrevBlock:  jsonHash(h.PrevBlock),
+		MerkleRoot: jsonHash(h.MerkleRoot),
+		Time:       uint32(h.Timestamp.Unix()),
+		Bits:       fmt.Sprintf("%08x", h.Bits),
+		Nonce:      h.Nonce,
+	}
+}
+
+// fromJSON sets the block header from its JSON representation.  The hash is
+// computed from the other fields and therefore ignored.
+func (h *BlockHeader) fromJSON(j *blockHeaderJSON) error {
+	bits, err := strconv.ParseUint(j.Bits, 16, 32)
+	if err != nil {
+		return jsonError(fmt.Sprintf("invalid bits %q", j.Bits))
+	}
+
+	*h = BlockHeader{
+		Version:    j.Version,
+		PrevBlock:  chainhash.Hash(j.PrevBlock),
+		MerkleRoot: chainhash.Hash(j.MerkleRoot),
+		Timestamp:  time.Unix(int64(j.Time), 0),
+		Bits:       uint32(bits),
+		Nonce:      j.Nonce,
+	}
+
+	return nil
+}
+
+// MarshalJSON returns the JSON representation of the block header in the
+// format of the getblockheader RPC of bitcoind.  This is part of the
+// json.Marshaler interface implementation.
+func (h BlockHeader) MarshalJSON() ([]byte, error) {
+	return json.Marshal(h.toJSON())
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (h *BlockHeader) UnmarshalJSON(data []byte) error {
+	var j blockHeaderJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	return h.fromJSON(&j)
+}
+
 // NewBlockHeader returns a new BlockHeader using the provided version, previous
 // block hash, merkle root hash, difficulty bits, and nonce used to generate the
 // block with defaults for the remaining fields.
@@ -114,15 +225,68 @@ func NewBlockHeader(version int32, prevHash, merkleRootHash *chainhash.Hash,
 // decoding block headers stored to disk, such as in a database, as opposed to
 // decoding from the wire.
 func readBlockHeader(r io.Reader, _ uint32, bh *BlockHeader) error {
-	return readElements(r, &bh.Version, &bh.PrevBlock, &bh.MerkleRoot,
-		(*uint32Time)(&bh.Timestamp), &bh.Bits, &bh.Nonce)
+	if err := readUint32(r, &bh.Version); err != nil {
+		return err
+	}
+
+	if err := readHash(r, &bh.PrevBlock); err != nil {
+		return err
+	}
+
+	if err := readHash(r, &bh.MerkleRoot); err != nil {
+		return err
+	}
+
+	var sec uint32
+	if err := readUint32(r, &sec); err != nil {
+		return err
+	}
+
+	bh.Timestamp = time.Unix(int64(sec), 0)
+
+	if err := readUint32(r, &bh.Bits); err != nil {
+		return err
+	}
+
+	return readUint32(r, &bh.Nonce)
 }
 
 // writeBlockHeader writes a bitcoin block header to w.  See Serialize for
 // encoding block headers to be stored to disk, such as in a database, as
 // opposed to encoding for the wire.
 func writeBlockHeader(w io.Writer, _ uint32, bh *BlockHeader) error {
-	sec := uint32(bh.Timestamp.Unix())
-	return writeElements(w, bh.Version, &bh.PrevBlock, &bh.MerkleRoot,
-		sec, bh.Bits, bh.Nonce)
+	if err := writeUint32(w, bh.Version); err != nil {
+		return err
+	}
+
+	if err := writeHash(w, &bh.PrevBlock); err != nil {
+		return err
+	}
+
+	if err := writeHash(w, &bh.MerkleRoot); err != nil {
+		return err
+	}
+
+	if err := writeUint32(w, uint32(bh.Timestamp.Unix())); err != nil {
+		return err
+	}
+
+	if err := writeUint32(w, bh.Bits); err != nil {
+		return err
+	}
+
+	return writeUint32(w, bh.Nonce)
+}
+
+// appendBlockHeader appends the encoding of a bitcoin block header to dst,
+// growing it to fit the header at most once.
+func appendBlockHeader(dst []byte, bh *BlockHeader) []byte {
+	dst = growExact(dst, blockHeaderLen)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(bh.Version))
+	dst = append(dst, bh.PrevBlock[:]...)
+	dst = append(dst, bh.MerkleRoot[:]...)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(bh.Timestamp.Unix()))
+	dst = binary.LittleEndian.AppendUint32(dst, bh.Bits)
+
+	return binary.LittleEndian.AppendUint32(dst, bh.Nonce)
 }
@@ -0,0 +1,458 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Codec reads and writes bitcoin messages for a single connection.  It carries
+// the settings which would otherwise have to be passed to every read and write
+// call or configured globally: the bitcoin network, the protocol version, the
+// message encoding, the excessive block size, the maximum payload length the
+// remote peer negotiated via its protoconf message, the set of external
+// handlers, the read and write interceptors and the observer.
+//
+// This allows a single process to talk to several networks with different
+// limits at the same time.  The package-level read and write functions are
+// thin wrappers over a default Codec which uses the package-wide limits set
+// via SetLimits and the handlers and interceptors installed via
+// SetExternalHandler, InterceptRead and InterceptWrite.
+//
+// All methods are safe for concurrent access.  The settings may be changed at
+// any time, for example to raise the protocol version once the version
+// handshake has completed, and take effect for subsequent reads and writes.
+type Codec struct {
+	mtx                  sync.RWMutex
+	bsvnet               BitcoinNet
+	pver                 uint32
+	enc                  MessageEncoding
+	excessiveBlockSize   uint64
+	maxRecvPayloadLength uint64
+	extended             bool
+	twoPass              bool
+	headerTimeout        time.Duration
+	payloadTimeout       time.Duration
+	writeTimeout         time.Duration
+	rawUnknown           bool
+	pooled               bool
+	handlers             map[string]ExternalHandler
+	readInterceptors     []*readInterceptor
+	writeInterceptors    []*writeInterceptor
+	observer             Observer
+}
+
+// codecParams houses a snapshot of the settings used for a single read or
+// write so that the settings of a Codec may change concurrently without
+// affecting messages which are already in flight.
+type codecParams struct {
+	bsvnet               BitcoinNet
+	pver                 uint32
+	enc                  MessageEncoding
+	excessiveBlockSize   uint64
+	maxRecvPayloadLength uint64
+	extended             bool
+	twoPass              bool
+	headerTimeout        time.Duration
+	payloadTimeout       time.Duration
+	writeTimeout         time.Duration
+	rawUnknown           bool
+	pooled               bool
+}
+
+// defaultCodec is the Codec used by the package-level read and write
+// functions.  Its network, protocol version and encoding are never used since
+// those functions provide them on every call.
+var defaultCodec = &Codec{
+	handlers: make(map[string]ExternalHandler),
+}
+
+// NewCodec returns a new Codec for the provided bitcoin network and protocol
+// version which uses the base encoding, the package-wide limits and no
+// external handlers.
+func NewCodec(bsvnet BitcoinNet, pver uint32) *Codec {
+	return &Codec{
+		bsvnet:   bsvnet,
+		pver:     pver,
+		enc:      BaseEncoding,
+		handlers: make(map[string]ExternalHandler),
+	}
+}
+
+// Net returns the bitcoin network the codec reads and writes messages for.
+func (c *Codec) Net() BitcoinNet {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.bsvnet
+}
+
+// ProtocolVersion returns the protocol version used to encode and decode
+// messages.
+func (c *Codec) ProtocolVersion() uint32 {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.pver
+}
+
+// SetProtocolVersion sets the protocol version used to encode and decode
+// messages.  This is typically lowered to the version negotiated with the
+// remote peer once its version message has been received.
+func (c *Codec) SetProtocolVersion(pver uint32) {
+	c.mtx.Lock()
+	c.pver = pver
+	c.mtx.Unlock()
+}
+
+// Encoding returns the message encoding used to encode and decode messages.
+func (c *Codec) Encoding() MessageEncoding {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.enc
+}
+
+// SetEncoding sets the message encoding used to encode and decode messages.
+func (c *Codec) SetEncoding(enc MessageEncoding) {
+	c.mtx.Lock()
+	c.enc = enc
+	c.mtx.Unlock()
+}
+
+// ExcessiveBlockSize returns the excessive block size in effect for the codec.
+// This is the value set via Codec.SetLimits or, when none has been set, the
+// package-wide value set via SetLimits.
+func (c *Codec) ExcessiveBlockSize() uint64 {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.params().maxBlockPayload()
+}
+
+// SetLimits adjusts the message limits of the codec based on max block size
+// configuration.  It is the per-connection counterpart of the package-level
+// SetLimits.  A value of zero makes the codec follow the package-wide value
+// again.
+//
+// The limits apply to the framing layer, that is the overall maximum payload
+// and the maximum payload of block-sized messages (block, tx, exttx,
+// merkleblock), as well as to the sanity checks performed by the message
+// decoders themselves, such as the maximum number of transactions in a block
+// and the maximum script length.
+func (c *Codec) SetLimits(excessiveBlockSize uint64) {
+	c.mtx.Lock()
+	c.excessiveBlockSize = excessiveBlockSize
+	c.mtx.Unlock()
+}
+
+// MaxRecvPayloadLength returns the maximum payload length the remote peer
+// accepts, or zero when none has been negotiated.
+func (c *Codec) MaxRecvPayloadLength() uint64 {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.maxRecvPayloadLength
+}
+
+// SetMaxRecvPayloadLength sets the maximum payload length the remote peer
+// advertised in its protoconf message.  Writing any message other than a
+// block-sized message (block, tx, exttx, merkleblock) with a larger payload
+// is rejected since the peer would disconnect.  A value of zero disables the
+// check.
+func (c *Codec) SetMaxRecvPayloadLength(maxRecvPayloadLength uint64) {
+	c.mtx.Lock()
+	c.maxRecvPayloadLength = maxRecvPayloadLength
+	c.mtx.Unlock()
+}
+
+// ExtendedMessages returns whether every message is written using the
+// extended message header.
+func (c *Codec) ExtendedMessages() bool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.extended
+}
+
+// SetExtendedMessages sets whether every message is written using the
+// extended message header rather than only those with a payload of 4GB or
+// more.  This requires a protocol version of at least ExtMsgVersion and is
+// only useful with peers which advertised support for extended messages.
+// Messages are read in either header form regardless of this setting.
+func (c *Codec) SetExtendedMessages(extended bool) {
+	c.mtx.Lock()
+	c.extended = extended
+	c.mtx.Unlock()
+}
+
+// TwoPassChecksum returns whether WriteStreaming encodes messages which carry
+// a checksum twice rather than buffering their payload.
+func (c *Codec) TwoPassChecksum() bool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.twoPass
+}
+
+// SetTwoPassChecksum sets whether WriteStreaming encodes messages which carry
+// a checksum twice, hashing the payload in the first pass and writing it in
+// the second, rather than buffering their payload.  This trades CPU time for
+// memory which stays bounded regardless of the size of the message.
+func (c *Codec) SetTwoPassChecksum(twoPass bool) {
+	c.mtx.Lock()
+	c.twoPass = twoPass
+	c.mtx.Unlock()
+}
+
+// UnknownPassthrough returns whether messages with unrecognized commands are
+// returned as a *MsgRaw rather than an error.
+func (c *Codec) UnknownPassthrough() bool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.rawUnknown
+}
+
+// SetUnknownPassthrough sets whether messages with commands which are not
+// registered are returned as a *MsgRaw carrying the command and payload rather
+// than discarded with an error.  A checksum mismatch on such a message is
+// reported through MsgRaw.ChecksumVerified instead of an error.  Messages with
+// recognized commands are unaffected.
+func (c *Codec) SetUnknownPassthrough(passthrough bool) {
+	c.mtx.Lock()
+	c.rawUnknown = passthrough
+	c.mtx.Unlock()
+}
+
+// MessagePooling returns whether messages are read into messages taken from a
+// pool rather than newly allocated ones.
+func (c *Codec) MessagePooling() bool {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.pooled
+}
+
+// SetMessagePooling sets whether messages are read into messages taken from a
+// pool, which Release returns them to, rather than newly allocated ones.
+// Along with the message, the pool keeps the memory its lists such as
+// inventory vectors, headers and transaction inputs and outputs allocated, so
+// steady-state reads of such messages allocate little or nothing.
+//
+// Messages read with pooling enabled may be released once the caller is done
+// with them, but need not be: messages which are never released are simply
+// garbage collected.
+func (c *Codec) SetMessagePooling(pooled bool) {
+	c.mtx.Lock()
+	c.pooled = pooled
+	c.mtx.Unlock()
+}
+
+// ReadTimeouts returns the maximum time ReadContext waits for the message
+// header and for the payload.  Zero means no timeout.
+func (c *Codec) ReadTimeouts() (time.Duration, time.Duration) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.headerTimeout, c.payloadTimeout
+}
+
+// SetReadTimeouts sets the maximum time ReadContext waits for the message
+// header and, once the header has been read, for the payload.  The header
+// timeout bounds how long an idle connection may stay silent, while the
+// payload timeout bounds how long a peer may take to deliver a message it has
+// announced, which stops a slow peer from holding a large payload allocation
+// open indefinitely.  A value of zero disables the respective timeout.
+func (c *Codec) SetReadTimeouts(header, payload time.Duration) {
+	c.mtx.Lock()
+	c.headerTimeout = header
+	c.payloadTimeout = payload
+	c.mtx.Unlock()
+}
+
+// WriteTimeout returns the maximum time WriteContext takes to write a message.
+// Zero means no timeout.
+func (c *Codec) WriteTimeout() time.Duration {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.writeTimeout
+}
+
+// SetWriteTimeout sets the maximum time WriteContext takes to write a
+// message.  A value of zero disables the timeout.
+func (c *Codec) SetWriteTimeout(timeout time.Duration) {
+	c.mtx.Lock()
+	c.writeTimeout = timeout
+	c.mtx.Unlock()
+}
+
+// SetExternalHandler installs handler to read the payload of messages with
+// the provided command instead of the default decoding.  It is the
+// per-connection counterpart of the package-level SetExternalHandler.  A nil
+// handler removes any existing handler for cmd.
+//
+// Deprecated: Use InterceptRead, which composes with other interceptors and
+// may decline messages to fall through to the default decoding.
+func (c *Codec) SetExternalHandler(cmd string, handler ExternalHandler) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if handler == nil {
+		delete(c.handlers, cmd)
+		return
+	}
+
+	c.handlers[cmd] = handler
+}
+
+// Read reads, validates, and parses the next bitcoin Message from r using the
+// settings of the codec.  It returns the number of bytes read in addition to
+// the parsed Message and raw bytes which comprise the message.  See
+// ReadMessageWithEncodingN for details.
+func (c *Codec) Read(r io.Reader) (int, Message, []byte, error) {
+	return c.readMessage(r, c.snapshot())
+}
+
+// ReadStreaming reads, validates, and parses the next bitcoin Message from r
+// using the settings of the codec without allocating a payload buffer for the
+// entire message body.  See ReadMessageStreamingN for details.
+func (c *Codec) ReadStreaming(r io.Reader) (int, Message, error) {
+	return c.readMessageStreaming(r, c.snapshot())
+}
+
+// Write writes msg to w including the necessary header information using the
+// settings of the codec and returns the number of bytes written.  See
+// WriteMessageWithEncodingN for details.
+func (c *Codec) Write(w io.Writer, msg Message) (int, error) {
+	return c.writeMessage(w, msg, c.snapshot())
+}
+
+// WriteStreaming writes msg to w including the necessary header information
+// using the settings of the codec and returns the number of bytes written.
+// Messages using the extended header are always streamed to w without
+// buffering their payload, while messages which carry a checksum are only
+// streamed when two-pass mode is enabled via SetTwoPassChecksum and are
+// otherwise written the same way as Write.  See WriteMessageStreamingN for
+// details.
+func (c *Codec) WriteStreaming(w io.Writer, msg Message) (int, error) {
+	return c.writeMessageStreaming(w, msg, c.snapshot())
+}
+
+// snapshot returns the current settings of the codec.
+func (c *Codec) snapshot() codecParams {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.params()
+}
+
+// params returns the current settings of the codec.  The caller must hold the
+// codec mutex.
+func (c *Codec) params() codecParams {
+	return codecParams{
+		bsvnet:               c.bsvnet,
+		pver:                 c.pver,
+		enc:                  c.enc,
+		excessiveBlockSize:   c.excessiveBlockSize,
+		maxRecvPayloadLength: c.maxRecvPayloadLength,
+		extended:             c.extended,
+		twoPass:              c.twoPass,
+		headerTimeout:        c.headerTimeout,
+		payloadTimeout:       c.payloadTimeout,
+		writeTimeout:         c.writeTimeout,
+		rawUnknown:           c.rawUnknown,
+		pooled:               c.pooled,
+	}
+}
+
+// handler returns the external handler installed for cmd, if any.
+func (c *Codec) handler(cmd string) ExternalHandler {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.handlers[cmd]
+}
+
+// limits returns the limits messages are decoded with.
+func (p codecParams) limits() decodeLimits {
+	return decodeLimits{excessiveBlockSize: p.excessiveBlockSize}
+}
+
+// maxBlockPayload returns the maximum bytes a block message can be.
+func (p codecParams) maxBlockPayload() uint64 {
+	return p.limits().maxBlockPayload()
+}
+
+// maxMessagePayload returns the maximum bytes any message can be.
+func (p codecParams) maxMessagePayload() uint64 {
+	return p.limits().maxMessagePayload()
+}
+
+// makeEmptyMessage creates a message of the appropriate concrete type based on
+// the command, falling back to a *MsgRaw for unrecognized commands when
+// unknown command passthrough is enabled.  Messages are taken from their pool
+// when message pooling is enabled.
+func (p codecParams) makeEmptyMessage(command string) (Message, error) {
+	var (
+		msg Message
+		err error
+	)
+
+	if p.pooled {
+		msg, err = acquireMessage(command)
+	} else {
+		msg, err = makeEmptyMessage(command)
+	}
+
+	if err != nil && p.rawUnknown {
+		return &MsgRaw{Cmd: command}, nil
+	}
+
+	return msg, err
+}
+
+// isBlockSized returns whether the maximum payload of msg is derived from the
+// excessive block size rather than being fixed by the protocol.
+func isBlockSized(msg Message) bool {
+	switch msg.(type) {
+	case *MsgBlock, *MsgTx, *MsgExtendedTx, *MsgMerkleBlock:
+		return true
+	}
+
+	return false
+}
+
+// maxPayloadLength returns the maximum payload length of msg.  This is the
+// limit reported by the message itself, except that limits derived from the
+// excessive block size follow the limit of the codec when one is set.
+func (p codecParams) maxPayloadLength(msg Message) uint64 {
+	mpl := msg.MaxPayloadLength(p.pver)
+	if p.excessiveBlockSize == 0 {
+		return mpl
+	}
+
+	if isBlockSized(msg) {
+		return p.maxBlockPayload()
+	}
+
+	switch msg.(type) {
+	case *MsgRaw:
+		return p.maxMessagePayload()
+
+	case *MsgReject, *MsgCFCheckpt:
+		// Both are limited by the overall maximum message payload, apart
+		// from reject messages which are not valid at all before
+		// RejectVersion.
+		if mpl != 0 {
+			return p.maxMessagePayload()
+		}
+	}
+
+	return mpl
+}
@@ -0,0 +1,302 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// largeTx returns a transaction whose serialized size exceeds scriptLen.
+func largeTx(scriptLen int) *MsgTx {
+	tx := NewMsgTx(1)
+	tx.AddTxOut(NewTxOut(0, make([]byte, scriptLen)))
+
+	return tx
+}
+
+// TestCodecRoundTrip ensures messages written by a codec are read back by a
+// codec with the same settings using both read paths.
+func TestCodecRoundTrip(t *testing.T) {
+	codec := NewCodec(TestNet, ProtocolVersion)
+
+	assert.Equal(t, TestNet, codec.Net())
+	assert.Equal(t, ProtocolVersion, codec.ProtocolVersion())
+	assert.Equal(t, BaseEncoding, codec.Encoding())
+	assert.Equal(t, MaxBlockPayload(), codec.ExcessiveBlockSize())
+
+	var buf bytes.Buffer
+	n, err := codec.Write(&buf, &blockOne)
+	require.NoError(t, err)
+	assert.Equal(t, buf.Len(), n)
+
+	n, msg, payload, err := codec.Read(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, buf.Len(), n)
+	assert.Equal(t, &blockOne, msg)
+	assert.Equal(t, buf.Bytes()[MessageHeaderSize:], payload)
+
+	n, msg, err = codec.ReadStreaming(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, buf.Len(), n)
+	assert.Equal(t, &blockOne, msg)
+
+	// Messages from another network must be rejected.
+	_, _, _, err = NewCodec(MainNet, ProtocolVersion).Read(bytes.NewReader(buf.Bytes()))
+
+	var msgErr *MessageError
+	require.ErrorAs(t, err, &msgErr)
+}
+
+// TestCodecSettings ensures changes to the codec settings take effect.
+func TestCodecSettings(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+
+	codec.SetProtocolVersion(BIP0031Version)
+	assert.Equal(t, BIP0031Version, codec.ProtocolVersion())
+
+	codec.SetEncoding(LatestEncoding)
+	assert.Equal(t, LatestEncoding, codec.Encoding())
+
+	codec.SetLimits(1000000)
+	assert.Equal(t, uint64(1000000), codec.ExcessiveBlockSize())
+
+	codec.SetLimits(0)
+	assert.Equal(t, MaxBlockPayload(), codec.ExcessiveBlockSize())
+
+	codec.SetMaxRecvPayloadLength(uint64(DefaultMaxRecvPayloadLength))
+	assert.Equal(t, uint64(DefaultMaxRecvPayloadLength), codec.MaxRecvPayloadLength())
+
+	// The pong message is only valid after BIP0031Version, so writing one
+	// proves the protocol version set above is used.
+	_, err := codec.Write(io.Discard, NewMsgPong(1))
+
+	var msgErr *MessageError
+	require.ErrorAs(t, err, &msgErr)
+
+	codec.SetProtocolVersion(ProtocolVersion)
+
+	_, err = codec.Write(io.Discard, NewMsgPong(1))
+	require.NoError(t, err)
+}
+
+// TestCodecLimits ensures each codec enforces its own excessive block size
+// independently of other codecs and the package-level functions.
+func TestCodecLimits(t *testing.T) {
+	tx := largeTx(1500000)
+
+	small := NewCodec(MainNet, ProtocolVersion)
+	small.SetLimits(1000000)
+
+	large := NewCodec(MainNet, ProtocolVersion)
+
+	tests := []struct {
+		name    string
+		codec   *Codec
+		wantErr bool
+	}{
+		{"codec limit below tx size", small, true},
+		{"codec following package limit", large, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			_, err := tt.codec.Write(&buf, tx)
+			if tt.wantErr {
+				var msgErr *MessageError
+				require.ErrorAs(t, err, &msgErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+
+	// A transaction written under the package limit must be rejected when
+	// read by the codec with the smaller limit, while the package-level
+	// functions continue to accept it.
+	var buf bytes.Buffer
+	_, err := WriteMessageN(&buf, tx, ProtocolVersion, MainNet)
+	require.NoError(t, err)
+
+	_, _, _, err = small.Read(bytes.NewReader(buf.Bytes()))
+
+	var msgErr *MessageError
+	require.ErrorAs(t, err, &msgErr)
+
+	_, _, err = small.ReadStreaming(bytes.NewReader(buf.Bytes()))
+	require.ErrorAs(t, err, &msgErr)
+
+	_, msg, _, err := ReadMessageN(bytes.NewReader(buf.Bytes()), ProtocolVersion, MainNet)
+	require.NoError(t, err)
+	assert.Equal(t, tx, msg)
+}
+
+// TestCodecLimitsDecode ensures the sanity checks of the message decoders
+// follow the limit of the codec rather than the package-wide limit.
+func TestCodecLimitsDecode(t *testing.T) {
+	SetLimits(1000000)
+	t.Cleanup(func() { SetLimits(DefaultExcessiveBlockSize) })
+
+	// The script is larger than the package-wide maximum message payload.
+	tx := largeTx(3000000)
+	require.Greater(t, uint64(len(tx.TxOut[0].PkScript)), maxMessagePayload())
+
+	block := NewMsgBlock(&blockOne.Header)
+	require.NoError(t, block.AddTransaction(tx))
+
+	codec := NewCodec(MainNet, ProtocolVersion)
+	codec.SetLimits(8000000)
+
+	var buf bytes.Buffer
+	_, err := codec.Write(&buf, block)
+	require.NoError(t, err)
+
+	_, msg, _, err := codec.Read(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, block, msg)
+
+	_, msg, err = codec.ReadStreaming(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, block, msg)
+
+	_, msg, _, err = codec.DecodeBytes(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, block, msg)
+
+	_, frame, err := codec.ReadFrameStreaming(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	msg, err = frame.Decode(ProtocolVersion, BaseEncoding)
+	require.NoError(t, err)
+	assert.Equal(t, block, msg)
+
+	// The package-level functions are bounded by the package-wide limit.
+	_, _, _, err = ReadMessageN(bytes.NewReader(buf.Bytes()), ProtocolVersion, MainNet)
+
+	var msgErr *MessageError
+	require.ErrorAs(t, err, &msgErr)
+}
+
+// TestCodecMaxRecvPayloadLength ensures messages larger than the payload
+// length negotiated by the peer are not written, except for block-sized
+// messages.
+func TestCodecMaxRecvPayloadLength(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	codec.SetMaxRecvPayloadLength(1024)
+
+	tests := []struct {
+		name    string
+		msg     Message
+		wantErr bool
+	}{
+		{"small reject", NewMsgReject(CmdTx, RejectInvalid, "bad"), false},
+		{"large reject", NewMsgReject(CmdTx, RejectInvalid, strings.Repeat("x", 2048)), true},
+		{"large tx", largeTx(2048), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := codec.Write(io.Discard, tt.msg)
+			if tt.wantErr {
+				var msgErr *MessageError
+				require.ErrorAs(t, err, &msgErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+// TestCodecExternalHandler ensures external handlers installed on a codec
+// only apply to that codec.
+func TestCodecExternalHandler(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := WriteMessageN(&buf, &blockOne, ProtocolVersion, MainNet)
+	require.NoError(t, err)
+
+	handled := &MsgBlock{}
+	codec := NewCodec(MainNet, ProtocolVersion)
+	codec.SetExternalHandler(CmdBlock, func(r io.Reader, length uint64, hdrBytes int) (int, Message, []byte, error) {
+		payload := make([]byte, length)
+		n, err := io.ReadFull(r, payload)
+
+		return hdrBytes + n, handled, payload, err
+	})
+
+	_, msg, _, err := codec.Read(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Same(t, handled, msg)
+
+	_, msg, err = codec.ReadStreaming(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Same(t, handled, msg)
+
+	// Neither the package-level functions nor other codecs use it.
+	_, msg, _, err = ReadMessageN(bytes.NewReader(buf.Bytes()), ProtocolVersion, MainNet)
+	require.NoError(t, err)
+	assert.NotSame(t, handled, msg)
+
+	_, msg, _, err = NewCodec(MainNet, ProtocolVersion).Read(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.NotSame(t, handled, msg)
+
+	// Removing the handler restores the default decoding.
+	codec.SetExternalHandler(CmdBlock, nil)
+
+	_, msg, _, err = codec.Read(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.NotSame(t, handled, msg)
+}
+
+// TestCodecConcurrentAccess ensures codec settings and the package-level
+// configuration may be changed while messages are being read and written.
+// It is primarily useful when run with the race detector.
+func TestCodecConcurrentAccess(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+
+	var buf bytes.Buffer
+	_, err := codec.Write(&buf, &blockOne)
+	require.NoError(t, err)
+
+	encoded := buf.Bytes()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				_, _, _, _ = codec.Read(bytes.NewReader(encoded))
+				_, _, _, _ = ReadMessageN(bytes.NewReader(encoded), ProtocolVersion, MainNet)
+				_, _ = codec.Write(io.Discard, &blockOne)
+			}
+		}()
+	}
+
+	for j := 0; j < 50; j++ {
+		codec.SetLimits(fixedExcessiveBlockSize)
+		codec.SetProtocolVersion(ProtocolVersion)
+		codec.SetExternalHandler(CmdPing, nil)
+		SetLimits(fixedExcessiveBlockSize)
+		SetExternalHandler(CmdPing, nil)
+	}
+
+	wg.Wait()
+}
@@ -201,68 +201,33 @@ type int64Time time.Time
 
 // readElement reads the next sequence of bytes from r using little endian
 // depending on the concrete type of element pointed to.
+//
+// Since it boxes every element in an interface and goes through a type switch,
+// the message implementations use the typed primitives such as readUint32 and
+// readHash instead.  It is kept as a compatibility shim delegating to them.
 func readElement(r io.Reader, element interface{}) error {
-	// Attempt to read the element based on the concrete type via fast
-	// type assertions first.
+	// Attempt to read the element based on the concrete type via the typed
+	// primitives first.
 	switch e := element.(type) {
 	case *int32:
-		rv, err := binarySerializer.Uint32(r, littleEndian)
-		if err != nil {
-			return err
-		}
-
-		*e = int32(rv)
-
-		return nil
+		return readUint32(r, e)
 
 	case *uint32:
-		rv, err := binarySerializer.Uint32(r, littleEndian)
-		if err != nil {
-			return err
-		}
-
-		*e = rv
-
-		return nil
+		return readUint32(r, e)
 
 	case *int64:
-		rv, err := binarySerializer.Uint64(r, littleEndian)
-		if err != nil {
-			return err
-		}
-
-		*e = int64(rv)
-
-		return nil
+		return readUint64(r, e)
 
 	case *uint64:
-		rv, err := binarySerializer.Uint64(r, littleEndian)
-		if err != nil {
-			return err
-		}
-
-		*e = rv
-
-		return nil
+		return readUint64(r, e)
 
 	case *bool:
-		rv, err := binarySerializer.Uint8(r)
-		if err != nil {
-			return err
-		}
-
-		if rv == 0x00 {
-			*e = false
-		} else {
-			*e = true
-		}
-
-		return nil
+		return readBool(r, e)
 
 	// Unix timestamp encoded as a uint32.
 	case *uint32Time:
-		rv, err := binarySerializer.Uint32(r, binary.LittleEndian)
-		if err != nil {
+		var rv uint32
+		if err := readUint32(r, &rv); err != nil {
 			return err
 		}
 
@@ -272,99 +237,44 @@ func readElement(r io.Reader, element interface{}) error {
 
 	// Unix timestamp encoded as an int64.
 	case *int64Time:
-		rv, err := binarySerializer.Uint64(r, binary.LittleEndian)
-		if err != nil {
+		var rv int64
+		if err := readUint64(r, &rv); err != nil {
 			return err
 		}
 
-		*e = int64Time(time.Unix(int64(rv), 0))
+		*e = int64Time(time.Unix(rv, 0))
 
 		return nil
 
 	// Message header checksum.
 	case *[4]byte:
-		_, err := io.ReadFull(r, e[:])
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return readFixed(r, e[:])
 
 	// Message header command.
 	case *[CommandSize]uint8:
-		_, err := io.ReadFull(r, e[:])
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return readFixed(r, e[:])
 
 	// IP address.
 	case *[16]byte:
-		_, err := io.ReadFull(r, e[:])
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return readFixed(r, e[:])
 
 	case *chainhash.Hash:
-		_, err := io.ReadFull(r, e[:])
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return readHash(r, e)
 
 	case *ServiceFlag:
-		rv, err := binarySerializer.Uint64(r, littleEndian)
-		if err != nil {
-			return err
-		}
-
-		*e = ServiceFlag(rv)
-
-		return nil
+		return readUint64(r, e)
 
 	case *InvType:
-		rv, err := binarySerializer.Uint32(r, littleEndian)
-		if err != nil {
-			return err
-		}
-
-		*e = InvType(rv)
-
-		return nil
+		return readUint32(r, e)
 
 	case *BitcoinNet:
-		rv, err := binarySerializer.Uint32(r, littleEndian)
-		if err != nil {
-			return err
-		}
-
-		*e = BitcoinNet(rv)
-
-		return nil
+		return readUint32(r, e)
 
 	case *BloomUpdateType:
-		rv, err := binarySerializer.Uint8(r)
-		if err != nil {
-			return err
-		}
-
-		*e = BloomUpdateType(rv)
-
-		return nil
+		return readUint8(r, e)
 
 	case *RejectCode:
-		rv, err := binarySerializer.Uint8(r)
-		if err != nil {
-			return err
-		}
-
-		*e = RejectCode(rv)
-
-		return nil
+		return readUint8(r, e)
 	}
 
 	// Fall back to the slower binary.Read if a fast path was not available
@@ -386,130 +296,58 @@ func readElements(r io.Reader, elements ...interface{}) error {
 }
 
 // writeElement writes the little endian representation of an element to w.
+//
+// Like readElement, it is kept as a compatibility shim delegating to the typed
+// primitives such as writeUint32 and writeHash, which the message
+// implementations use directly.
 func writeElement(w io.Writer, element interface{}) error {
-	// Attempt to write the element based on the concrete type via fast
-	// type assertions first.
+	// Attempt to write the element based on the concrete type via the typed
+	// primitives first.
 	switch e := element.(type) {
 	case int32:
-		err := binarySerializer.PutUint32(w, littleEndian, uint32(e))
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeUint32(w, e)
 
 	case uint32:
-		err := binarySerializer.PutUint32(w, littleEndian, e)
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeUint32(w, e)
 
 	case int64:
-		err := binarySerializer.PutUint64(w, littleEndian, uint64(e))
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeUint64(w, e)
 
 	case uint64:
-		err := binarySerializer.PutUint64(w, littleEndian, e)
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeUint64(w, e)
 
 	case bool:
-		var err error
-		if e {
-			err = binarySerializer.PutUint8(w, 0x01)
-		} else {
-			err = binarySerializer.PutUint8(w, 0x00)
-		}
-
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeBool(w, e)
 
 	// Message header checksum.
 	case [4]byte:
-		_, err := w.Write(e[:])
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeFixed(w, e[:])
 
 	// Message header command.
 	case [CommandSize]uint8:
-		_, err := w.Write(e[:])
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeFixed(w, e[:])
 
 	// IP address.
 	case [16]byte:
-		_, err := w.Write(e[:])
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeFixed(w, e[:])
 
 	case *chainhash.Hash:
-		_, err := w.Write(e[:])
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeHash(w, e)
 
 	case ServiceFlag:
-		err := binarySerializer.PutUint64(w, littleEndian, uint64(e))
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeUint64(w, e)
 
 	case InvType:
-		err := binarySerializer.PutUint32(w, littleEndian, uint32(e))
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeUint32(w, e)
 
 	case BitcoinNet:
-		err := binarySerializer.PutUint32(w, littleEndian, uint32(e))
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeUint32(w, e)
 
 	case BloomUpdateType:
-		err := binarySerializer.PutUint8(w, uint8(e))
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeUint8(w, e)
 
 	case RejectCode:
-		err := binarySerializer.PutUint8(w, uint8(e))
-		if err != nil {
-			return err
-		}
-
-		return nil
+		return writeUint8(w, e)
 	}
 
 	// Fall back to the slower binary.Write if a fast path was not available
@@ -532,7 +370,9 @@ func writeElements(w io.Writer, elements ...interface{}) error {
 
 // ReadVarInt reads a variable length integer from r and returns it as an uint64.
 func ReadVarInt(r io.Reader, _ uint32) (uint64, error) {
-	discriminant, err := binarySerializer.Uint8(r)
+	var discriminant uint8
+
+	err := readUint8(r, &discriminant)
 	if err != nil {
 		return 0, err
 	}
@@ -542,7 +382,7 @@ func ReadVarInt(r io.Reader, _ uint32) (uint64, error) {
 	switch discriminant {
 	case 0xff:
 		var sv uint64
-		sv, err = binarySerializer.Uint64(r, littleEndian)
+		err = readUint64(r, &sv)
 		if err != nil {
 			return 0, err
 		}
@@ -553,7 +393,7 @@ func ReadVarInt(r io.Reader, _ uint32) (uint64, error) {
 		// encoded using fewer bytes.
 		minVal := uint64(0x100000000)
 		if rv < minVal {
-			return 0, messageError("ReadVarInt", fmt.Sprintf(
+			return 0, messageError("ReadVarInt", ErrNonCanonicalVarInt, fmt.Sprintf(
 				errNonCanonicalVarInt, rv, discriminant, minVal,
 			))
 		}
@@ -561,7 +401,7 @@ func ReadVarInt(r io.Reader, _ uint32) (uint64, error) {
 	case 0xfe:
 		var sv uint32
 
-		sv, err = binarySerializer.Uint32(r, littleEndian)
+		err = readUint32(r, &sv)
 		if err != nil {
 			return 0, err
 		}
@@ -572,7 +412,7 @@ func ReadVarInt(r io.Reader, _ uint32) (uint64, error) {
 		// encoded using fewer bytes.
 		minVal := uint64(0x10000)
 		if rv < minVal {
-			return 0, messageError("ReadVarInt", fmt.Sprintf(
+			return 0, messageError("ReadVarInt", ErrNonCanonicalVarInt, fmt.Sprintf(
 				errNonCanonicalVarInt, rv, discriminant, minVal,
 			))
 		}
@@ -580,7 +420,7 @@ func ReadVarInt(r io.Reader, _ uint32) (uint64, error) {
 	case 0xfd:
 		var sv uint16
 
-		sv, err = binarySerializer.Uint16(r, littleEndian)
+		err = readUint16(r, &sv)
 		if err != nil {
 			return 0, err
 		}
@@ -591,7 +431,7 @@ func ReadVarInt(r io.Reader, _ uint32) (uint64, error) {
 		// encoded using fewer bytes.
 		minVal := uint64(0xfd)
 		if rv < minVal {
-			return 0, messageError("ReadVarInt", fmt.Sprintf(
+			return 0, messageError("ReadVarInt", ErrNonCanonicalVarInt, fmt.Sprintf(
 				errNonCanonicalVarInt, rv, discriminant, minVal,
 			))
 		}
@@ -607,33 +447,33 @@ func ReadVarInt(r io.Reader, _ uint32) (uint64, error) {
 // on its value.
 func WriteVarInt(w io.Writer, _ uint32, val uint64) error {
 	if val < 0xfd {
-		return binarySerializer.PutUint8(w, uint8(val))
+		return writeUint8(w, uint8(val))
 	}
 
 	if val <= math.MaxUint16 {
-		err := binarySerializer.PutUint8(w, 0xfd)
+		err := writeUint8(w, uint8(0xfd))
 		if err != nil {
 			return err
 		}
 
-		return binarySerializer.PutUint16(w, littleEndian, uint16(val))
+		return writeUint16(w, uint16(val))
 	}
 
 	if val <= math.MaxUint32 {
-		err := binarySerializer.PutUint8(w, 0xfe)
+		err := writeUint8(w, uint8(0xfe))
 		if err != nil {
 			return err
 		}
 
-		return binarySerializer.PutUint32(w, littleEndian, uint32(val))
+		return writeUint32(w, uint32(val))
 	}
 
-	err := binarySerializer.PutUint8(w, 0xff)
+	err := writeUint8(w, uint8(0xff))
 	if err != nil {
 		return err
 	}
 
-	return binarySerializer.PutUint64(w, littleEndian, val)
+	return writeUint64(w, val)
 }
 
 // VarIntSerializeSize returns the number of bytes it would take to serialize
@@ -674,11 +514,11 @@ func ReadVarString(r io.Reader, pver uint32) (string, error) {
 	// Prevent variable length strings that are larger than the maximum
 	// message size.  It would be possible to cause memory exhaustion and
 	//  panic without a sane upper bound on this count.
-	if count > maxMessagePayload() {
+	if count > limitsOf(r).maxMessagePayload() {
 		str := fmt.Sprintf("variable length string is too long "+
-			"[count %d, max %d]", count, maxMessagePayload())
+			"[count %d, max %d]", count, limitsOf(r).maxMessagePayload())
 
-		return "", messageError("ReadVarString", str)
+		return "", messageError("ReadVarString", ErrElementTooLarge, str)
 	}
 
 	buf := make([]byte, count)
@@ -727,7 +567,7 @@ func ReadVarBytes(r io.Reader, pver uint32, maxAllowed uint64,
 		str := fmt.Sprintf("%s is larger than the max allowed size "+
 			"[count %d, max %d]", fieldName, count, maxAllowed)
 
-		return nil, messageError("ReadVarBytes", str)
+		return nil, messageError("ReadVarBytes", ErrElementTooLarge, str)
 	}
 
 	b := make([]byte, count)
@@ -0,0 +1,210 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// decodeErrorWindow is the number of payload bytes on either side of the
+// failure offset which are captured in the window of a DecodeError.
+const decodeErrorWindow = 16
+
+// DecodeError describes a failure to decode the payload of a message read by
+// the read functions of this package.  It locates the failure within the
+// payload so fuzz findings and malformed messages seen on the network can be
+// traced to the offending bytes.
+//
+// The underlying error, such as a *MessageError or io.ErrUnexpectedEOF, is
+// available through errors.Is and errors.As, so the kind and severity of the
+// failure are unaffected.
+type DecodeError struct {
+	// Command is the command of the message being decoded.
+	Command string
+
+	// Path is the path of the field being decoded when decoding failed,
+	// for example Transactions[9999].TxOut[2].PkScript.  It is empty when
+	// the message does not record field paths.
+	Path string
+
+	// Offset is the number of payload bytes consumed when decoding
+	// failed, so the offending bytes end at, or start at, Offset.
+	Offset int
+
+	// Window holds the payload bytes surrounding Offset.  It is nil when
+	// the payload was decoded from a stream rather than from memory.
+	Window []byte
+
+	// WindowOffset is the offset of the first byte of Window within the
+	// payload.
+	WindowOffset int
+
+	// Err is the error which caused decoding to fail.
+	Err error
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *DecodeError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("decoding %s payload at offset %d: %v", e.Command,
+			e.Offset, e.Err)
+	}
+
+	return fmt.Sprintf("decoding %s payload at offset %d (%s): %v", e.Command,
+		e.Offset, e.Path, e.Err)
+}
+
+// Unwrap returns the error which caused decoding to fail.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// HexWindow returns Window hex encoded with a '|' marking Offset, or an empty
+// string when there is no window.
+func (e *DecodeError) HexWindow() string {
+	if e.Window == nil {
+		return ""
+	}
+
+	split := e.Offset - e.WindowOffset
+
+	return hex.EncodeToString(e.Window[:split]) + "|" +
+		hex.EncodeToString(e.Window[split:])
+}
+
+// newDecodeError returns a DecodeError for err, which occurred offset bytes
+// into the payload of a message with the provided command.  payload may be nil
+// when it is not held in memory.
+func newDecodeError(command string, err error, payload []byte, offset int, path *fieldPath) *DecodeError {
+	e := &DecodeError{
+		Command: command,
+		Path:    path.String(),
+		Offset:  offset,
+		Err:     err,
+	}
+
+	if payload != nil {
+		start := max(offset-decodeErrorWindow, 0)
+		end := min(offset+decodeErrorWindow, len(payload))
+
+		e.Window = payload[start:end:end]
+		e.WindowOffset = start
+	}
+
+	return e
+}
+
+// fieldRecorder is implemented by readers which record the path of the field
+// being decoded when decoding fails.
+type fieldRecorder interface {
+	recordField(name string)
+}
+
+// fieldPath records the path of a field from the innermost field outwards.
+// It implements the fieldRecorder interface.
+type fieldPath struct {
+	fields []string
+}
+
+// recordField adds name as the parent of the fields recorded so far.  This is
+// part of the fieldRecorder interface implementation.
+func (p *fieldPath) recordField(name string) {
+	p.fields = append(p.fields, name)
+}
+
+// String returns the recorded path with its fields separated by dots.
+func (p *fieldPath) String() string {
+	var sb strings.Builder
+
+	for i := len(p.fields) - 1; i >= 0; i-- {
+		sb.WriteString(p.fields[i])
+
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+	}
+
+	return sb.String()
+}
+
+// traceField records name as the field being decoded when r records field
+// paths and returns err unchanged.  Decoders call it as they return an error,
+// so the path is built while unwinding and successful decoding is unaffected.
+func traceField(r io.Reader, err error, name string) error {
+	if fr, ok := r.(fieldRecorder); ok {
+		fr.recordField(name)
+	}
+
+	return err
+}
+
+// traceItem is like traceField for the element at index i of a list field.
+func traceItem(r io.Reader, err error, name string, i uint64) error {
+	if fr, ok := r.(fieldRecorder); ok {
+		fr.recordField(name + "[" + strconv.FormatUint(i, 10) + "]")
+	}
+
+	return err
+}
+
+// tracedBuffer is a bytes.Buffer which records field paths and carries the
+// limits and pooling of the codec decoding from it.
+type tracedBuffer struct {
+	*bytes.Buffer
+	fieldPath
+	decodeLimits
+	recycling
+}
+
+// tracedReader is an io.Reader which records field paths and carries the
+// limits and pooling of the codec decoding from it.
+type tracedReader struct {
+	io.Reader
+	fieldPath
+	decodeLimits
+	recycling
+}
+
+// decodePayload decodes the in-memory payload into msg with the settings of p.
+// Failures are returned as a *DecodeError locating the failure within payload.
+func decodePayload(msg Message, payload []byte, p codecParams) error {
+	_, err := decodePayloadRest(msg, payload, p)
+	return err
+}
+
+// decodePayloadRest is decodePayload which additionally returns the number of
+// bytes msg left unread at the end of payload.
+func decodePayloadRest(msg Message, payload []byte, p codecParams) (int, error) {
+	buf := bytes.NewBuffer(payload)
+	tb := &tracedBuffer{Buffer: buf, decodeLimits: p.limits(), recycling: recycling(p.pooled)}
+
+	// MsgVersion requires a *bytes.Buffer and has no nested fields.
+	var r io.Reader = tb
+	if _, isVersion := msg.(*MsgVersion); isVersion {
+		r = buf
+	}
+
+	if err := msg.Bsvdecode(r, p.pver, p.enc); err != nil {
+		return buf.Len(), newDecodeError(msg.Command(), err, payload, len(payload)-buf.Len(), &tb.fieldPath)
+	}
+
+	return buf.Len(), nil
+}
+
+// decodeAliasPayload decodes the in-memory payload into d, letting it alias
+// payload.  Failures are returned as a *DecodeError like decodePayload.
+func decodeAliasPayload(d aliasDecoder, command string, payload []byte, p codecParams) error {
+	pr := &sliceReader{buf: payload, decodeLimits: p.limits(), recycling: recycling(p.pooled)}
+	if err := d.decodeAlias(pr, p.pver); err != nil {
+		return newDecodeError(command, err, payload, pr.off, &pr.fieldPath)
+	}
+
+	return nil
+}
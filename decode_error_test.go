@@ -0,0 +1,138 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDecodeError ensures payload decoding failures are located by their
+// offset and field path on every read path.
+func TestDecodeError(t *testing.T) {
+	marker := []byte{0xde, 0xad, 0xbe, 0xef, 0x42}
+
+	tx := multiTx.Copy()
+	tx.TxOut[1].PkScript = marker
+
+	block := &MsgBlock{
+		Header:       blockOne.Header,
+		Transactions: []*MsgTx{multiTx, multiTx, tx},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, block.BsvEncode(&buf, ProtocolVersion, BaseEncoding))
+
+	// Encode the length of the marked script as a non-canonical varint.
+	payload := buf.Bytes()
+	idx := bytes.Index(payload, marker) - 1
+	require.Equal(t, byte(len(marker)), payload[idx])
+
+	payload = concat(payload[:idx], []byte{0xfd, byte(len(marker)), 0x00}, payload[idx+1:])
+	offset := idx + 3
+
+	codec := NewCodec(MainNet, ProtocolVersion)
+	frame := encodeFrame(t, codec, &fakeMessage{command: CmdBlock, payload: payload})
+
+	reads := map[string]func() error{
+		"Read": func() error {
+			_, _, _, err := codec.Read(bytes.NewReader(frame))
+			return err
+		},
+		"DecodeBytes": func() error {
+			_, _, _, err := codec.DecodeBytes(frame)
+			return err
+		},
+		"ReadFrame": func() error {
+			_, f, err := codec.ReadFrame(bytes.NewReader(frame))
+			require.NoError(t, err)
+
+			_, err = f.Decode(ProtocolVersion, BaseEncoding)
+
+			return err
+		},
+		"ReadStreaming": func() error {
+			_, _, err := codec.ReadStreaming(bytes.NewReader(frame))
+			return err
+		},
+	}
+
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			err := read()
+			require.ErrorIs(t, err, ErrNonCanonicalVarInt)
+
+			var decodeErr *DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, CmdBlock, decodeErr.Command)
+			assert.Equal(t, "Transactions[2].TxOut[1].PkScript", decodeErr.Path)
+			assert.Equal(t, offset, decodeErr.Offset)
+			assert.Contains(t, err.Error(), "Transactions[2].TxOut[1].PkScript")
+
+			if name == "ReadStreaming" {
+				assert.Nil(t, decodeErr.Window)
+				assert.Empty(t, decodeErr.HexWindow())
+
+				return
+			}
+
+			// The window is clamped to the end of the payload.
+			start := offset - decodeErrorWindow
+
+			assert.Equal(t, start, decodeErr.WindowOffset)
+			assert.Equal(t, payload[start:], decodeErr.Window)
+			assert.Equal(t, hex.EncodeToString(payload[start:offset])+"|"+
+				hex.EncodeToString(payload[offset:]), decodeErr.HexWindow())
+		})
+	}
+}
+
+// TestDecodeErrorPaths ensures the field paths of failures in other messages
+// are recorded.
+func TestDecodeErrorPaths(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+
+	inv := NewMsgInv()
+	require.NoError(t, inv.AddInvVect(NewInvVect(InvTypeTx, &chainhash.Hash{})))
+	require.NoError(t, inv.AddInvVect(NewInvVect(InvTypeTx, &chainhash.Hash{})))
+
+	var buf bytes.Buffer
+	require.NoError(t, inv.BsvEncode(&buf, ProtocolVersion, BaseEncoding))
+
+	tests := []struct {
+		name    string
+		command string
+		payload []byte
+		path    string
+		offset  int
+		err     error
+	}{
+		{"truncated inv", CmdInv, buf.Bytes()[:buf.Len()-1], "InvList[1]", buf.Len() - 1, io.ErrUnexpectedEOF},
+		{"too many inv", CmdInv, []byte{0xfe, 0x00, 0x00, 0x00, 0x01}, "InvList", 5, ErrTooManyItems},
+		{"headers with transactions", CmdHeaders, concat([]byte{0x01}, make([]byte, 80), []byte{0x01}), "Headers[0]", 82, ErrMalformedMessage},
+		{"version", CmdVersion, []byte{0x01}, "", 1, io.ErrUnexpectedEOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frame := encodeFrame(t, codec, &fakeMessage{command: tt.command, payload: tt.payload})
+
+			_, _, _, err := codec.Read(bytes.NewReader(frame))
+			require.ErrorIs(t, err, tt.err)
+
+			var decodeErr *DecodeError
+			require.ErrorAs(t, err, &decodeErr)
+			assert.Equal(t, tt.path, decodeErr.Path)
+			assert.Equal(t, tt.offset, decodeErr.Offset)
+			assert.Equal(t, tt.payload[max(tt.offset-decodeErrorWindow, 0):], decodeErr.Window)
+		})
+	}
+}
@@ -137,6 +137,123 @@ from a remote peer is:
 		// Log and handle the error
 	}
 
+# Per-Connection Codecs
+
+The package-level read and write functions share the limits configured via
+SetLimits and the interceptors added via InterceptRead and InterceptWrite.
+Applications which talk to several networks or peers with different limits
+should instead create a Codec per connection.  A Codec carries the bitcoin
+network, protocol version, encoding, excessive block size, the maximum payload
+length the peer negotiated via its protoconf message and its own interceptors:
+
+	codec := wire.NewCodec(wire.MainNet, wire.ProtocolVersion)
+	codec.SetLimits(excessiveBlockSize)
+
+	n, msg, rawPayload, err := codec.Read(conn)
+	if err != nil {
+		// Log and handle the error
+	}
+
+# Intercepting Messages
+
+Interceptors are middleware which wrap the reading and writing of messages,
+optionally only for some commands.  A read interceptor is passed the frame of
+each message, giving it access to the validated header and a bounded reader
+over the payload, and the next handler in the chain, which ends with the
+default decoding.  It may observe or transform the message, replace the
+decoding, for example to spill very large blocks to disk, or decline by
+calling next:
+
+	remove := codec.InterceptRead(func(f *wire.Frame, next wire.ReadHandler) (wire.Message, error) {
+		if f.Length < spillThreshold {
+			return next(f)
+		}
+		return spillBlock(f.Reader())
+	}, wire.CmdBlock)
+	defer remove()
+
+# Observing Traffic
+
+An Observer set via SetObserver is notified of every message a codec reads or
+writes with its command, direction, header and payload sizes, decode or encode
+duration and, for failures, the error and its kind.  Metrics is a ready-made
+Observer which aggregates these events in memory by command and can be
+published via expvar:
+
+	metrics := wire.NewMetrics()
+	metrics.Publish("wire")
+	codec.SetObserver(metrics)
+
+# Reusing Messages
+
+Every message implements Resetter, whose Reset method clears the message while
+keeping the capacity of its lists, and the inventory vectors, headers or
+transactions they point to.  Codecs with message pooling enabled via
+SetMessagePooling read into messages returned by Release and reuse what they
+kept, so a steady stream of messages of similar shape can be decoded with few
+allocations.  Any other decode allocates new elements, so elements a caller
+still holds are never overwritten:
+
+	codec.SetMessagePooling(true)
+	for {
+		_, msg, _, err := codec.Read(conn)
+		if err != nil {
+			// Log and handle the error
+		}
+		handle(msg)
+		wire.Release(msg)
+	}
+
+# Standard Library Interfaces
+
+Every message, as well as BlockHeader, OutPoint, NetAddress and InvVect,
+implements encoding.BinaryMarshaler, encoding.BinaryUnmarshaler, io.WriterTo
+and io.ReaderFrom using its bitcoin protocol encoding at the latest protocol
+version, without a message header.  This allows them to be stored in caches
+and key-value stores or sent over other transports as is:
+
+	b, err := tx.MarshalBinary()
+	if err != nil {
+		// Log and handle the error
+	}
+	err = cache.Set(tx.TxHash().String(), b)
+
+ReadFrom reads until EOF and, like UnmarshalBinary, rejects input holding
+anything after the encoding.
+
+# JSON
+
+Every message implements json.Marshaler and json.Unmarshaler with a stable
+representation following the conventions of bitcoind's RPC interface:
+transactions look like the output of decoderawtransaction, with hex scripts,
+byte-reversed hashes and amounts in BSV, while service flags, inventory types
+and reject codes are named.  Unmarshalling the JSON of a message reproduces its
+encoding exactly.  MarshalMessageJSON and UnmarshalMessageJSON additionally
+record the command so messages of any type can be stored as test fixtures or
+served by debugging tools:
+
+	b, err := wire.MarshalMessageJSON(msg)
+	// {"command":"ping","payload":{"nonce":42}}
+
+	msg, err = wire.UnmarshalMessageJSON(b)
+
+# Recovering From Corrupt Streams
+
+After a malformed frame the read functions leave the reader at an arbitrary
+offset, so peers are expected to disconnect.  Lenient consumers such as
+capture-file tooling can instead use a ResyncReader, which scans forward for the
+next plausible header on the network of its codec and reports how many bytes it
+skipped:
+
+	rr := wire.NewResyncReader(file, codec)
+	for {
+		skipped, n, msg, rawPayload, err := rr.ReadMessage()
+		if err == io.EOF {
+			break
+		}
+		// Log skipped bytes and handle the message or error
+	}
+
 # Errors
 
 Errors returned by this package are either the raw errors provided by underlying
@@ -145,6 +262,25 @@ io.ErrShortWrite, or of type wire.MessageError.  This allows the caller to
 differentiate between general IO errors and malformed messages through type
 assertions.
 
+Every MessageError has a Kind, such as ErrChecksumMismatch, ErrWrongNetwork or
+ErrTooManyItems, which can be tested with errors.Is even when the error has been
+wrapped.  Each kind carries a suggested misbehaviour severity, available through
+ErrorSeverity, which a node may use to decide whether to drop the message,
+disconnect or ban the peer:
+
+	_, msg, _, err := codec.Read(conn)
+	if errors.Is(err, wire.ErrUnknownCommand) {
+		// Ignore the message.
+	} else if wire.ErrorSeverity(err) == wire.SeverityHigh {
+		// Ban the peer.
+	}
+
+Failures to decode the payload of a message read by the read functions are
+returned as a *wire.DecodeError wrapping the underlying error.  It records the
+offset into the payload at which decoding failed, the path of the field being
+decoded, such as Transactions[9999].TxOut[2].PkScript, and, for payloads held
+in memory, the surrounding payload bytes.
+
 # Bitcoin Improvement Proposals
 
 This package includes spec changes outlined by the following BIPs:
@@ -5,19 +5,171 @@
 package wire
 
 import (
+	"errors"
 	"fmt"
 )
 
+// Severity is the suggested misbehaviour severity of an error caused by a
+// message received from a peer.  It lets a node decide how to respond to the
+// peer without inspecting error descriptions.
+type Severity uint8
+
+// These constants define the misbehaviour severities in increasing order.
+const (
+	// SeverityNone indicates the error does not imply any misbehaviour by
+	// the peer, for example a command introduced by a newer protocol
+	// version, or that it was caused locally.
+	SeverityNone Severity = iota
+
+	// SeverityLow indicates misbehaviour which may be accidental, such as
+	// a corrupted payload.  Dropping the message is usually sufficient.
+	SeverityLow
+
+	// SeverityMedium indicates the peer is incompatible, for example
+	// because it is on another network.  Disconnecting is usually
+	// sufficient.
+	SeverityMedium
+
+	// SeverityHigh indicates the peer sent a message no correct
+	// implementation produces, such as one exceeding the protocol limits.
+	// Banning the peer is usually warranted.
+	SeverityHigh
+)
+
+// Map of severities back to their constant names for pretty printing.
+var severityStrings = map[Severity]string{
+	SeverityNone:   "SeverityNone",
+	SeverityLow:    "SeverityLow",
+	SeverityMedium: "SeverityMedium",
+	SeverityHigh:   "SeverityHigh",
+}
+
+// String returns the Severity in human-readable form.
+func (s Severity) String() string {
+	if str, ok := severityStrings[s]; ok {
+		return str
+	}
+
+	return fmt.Sprintf("Unknown Severity (%d)", uint8(s))
+}
+
+// ErrorKind identifies a kind of message error.  It implements the error
+// interface so the kind of a MessageError can be tested with errors.Is, and
+// extracted with errors.As, through any number of wrapping errors.
+type ErrorKind int
+
+// These constants define the kinds of message errors.
+const (
+	// ErrMalformedMessage indicates a message whose fields are invalid or
+	// inconsistent, for example trailing bytes after a transaction.
+	ErrMalformedMessage ErrorKind = iota + 1
+
+	// ErrChecksumMismatch indicates the checksum of a payload does not
+	// match its header.
+	ErrChecksumMismatch
+
+	// ErrWrongNetwork indicates a message for another bitcoin network.
+	ErrWrongNetwork
+
+	// ErrPayloadTooLarge indicates a payload which exceeds the maximum
+	// payload of all messages, of its message type, or of the peer.
+	ErrPayloadTooLarge
+
+	// ErrInvalidCommand indicates a command which is not valid utf-8 or
+	// is too long.
+	ErrInvalidCommand
+
+	// ErrUnknownCommand indicates a well-formed command which is not
+	// registered.  See RegisterMessage and Codec.SetUnknownPassthrough.
+	ErrUnknownCommand
+
+	// ErrNonCanonicalVarInt indicates a variable length integer which is
+	// not encoded using the fewest possible bytes.
+	ErrNonCanonicalVarInt
+
+	// ErrTooManyItems indicates a count of items, such as inventory
+	// vectors, addresses or transactions, which exceeds its limit.
+	ErrTooManyItems
+
+	// ErrElementTooLarge indicates a single variable length element, such
+	// as a string, script or filter, which exceeds its limit.
+	ErrElementTooLarge
+
+	// ErrProtocolVersion indicates a message which is not valid for the
+	// protocol version in use.
+	ErrProtocolVersion
+
+	// ErrUnsupported indicates an operation this package does not support
+	// for the message or reader, such as streaming a version message.
+	ErrUnsupported
+
+	// ErrInvalidArgument indicates the package was used incorrectly, for
+	// example by registering the same command twice.
+	ErrInvalidArgument
+)
+
+// Map of error kinds back to their constant names for pretty printing.
+var errorKindStrings = map[ErrorKind]string{
+	ErrMalformedMessage:   "ErrMalformedMessage",
+	ErrChecksumMismatch:   "ErrChecksumMismatch",
+	ErrWrongNetwork:       "ErrWrongNetwork",
+	ErrPayloadTooLarge:    "ErrPayloadTooLarge",
+	ErrInvalidCommand:     "ErrInvalidCommand",
+	ErrUnknownCommand:     "ErrUnknownCommand",
+	ErrNonCanonicalVarInt: "ErrNonCanonicalVarInt",
+	ErrTooManyItems:       "ErrTooManyItems",
+	ErrElementTooLarge:    "ErrElementTooLarge",
+	ErrProtocolVersion:    "ErrProtocolVersion",
+	ErrUnsupported:        "ErrUnsupported",
+	ErrInvalidArgument:    "ErrInvalidArgument",
+}
+
+// Map of error kinds to the suggested severity when the error is caused by a
+// message received from a peer.
+var errorKindSeverities = map[ErrorKind]Severity{
+	ErrMalformedMessage:   SeverityHigh,
+	ErrChecksumMismatch:   SeverityLow,
+	ErrWrongNetwork:       SeverityMedium,
+	ErrPayloadTooLarge:    SeverityHigh,
+	ErrInvalidCommand:     SeverityMedium,
+	ErrUnknownCommand:     SeverityNone,
+	ErrNonCanonicalVarInt: SeverityHigh,
+	ErrTooManyItems:       SeverityHigh,
+	ErrElementTooLarge:    SeverityHigh,
+	ErrProtocolVersion:    SeverityLow,
+	ErrUnsupported:        SeverityNone,
+	ErrInvalidArgument:    SeverityNone,
+}
+
+// Error returns the ErrorKind in human-readable form.  This is part of the
+// error interface implementation.
+func (k ErrorKind) Error() string {
+	if str, ok := errorKindStrings[k]; ok {
+		return str
+	}
+
+	return fmt.Sprintf("Unknown ErrorKind (%d)", int(k))
+}
+
+// Severity returns the suggested misbehaviour severity for errors of the kind
+// caused by a message received from a peer.  Errors returned while encoding
+// are caused locally regardless of their kind.
+func (k ErrorKind) Severity() Severity {
+	return errorKindSeverities[k]
+}
+
 // MessageError describes an issue with a message.
 // An example of some potential issues are messages from the wrong bitcoin
 // network, invalid commands, mismatched checksums, and exceeding max payloads.
 //
 // This provides a mechanism for the caller to type assert the error to
 // differentiate between general io errors such as io.EOF and issues that
-// resulted from malformed messages.
+// resulted from malformed messages.  The Kind of the error can be tested with
+// errors.Is, for example errors.Is(err, ErrChecksumMismatch).
 type MessageError struct {
-	Func        string // Function name
-	Description string // Human readable description of the issue
+	Func        string    // Function name
+	Kind        ErrorKind // Kind of the issue
+	Description string    // Human readable description of the issue
 }
 
 // Error satisfies the error interface and prints human-readable errors.
@@ -29,7 +181,35 @@ func (e *MessageError) Error() string {
 	return e.Description
 }
 
-// messageError creates an error for the given function and description.
-func messageError(f, desc string) *MessageError {
-	return &MessageError{Func: f, Description: desc}
+// Unwrap returns the kind of the error, or nil when it has none, so the kind
+// can be tested with errors.Is and errors.As.
+func (e *MessageError) Unwrap() error {
+	if e.Kind == 0 {
+		return nil
+	}
+
+	return e.Kind
+}
+
+// Severity returns the suggested misbehaviour severity of the error.
+func (e *MessageError) Severity() Severity {
+	return e.Kind.Severity()
+}
+
+// ErrorSeverity returns the suggested misbehaviour severity of err, which may
+// wrap a MessageError.  Errors without a kind, such as io errors, have
+// SeverityNone.
+func ErrorSeverity(err error) Severity {
+	var kind ErrorKind
+	if errors.As(err, &kind) {
+		return kind.Severity()
+	}
+
+	return SeverityNone
+}
+
+// messageError creates an error of the given kind for the given function and
+// description.
+func messageError(f string, kind ErrorKind, desc string) *MessageError {
+	return &MessageError{Func: f, Kind: kind, Description: desc}
 }
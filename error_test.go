@@ -0,0 +1,160 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestErrorKindStringer tests the stringized output of the error kinds and
+// severities.
+func TestErrorKindStringer(t *testing.T) {
+	for kind := ErrMalformedMessage; kind <= ErrInvalidArgument; kind++ {
+		assert.Contains(t, errorKindStrings, kind)
+		assert.Contains(t, errorKindSeverities, kind)
+	}
+
+	assert.Equal(t, "ErrChecksumMismatch", ErrChecksumMismatch.Error())
+	assert.Equal(t, "Unknown ErrorKind (0)", ErrorKind(0).Error())
+	assert.Equal(t, "SeverityHigh", SeverityHigh.String())
+	assert.Equal(t, "Unknown Severity (9)", Severity(9).String())
+}
+
+// TestMessageErrorKind ensures message errors can be classified with
+// errors.Is and errors.As, including through wrapping errors.
+func TestMessageErrorKind(t *testing.T) {
+	err := messageError("foo", ErrTooManyItems, "too many things")
+	assert.Equal(t, "foo: too many things", err.Error())
+	assert.Equal(t, SeverityHigh, err.Severity())
+
+	wrapped := fmt.Errorf("peer 1: %w", &BatchError{Index: 3, Err: err})
+	require.ErrorIs(t, wrapped, ErrTooManyItems)
+	assert.NotErrorIs(t, wrapped, ErrMalformedMessage)
+	assert.Equal(t, SeverityHigh, ErrorSeverity(wrapped))
+
+	var kind ErrorKind
+	require.ErrorAs(t, wrapped, &kind)
+	assert.Equal(t, ErrTooManyItems, kind)
+
+	// Errors without a kind.
+	assert.Equal(t, SeverityNone, ErrorSeverity(&MessageError{Description: "bad"}))
+	assert.Equal(t, SeverityNone, ErrorSeverity(io.ErrUnexpectedEOF))
+	assert.Equal(t, SeverityNone, ErrorSeverity(nil))
+	assert.NoError(t, (&MessageError{}).Unwrap())
+}
+
+// TestReadMessageErrorKinds ensures the read paths report the kind of each
+// decode and framing failure.
+func TestReadMessageErrorKinds(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+
+	badChecksum := encodeFrame(t, codec, NewMsgPing(7))
+	badChecksum[MessageHeaderSize] ^= 0xff
+
+	limited := NewCodec(MainNet, ProtocolVersion)
+	limited.SetLimits(1000)
+
+	tooManyInv := []byte{0xfe, 0x00, 0x00, 0x00, 0x01}
+
+	tests := []struct {
+		name     string
+		codec    *Codec
+		frame    []byte
+		kind     ErrorKind
+		severity Severity
+	}{
+		{
+			"checksum", codec, badChecksum,
+			ErrChecksumMismatch, SeverityLow,
+		},
+		{
+			"other network", codec, encodeFrame(t, NewCodec(TestNet, ProtocolVersion), NewMsgPing(7)),
+			ErrWrongNetwork, SeverityMedium,
+		},
+		{
+			"payload too large", limited, encodeFrame(t, codec, &blockOne),
+			ErrPayloadTooLarge, SeverityHigh,
+		},
+		{
+			"invalid command", codec, encodeFrame(t, codec, &fakeMessage{command: "\xff"}),
+			ErrInvalidCommand, SeverityMedium,
+		},
+		{
+			"unknown command", codec, encodeFrame(t, codec, &fakeMessage{command: "xunknown"}),
+			ErrUnknownCommand, SeverityNone,
+		},
+		{
+			"non-canonical varint", codec, encodeFrame(t, codec, &fakeMessage{command: CmdInv, payload: []byte{0xfd, 0x01, 0x00}}),
+			ErrNonCanonicalVarInt, SeverityHigh,
+		},
+		{
+			"too many items", codec, encodeFrame(t, codec, &fakeMessage{command: CmdInv, payload: tooManyInv}),
+			ErrTooManyItems, SeverityHigh,
+		},
+		{
+			"element too large", codec, encodeFrame(t, codec, &fakeMessage{command: CmdReject, payload: []byte{0xfe, 0x00, 0x00, 0x00, 0x10}}),
+			ErrElementTooLarge, SeverityHigh,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reads := map[string]func() error{
+				"Read": func() error {
+					_, _, _, err := tt.codec.Read(bytes.NewReader(tt.frame))
+					return err
+				},
+				"ReadStreaming": func() error {
+					_, _, err := tt.codec.ReadStreaming(bytes.NewReader(tt.frame))
+					return err
+				},
+				"DecodeBytes": func() error {
+					_, _, _, err := tt.codec.DecodeBytes(tt.frame)
+					return err
+				},
+			}
+
+			for name, read := range reads {
+				err := read()
+				require.ErrorIs(t, err, tt.kind, name)
+				assert.Equal(t, tt.severity, ErrorSeverity(err), name)
+
+				var msgErr *MessageError
+				require.ErrorAs(t, err, &msgErr, name)
+				assert.Equal(t, tt.kind, msgErr.Kind, name)
+			}
+		})
+	}
+}
+
+// TestBsvdecodeErrorKinds ensures message decoders report the kind of their
+// failures.
+func TestBsvdecodeErrorKinds(t *testing.T) {
+	err := NewMsgFeeFilter(1).Bsvdecode(bytes.NewReader(make([]byte, 8)), FeeFilterVersion-1, BaseEncoding)
+	require.ErrorIs(t, err, ErrProtocolVersion)
+
+	var tx MsgTx
+
+	err = tx.FromBytes(append(bytes.Clone(multiTxEncoded), 0x00))
+	require.ErrorIs(t, err, ErrMalformedMessage)
+
+	var cfcheckpt MsgCFCheckpt
+
+	err = cfcheckpt.Bsvdecode(bytes.NewReader(append(make([]byte, 33), 0xfe, 0xff, 0xff, 0xff, 0x00)),
+		ProtocolVersion, BaseEncoding)
+	require.ErrorIs(t, err, ErrInsaneCFHeaderCount)
+	require.ErrorIs(t, err, ErrTooManyItems)
+
+	err = RegisterMessage(CmdPing, func() Message { return &MsgPing{} })
+	require.ErrorIs(t, err, ErrInvalidArgument)
+	assert.False(t, errors.Is(err, ErrUnknownCommand))
+}
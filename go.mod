@@ -4,12 +4,12 @@ go 1.25.0
 
 require (
 	github.com/bsv-blockchain/go-bt/v2 v2.6.9
+	github.com/bsv-blockchain/go-sdk v1.3.3
 	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc
 	github.com/stretchr/testify v1.12.0
 )
 
 require (
-	github.com/bsv-blockchain/go-sdk v1.3.3 // indirect
 	github.com/kr/text v0.2.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	golang.org/x/crypto v0.55.0 // indirect
@@ -0,0 +1,179 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"io"
+	"slices"
+)
+
+// ReadHandler decodes the message of a frame read by a Codec.
+type ReadHandler func(f *Frame) (Message, error)
+
+// ReadInterceptor intercepts the decoding of messages read by a Codec.  It is
+// passed the frame of the message, whose header has been validated, and the
+// next handler in the chain, which ends with the default decoding.
+//
+// An interceptor may observe the message by calling next and inspecting its
+// results, transform it by modifying the frame before calling next or the
+// message after, or replace the decoding altogether by consuming the payload
+// itself via the Reader of the frame without calling next.  An interceptor
+// which does not want to handle a message declines by returning next(f).
+//
+// For Codec.Read the payload of the frame is held in memory and its checksum
+// has been verified; it may be rewritten by replacing Payload before calling
+// next.  For Codec.ReadStreaming the payload is still on the reader: Reader
+// verifies the checksum once the payload has been read in full, and any part
+// of the payload which is not consumed is discarded after the chain returns.
+type ReadInterceptor func(f *Frame, next ReadHandler) (Message, error)
+
+// WriteHandler writes a message to w and returns the number of bytes written.
+type WriteHandler func(w io.Writer, msg Message) (int, error)
+
+// WriteInterceptor intercepts the writing of messages by a Codec.  It is
+// passed the writer, the message and the next handler in the chain, which ends
+// with the default framing and encoding.
+//
+// Like a ReadInterceptor it may observe, transform or replace the write, and
+// declines by returning next(w, msg).
+type WriteInterceptor func(w io.Writer, msg Message, next WriteHandler) (int, error)
+
+// readInterceptor is a read interceptor along with the commands it applies
+// to.  No commands means every command.
+type readInterceptor struct {
+	ic       ReadInterceptor
+	commands []string
+}
+
+// writeInterceptor is a write interceptor along with the commands it applies
+// to.  No commands means every command.
+type writeInterceptor struct {
+	ic       WriteInterceptor
+	commands []string
+}
+
+// InterceptRead adds ic to the read interceptors of the default codec used by
+// the package-level read functions.  See Codec.InterceptRead for details.
+func InterceptRead(ic ReadInterceptor, commands ...string) func() {
+	return defaultCodec.InterceptRead(ic, commands...)
+}
+
+// InterceptWrite adds ic to the write interceptors of the default codec used
+// by the package-level write functions.  See Codec.InterceptWrite for details.
+func InterceptWrite(ic WriteInterceptor, commands ...string) func() {
+	return defaultCodec.InterceptWrite(ic, commands...)
+}
+
+// InterceptRead adds ic to the read interceptors of the codec for messages
+// with the provided commands, or for every message when no commands are
+// provided, and returns a function which removes it again.
+//
+// Interceptors apply to Read, ReadStreaming, ReadContext and DecodeBytes.
+// Interceptors added earlier run first, so each wraps those added after it.
+// Handlers installed via SetExternalHandler take priority over interceptors.
+func (c *Codec) InterceptRead(ic ReadInterceptor, commands ...string) func() {
+	entry := &readInterceptor{ic: ic, commands: slices.Clone(commands)}
+
+	c.mtx.Lock()
+	c.readInterceptors = append(slices.Clip(c.readInterceptors), entry)
+	c.mtx.Unlock()
+
+	return func() {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+
+		c.readInterceptors = slices.DeleteFunc(slices.Clone(c.readInterceptors),
+			func(e *readInterceptor) bool { return e == entry })
+	}
+}
+
+// InterceptWrite adds ic to the write interceptors of the codec for messages
+// with the provided commands, or for every message when no commands are
+// provided, and returns a function which removes it again.
+//
+// Interceptors apply to Write, WriteStreaming, WriteContext and to every
+// message added to a batch.  Interceptors added earlier run first, so each
+// wraps those added after it.
+func (c *Codec) InterceptWrite(ic WriteInterceptor, commands ...string) func() {
+	entry := &writeInterceptor{ic: ic, commands: slices.Clone(commands)}
+
+	c.mtx.Lock()
+	c.writeInterceptors = append(slices.Clip(c.writeInterceptors), entry)
+	c.mtx.Unlock()
+
+	return func() {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+
+		c.writeInterceptors = slices.DeleteFunc(slices.Clone(c.writeInterceptors),
+			func(e *writeInterceptor) bool { return e == entry })
+	}
+}
+
+// readChain returns handler wrapped by the read interceptors which apply to
+// cmd, or nil when there are none.
+func (c *Codec) readChain(cmd string, handler ReadHandler) ReadHandler {
+	c.mtx.RLock()
+	ics := c.readInterceptors
+	c.mtx.RUnlock()
+
+	var chain ReadHandler
+
+	for i := len(ics) - 1; i >= 0; i-- {
+		e := ics[i]
+		if len(e.commands) != 0 && !slices.Contains(e.commands, cmd) {
+			continue
+		}
+
+		next := chain
+		if next == nil {
+			next = handler
+		}
+
+		chain = func(f *Frame) (Message, error) {
+			return e.ic(f, next)
+		}
+	}
+
+	return chain
+}
+
+// writeChain returns write, bound to p, wrapped by the write interceptors
+// which apply to cmd, or nil when there are none.
+func (c *Codec) writeChain(cmd string, p codecParams,
+	write func(io.Writer, Message, codecParams) (int, error),
+) WriteHandler {
+	c.mtx.RLock()
+	ics := c.writeInterceptors
+	c.mtx.RUnlock()
+
+	var chain WriteHandler
+
+	for i := len(ics) - 1; i >= 0; i-- {
+		e := ics[i]
+		if len(e.commands) != 0 && !slices.Contains(e.commands, cmd) {
+			continue
+		}
+
+		next := chain
+		if next == nil {
+			next = func(w io.Writer, msg Message) (int, error) {
+				return write(w, msg, p)
+			}
+		}
+
+		chain = func(w io.Writer, msg Message) (int, error) {
+			return e.ic(w, msg, next)
+		}
+	}
+
+	return chain
+}
+
+// decodeFrame decodes f using the protocol version and encoding it was read
+// with.  It is the default ReadHandler which ends every read chain.
+func decodeFrame(f *Frame) (Message, error) {
+	return f.Decode(f.p.pver, f.p.enc)
+}
@@ -0,0 +1,311 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// interceptReads returns the read functions of codec which run the read
+// interceptors, each reading a single message from r.
+func interceptReads(codec *Codec) map[string]func(r io.Reader) (int, Message, error) {
+	return map[string]func(r io.Reader) (int, Message, error){
+		"Read": func(r io.Reader) (int, Message, error) {
+			n, msg, _, err := codec.Read(r)
+			return n, msg, err
+		},
+		"ReadStreaming": codec.ReadStreaming,
+	}
+}
+
+// TestInterceptRead ensures read interceptors observe messages in the order
+// they were added, apply only to their commands, fall through to the default
+// decoding when they decline and can be removed.
+func TestInterceptRead(t *testing.T) {
+	for _, name := range []string{"Read", "ReadStreaming"} {
+		t.Run(name, func(t *testing.T) {
+			codec := NewCodec(MainNet, ProtocolVersion)
+			block := encodeFrame(t, codec, &blockOne)
+			ping := encodeFrame(t, codec, NewMsgPing(7))
+			read := interceptReads(codec)[name]
+
+			var seen []string
+
+			observe := func(tag string) ReadInterceptor {
+				return func(f *Frame, next ReadHandler) (Message, error) {
+					seen = append(seen, tag+":"+f.Command)
+
+					msg, err := next(f)
+					if err == nil {
+						seen = append(seen, tag+":"+msg.Command())
+					}
+
+					return msg, err
+				}
+			}
+
+			removeAll := codec.InterceptRead(observe("all"))
+			removeBlock := codec.InterceptRead(observe("block"), CmdBlock)
+
+			r := bytes.NewReader(concat(block, ping))
+
+			n, msg, err := read(r)
+			require.NoError(t, err)
+			assert.Equal(t, len(block), n)
+			assert.Equal(t, &blockOne, msg)
+
+			n, msg, err = read(r)
+			require.NoError(t, err)
+			assert.Equal(t, len(ping), n)
+			assert.Equal(t, NewMsgPing(7), msg)
+
+			assert.Equal(t, []string{
+				"all:block", "block:block", "block:block", "all:block",
+				"all:ping", "all:ping",
+			}, seen)
+
+			// Removed interceptors no longer run.
+			removeAll()
+			removeBlock()
+			removeBlock()
+
+			seen = nil
+
+			_, msg, err = read(bytes.NewReader(block))
+			require.NoError(t, err)
+			assert.Equal(t, &blockOne, msg)
+			assert.Empty(t, seen)
+		})
+	}
+}
+
+// TestInterceptReadReplace ensures read interceptors can replace the decoding
+// by consuming the payload themselves, and that the stream stays aligned and
+// the byte counts exact however much of the payload they read.
+func TestInterceptReadReplace(t *testing.T) {
+	for _, name := range []string{"Read", "ReadStreaming"} {
+		t.Run(name, func(t *testing.T) {
+			codec := NewCodec(MainNet, ProtocolVersion)
+			block := encodeFrame(t, codec, &blockOne)
+			ping := encodeFrame(t, codec, NewMsgPing(7))
+			read := interceptReads(codec)[name]
+
+			// Spill the block to a buffer standing in for a file.
+			var spill bytes.Buffer
+
+			remove := codec.InterceptRead(func(f *Frame, _ ReadHandler) (Message, error) {
+				spill.Reset()
+
+				if _, err := io.Copy(&spill, f.Reader()); err != nil {
+					return nil, err
+				}
+
+				return &MsgRaw{Cmd: f.Command, Payload: spill.Bytes()}, nil
+			}, CmdBlock)
+
+			r := bytes.NewReader(concat(block, ping))
+
+			n, msg, err := read(r)
+			require.NoError(t, err)
+			assert.Equal(t, len(block), n)
+			assert.Equal(t, block[MessageHeaderSize:], msg.(*MsgRaw).Payload)
+
+			n, msg, err = read(r)
+			require.NoError(t, err)
+			assert.Equal(t, len(ping), n)
+			assert.Equal(t, NewMsgPing(7), msg)
+
+			remove()
+
+			// Read only part of the payload.
+			codec.InterceptRead(func(f *Frame, _ ReadHandler) (Message, error) {
+				_, err := io.ReadFull(f.Reader(), make([]byte, 10))
+				return &MsgRaw{Cmd: f.Command}, err
+			}, CmdBlock)
+
+			r = bytes.NewReader(concat(block, ping))
+
+			n, _, err = read(r)
+			require.NoError(t, err)
+			assert.Equal(t, len(block), n)
+
+			_, msg, err = read(r)
+			require.NoError(t, err)
+			assert.Equal(t, NewMsgPing(7), msg)
+		})
+	}
+}
+
+// TestInterceptReadRewrite ensures read interceptors can rewrite the payload
+// of buffered reads before it is decoded.
+func TestInterceptReadRewrite(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	frame := encodeFrame(t, codec, NewMsgPing(7))
+	rewritten := encodeFrame(t, codec, NewMsgPing(9))[MessageHeaderSize:]
+
+	codec.InterceptRead(func(f *Frame, next ReadHandler) (Message, error) {
+		f.Payload = rewritten
+		return next(f)
+	})
+
+	n, msg, payload, err := codec.Read(bytes.NewReader(frame))
+	require.NoError(t, err)
+	assert.Equal(t, len(frame), n)
+	assert.Equal(t, NewMsgPing(9), msg)
+	assert.Equal(t, rewritten, payload)
+
+	_, msg, _, err = codec.DecodeBytes(frame)
+	require.NoError(t, err)
+	assert.Equal(t, NewMsgPing(9), msg)
+}
+
+// TestInterceptReadChecksum ensures the payload reader of streamed frames
+// verifies the checksum and reports truncation.
+func TestInterceptReadChecksum(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	frame := encodeFrame(t, codec, &blockOne)
+
+	codec.InterceptRead(func(f *Frame, _ ReadHandler) (Message, error) {
+		_, err := io.ReadAll(f.Reader())
+		return &MsgRaw{Cmd: f.Command}, err
+	})
+
+	corrupt := bytes.Clone(frame)
+	corrupt[len(corrupt)-1] ^= 0xff
+
+	n, _, err := codec.ReadStreaming(bytes.NewReader(corrupt))
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+	assert.Equal(t, len(frame), n)
+
+	n, _, err = codec.ReadStreaming(bytes.NewReader(frame[:len(frame)-1]))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, len(frame)-1, n)
+
+	// Buffered reads verify the checksum before the interceptors run.
+	_, _, _, err = codec.Read(bytes.NewReader(corrupt))
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+}
+
+// TestInterceptWrite ensures write interceptors can observe, transform and
+// replace writes and apply only to their commands.
+func TestInterceptWrite(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	ping := encodeFrame(t, codec, NewMsgPing(7))
+	pong := encodeFrame(t, codec, NewMsgPong(7))
+	rewritten := encodeFrame(t, codec, NewMsgPing(9))
+
+	writes := map[string]func(w io.Writer, msg Message) (int, error){
+		"Write":          codec.Write,
+		"WriteStreaming": codec.WriteStreaming,
+	}
+
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			var seen []string
+
+			removeObserve := codec.InterceptWrite(func(w io.Writer, msg Message, next WriteHandler) (int, error) {
+				n, err := next(w, msg)
+				seen = append(seen, msg.Command())
+
+				return n, err
+			})
+			defer removeObserve()
+
+			removeTransform := codec.InterceptWrite(func(w io.Writer, _ Message, next WriteHandler) (int, error) {
+				return next(w, NewMsgPing(9))
+			}, CmdPing)
+
+			var buf bytes.Buffer
+
+			n, err := write(&buf, NewMsgPing(7))
+			require.NoError(t, err)
+			assert.Equal(t, len(rewritten), n)
+			assert.Equal(t, rewritten, buf.Bytes())
+
+			buf.Reset()
+
+			n, err = write(&buf, NewMsgPong(7))
+			require.NoError(t, err)
+			assert.Equal(t, len(pong), n)
+			assert.Equal(t, pong, buf.Bytes())
+			assert.Equal(t, []string{CmdPing, CmdPong}, seen)
+
+			removeTransform()
+			buf.Reset()
+
+			_, err = write(&buf, NewMsgPing(7))
+			require.NoError(t, err)
+			assert.Equal(t, ping, buf.Bytes())
+
+			// Replace the write altogether.
+			removeReplace := codec.InterceptWrite(func(_ io.Writer, _ Message, _ WriteHandler) (int, error) {
+				return 0, nil
+			})
+
+			buf.Reset()
+
+			n, err = write(&buf, NewMsgPing(7))
+			require.NoError(t, err)
+			assert.Equal(t, 0, n)
+			assert.Equal(t, 0, buf.Len())
+
+			removeReplace()
+		})
+	}
+}
+
+// TestInterceptWriteBatch ensures write interceptors apply to every message
+// added to a batch and that a failed interceptor leaves the batch unchanged.
+func TestInterceptWriteBatch(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	pong := encodeFrame(t, codec, NewMsgPong(7))
+	rewritten := encodeFrame(t, codec, NewMsgPing(9))
+
+	remove := codec.InterceptWrite(func(w io.Writer, _ Message, next WriteHandler) (int, error) {
+		return next(w, NewMsgPing(9))
+	}, CmdPing)
+	defer remove()
+
+	var buf bytes.Buffer
+
+	counts, err := codec.WriteBatch(&buf, NewMsgPing(7), NewMsgPong(7))
+	require.NoError(t, err)
+	assert.Equal(t, []int{len(rewritten), len(pong)}, counts)
+	assert.Equal(t, append(rewritten, pong...), buf.Bytes())
+
+	// Replace the write altogether.
+	removeReplace := codec.InterceptWrite(func(_ io.Writer, _ Message, _ WriteHandler) (int, error) {
+		return 0, nil
+	}, CmdPing)
+
+	buf.Reset()
+
+	counts, err = codec.WriteBatch(&buf, NewMsgPing(7), NewMsgPong(7))
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, len(pong)}, counts)
+	assert.Equal(t, pong, buf.Bytes())
+
+	removeReplace()
+
+	// A failed interceptor leaves the batch unchanged.
+	errIntercept := errors.New("intercepted")
+
+	removeFail := codec.InterceptWrite(func(_ io.Writer, _ Message, _ WriteHandler) (int, error) {
+		return 0, errIntercept
+	}, CmdPong)
+	defer removeFail()
+
+	b := codec.NewBatchWriter()
+	require.NoError(t, b.Add(NewMsgPing(7)))
+	require.ErrorIs(t, b.Add(NewMsgPong(7)), errIntercept)
+	assert.Equal(t, 1, b.Len())
+	assert.Equal(t, len(rewritten), b.Size())
+}
@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 
@@ -64,13 +65,81 @@ func NewInvVect(typ InvType, hash *chainhash.Hash) *InvVect {
 	}
 }
 
+// MarshalBinary returns the bitcoin protocol encoding of the inventory vector.
+// This is part of the encoding.BinaryMarshaler interface implementation.
+func (iv *InvVect) MarshalBinary() ([]byte, error) {
+	aw := &appendWriter{b: make([]byte, 0, maxInvVectPayload)}
+	if err := writeInvVect(aw, ProtocolVersion, iv); err != nil {
+		return nil, err
+	}
+
+	return aw.b, nil
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of an inventory vector, into the receiver.  This is part of the
+// encoding.BinaryUnmarshaler interface implementation.
+func (iv *InvVect) UnmarshalBinary(data []byte) error {
+	return decodeExact(data, "inventory vector", func(r io.Reader) error {
+		return readInvVect(r, ProtocolVersion, iv)
+	})
+}
+
+// WriteTo writes the bitcoin protocol encoding of the inventory vector to w
+// and returns the number of bytes written.  This is part of the io.WriterTo
+// interface implementation.
+func (iv *InvVect) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(iv, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding of an
+// inventory vector read from r until EOF and returns the number of bytes read.
+// This is part of the io.ReaderFrom interface implementation.
+func (iv *InvVect) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(iv, r, maxInvVectPayload)
+}
+
+// invVectJSON is the JSON representation of an inventory vector.
+type invVectJSON struct {
+	Type jsonInvType `json:"type"`
+	Hash jsonHash    `json:"hash"`
+}
+
+// MarshalJSON returns the JSON representation of the inventory vector, which
+// holds its type by name, such as "MSG_TX", and its hash.  This is part of the
+// json.Marshaler interface implementation.
+func (iv InvVect) MarshalJSON() ([]byte, error) {
+	return json.Marshal(invVectJSON{Type: jsonInvType(iv.Type), Hash: jsonHash(iv.Hash)})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (iv *InvVect) UnmarshalJSON(data []byte) error {
+	var j invVectJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*iv = InvVect{Type: InvType(j.Type), Hash: chainhash.Hash(j.Hash)}
+
+	return nil
+}
+
 // readInvVect reads an encoded InvVect from r depending on the protocol
 // version.
 func readInvVect(r io.Reader, _ uint32, iv *InvVect) error {
-	return readElements(r, &iv.Type, &iv.Hash)
+	if err := readUint32(r, &iv.Type); err != nil {
+		return err
+	}
+
+	return readHash(r, &iv.Hash)
 }
 
 // writeInvVect serializes an InvVect to w depending on the protocol version.
 func writeInvVect(w io.Writer, _ uint32, iv *InvVect) error {
-	return writeElements(w, iv.Type, &iv.Hash)
+	if err := writeUint32(w, iv.Type); err != nil {
+		return err
+	}
+
+	return writeHash(w, &iv.Hash)
 }
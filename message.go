@@ -7,11 +7,13 @@ package wire
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"hash"
 	"io"
 	"math"
+	"sync/atomic"
 	"unicode/utf8"
 
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
@@ -26,29 +28,122 @@ const MessageHeaderSize = 24
 // header.  Shorter commands must be zero padded.
 const CommandSize = 12
 
-// ebs is the excessive block size, used to determine reasonable maximum message sizes.
-// 32MB is the current default value
-var ebs uint64 = 32000000
+// ExtendedMessageHeaderSize is the number of bytes in an extended bitcoin
+// message header, which is used for payloads of 4GB and larger.  It consists
+// of a common header with the extmsg command, a payload length of 0xffffffff
+// and a zero checksum, followed by the actual command 12 bytes + payload
+// length 8 bytes.
+const ExtendedMessageHeaderSize = MessageHeaderSize + CommandSize + 8
+
+// extLengthMarker is the payload length used in the common header of an
+// extended message.
+const extLengthMarker = math.MaxUint32
+
+// DefaultExcessiveBlockSize is the excessive block size used until SetLimits
+// is called.  32MB is the current default value.
+const DefaultExcessiveBlockSize uint64 = 32000000
+
+// ebs is the excessive block size, used to determine reasonable maximum message
+// sizes.  It is accessed atomically since SetLimits may be called while other
+// goroutines are reading and writing messages.
+var ebs = func() *atomic.Uint64 {
+	v := new(atomic.Uint64)
+	v.Store(DefaultExcessiveBlockSize)
+
+	return v
+}()
 
 // SetLimits adjusts various message limits based on max block size configuration.
+//
+// This sets the package-wide limit used by the package-level read and write
+// functions and by every Codec which does not have its own limit configured.
+// It also bounds the sanity checks performed by the message decoders
+// themselves (such as the maximum script length) for those codecs.
+//
+// This function is safe for concurrent access.
 func SetLimits(excessiveBlockSize uint64) {
-	ebs = excessiveBlockSize
+	ebs.Store(excessiveBlockSize)
 }
 
 // MaxMessagePayload returns is the maximum bytes a message can be regardless of other
 // individual limits imposed by messages themselves.
 func maxMessagePayload() uint64 {
-	return ((ebs / 1000000) * 1024 * 1024) * 2
+	return decodeLimits{}.maxMessagePayload()
+}
+
+// maxMessagePayloadFor returns the maximum bytes a message can be for the
+// provided excessive block size.
+func maxMessagePayloadFor(excessiveBlockSize uint64) uint64 {
+	return ((excessiveBlockSize / 1000000) * 1024 * 1024) * 2
+}
+
+// decodeLimits holds the excessive block size messages are decoded with, so
+// the sanity checks of the decoders follow the limits of the Codec decoding
+// them.  The zero value follows the package-wide limit set by SetLimits.
+//
+// The readers payloads are decoded from embed it, which makes them implement
+// the limiter interface.
+type decodeLimits struct {
+	excessiveBlockSize uint64
+}
+
+// limiter is implemented by readers which carry the limits the messages
+// decoded from them are bounded by.
+type limiter interface {
+	limits() decodeLimits
+}
+
+// limits returns l.  This is part of the limiter interface implementation.
+func (l decodeLimits) limits() decodeLimits {
+	return l
+}
+
+// limitsOf returns the limits of r when it carries any and the package-wide
+// limits otherwise.
+func limitsOf(r io.Reader) decodeLimits {
+	if l, ok := r.(limiter); ok {
+		return l.limits()
+	}
+
+	return decodeLimits{}
+}
+
+// maxBlockPayload returns the maximum bytes a block message can be.
+func (l decodeLimits) maxBlockPayload() uint64 {
+	if l.excessiveBlockSize != 0 {
+		return l.excessiveBlockSize
+	}
+
+	return MaxBlockPayload()
+}
+
+// maxMessagePayload returns the maximum bytes any message can be.
+func (l decodeLimits) maxMessagePayload() uint64 {
+	return maxMessagePayloadFor(l.maxBlockPayload())
 }
 
-// the external handlers will allow a third party to handle the wire message
+// ExternalHandler is a function which takes over reading the payload of a
+// message.  It is passed the reader positioned at the start of the payload,
+// the payload length and the number of header bytes already read, and returns
+// the total number of bytes read, the decoded message and, optionally, the
+// raw payload.
+type ExternalHandler func(r io.Reader, length uint64, hdrBytes int) (int, Message, []byte, error)
+
+// SetExternalHandler allows a third party to override the way a message is handled globally.
+// The external handlers will allow a third party to handle the wire message
 // differently than the default. This is especially useful, for instance, for very large
 // blocks that may not fit in memory and need to be processed differently.
-var externalHandler = map[string]func(io.Reader, uint64, int) (int, Message, []byte, error){}
-
-// SetExternalHandler allows a third party to override the way a message is handled globally
+//
+// The handler applies to the package-level read functions.  Use
+// Codec.SetExternalHandler to install a handler for a single connection.  A
+// nil handler removes any existing handler for cmd.
+//
+// This function is safe for concurrent access.
+//
+// Deprecated: Use InterceptRead, which composes with other interceptors and
+// may decline messages to fall through to the default decoding.
 func SetExternalHandler(cmd string, handler func(io.Reader, uint64, int) (int, Message, []byte, error)) {
-	externalHandler[cmd] = handler
+	defaultCodec.SetExternalHandler(cmd, handler)
 }
 
 // Commands used in bitcoin message headers which describe the type of message.
@@ -115,122 +210,28 @@ type Message interface {
 	MaxPayloadLength(val uint32) uint64
 }
 
+// PayloadSizer is implemented by messages which know the exact size of their
+// payload up front, which allows buffers to be preallocated and messages to be
+// split to fit the maximum payload length of a peer before encoding them.
+// Every message implemented by this package implements it.
+//
+// PayloadSize returns the number of bytes BsvEncode writes for the message at
+// the provided protocol version.  The result is meaningless when BsvEncode
+// would fail.
+type PayloadSizer interface {
+	PayloadSize(pver uint32) int
+}
+
 // makeEmptyMessage creates a message of the appropriate concrete type based
-// on the command.
+// on the command by consulting the message registry.  See RegisterMessage.
 func makeEmptyMessage(command string) (Message, error) {
-	var msg Message
-
-	switch command {
-	case CmdVersion:
-		msg = &MsgVersion{}
-
-	case CmdVerAck:
-		msg = &MsgVerAck{}
-
-	case CmdGetAddr:
-		msg = &MsgGetAddr{}
-
-	case CmdAddr:
-		msg = &MsgAddr{}
-
-	case CmdGetBlocks:
-		msg = &MsgGetBlocks{}
-
-	case CmdBlock:
-		msg = &MsgBlock{}
-
-	case CmdInv:
-		msg = &MsgInv{}
-
-	case CmdGetData:
-		msg = &MsgGetData{}
-
-	case CmdNotFound:
-		msg = &MsgNotFound{}
-
-	case CmdTx:
-		msg = &MsgTx{}
-
-	case CmdExtendedTx:
-		msg = &MsgExtendedTx{}
-
-	case CmdPing:
-		msg = &MsgPing{}
-
-	case CmdPong:
-		msg = &MsgPong{}
-
-	case CmdGetHeaders:
-		msg = &MsgGetHeaders{}
-
-	case CmdHeaders:
-		msg = &MsgHeaders{}
-
-	case CmdMemPool:
-		msg = &MsgMemPool{}
-
-	case CmdFilterAdd:
-		msg = &MsgFilterAdd{}
-
-	case CmdFilterClear:
-		msg = &MsgFilterClear{}
-
-	case CmdFilterLoad:
-		msg = &MsgFilterLoad{}
-
-	case CmdMerkleBlock:
-		msg = &MsgMerkleBlock{}
-
-	case CmdReject:
-		msg = &MsgReject{}
-
-	case CmdSendHeaders:
-		msg = &MsgSendHeaders{}
-
-	case CmdFeeFilter:
-		msg = &MsgFeeFilter{}
-
-	case CmdGetCFilters:
-		msg = &MsgGetCFilters{}
-
-	case CmdGetCFCheckpt:
-		msg = &MsgGetCFCheckpt{}
-
-	case CmdCFilter:
-		msg = &MsgCFilter{}
-
-	case CmdCFHeaders:
-		msg = &MsgCFHeaders{}
-
-	case CmdCFCheckpt:
-		msg = &MsgCFCheckpt{}
-
-	case CmdProtoconf:
-		msg = &MsgProtoconf{}
-
-	case CmdExtMsg:
-		msg = &MsgExtMsg{}
-
-	case CmdAuthch:
-		msg = &MsgAuthch{}
-
-	case CmdAuthresp:
-		msg = &MsgAuthresp{}
-
-	case CmdSendcmpct:
-		msg = &MsgSendcmpct{}
-
-	case CmdCreateStream:
-		msg = &MsgCreateStream{}
-
-	case CmdStreamAck:
-		msg = &MsgStreamAck{}
-
-	default:
-		return nil, fmt.Errorf("unhandled command [%s]: %#v", command, msg) //nolint:err113 // needs refactoring
+	constructor, ok := LookupMessage(command)
+	if !ok {
+		str := fmt.Sprintf("unhandled command [%s]", command)
+		return nil, &MessageError{Kind: ErrUnknownCommand, Description: str}
 	}
 
-	return msg, nil
+	return constructor(), nil
 }
 
 // messageHeader defines the header structure for all bitcoin protocol messages.
@@ -240,48 +241,93 @@ type messageHeader struct {
 	length    uint32     // 4 bytes
 	checksum  [4]byte    // 4 bytes
 	extLength uint64     // 8 bytes
+	extended  bool       // whether the extended header form is used
 }
 
-// readMessageHeader reads a bitcoin message header from r.
+// payloadLength returns the number of payload bytes which follow the header.
+func (hdr *messageHeader) payloadLength() uint64 {
+	if hdr.extended {
+		return hdr.extLength
+	}
+
+	return uint64(hdr.length)
+}
+
+// readMessageHeader reads a bitcoin message header from r.  Both the common
+// and the extended header forms are supported.
 func readMessageHeader(r io.Reader) (int, *messageHeader, error) {
-	// Since readElements doesn't return the amount of bytes read, attempt
-	// to read the entire header into a buffer first in case there is a
-	// short read, so the proper number of read bytes are known.  This works
-	// since the header is a fixed size.
-	var headerBytes [MessageHeaderSize]byte
+	// Read the entire header into a buffer first in case there is a short
+	// read, so the proper number of read bytes are known.  This works since
+	// the header is a fixed size.
+	var headerBytes [ExtendedMessageHeaderSize]byte
 
-	n, err := io.ReadFull(r, headerBytes[:])
+	n, err := io.ReadFull(r, headerBytes[:MessageHeaderSize])
 	if err != nil {
 		return n, nil, err
 	}
 
-	hr := bytes.NewReader(headerBytes[:])
-
-	// Create and populate a messageHeader struct from the raw header bytes.
-	hdr := messageHeader{}
-
-	var command [CommandSize]byte
+	// Create and populate a messageHeader struct from the raw header bytes:
+	// the magic, the command, the length and the checksum.
+	hdr := messageHeader{
+		magic:  BitcoinNet(binary.LittleEndian.Uint32(headerBytes[0:4])),
+		length: binary.LittleEndian.Uint32(headerBytes[4+CommandSize : 8+CommandSize]),
+	}
 
-	_ = readElements(hr, &hdr.magic, &command, &hdr.length, &hdr.checksum)
+	copy(hdr.checksum[:], headerBytes[8+CommandSize:MessageHeaderSize])
 
 	// Strip trailing zeros from command string.
-	hdr.command = string(bytes.TrimRight(command[:], string(rune(0))))
+	hdr.command = string(bytes.TrimRight(headerBytes[4:4+CommandSize], string(rune(0))))
 
-	if hdr.command == "extmsg" && hdr.length == 0xffffffff && bytes.Equal(hdr.checksum[:], []byte{0x00, 0x00, 0x00, 0x00}) {
-		// This is a special case for the extmsg command which has a
-		var actualCmd [CommandSize]byte
+	if hdr.command == CmdExtMsg && hdr.length == extLengthMarker && hdr.checksum == [4]byte{} {
+		// The extended header form carries the actual command and the
+		// 64-bit payload length directly after the common header.
+		extN, err := io.ReadFull(r, headerBytes[MessageHeaderSize:])
+		n += extN
 
-		var extLength uint64
+		if err != nil {
+			return n, nil, err
+		}
 
-		_ = readElements(hr, &actualCmd, &extLength)
+		ext := headerBytes[MessageHeaderSize:]
 
-		hdr.command = string(bytes.TrimRight(actualCmd[:], string(rune(0))))
-		hdr.extLength = extLength
+		hdr.command = string(bytes.TrimRight(ext[:CommandSize], string(rune(0))))
+		hdr.extLength = binary.LittleEndian.Uint64(ext[CommandSize:])
+		hdr.extended = true
 	}
 
 	return n, &hdr, nil
 }
 
+// encodeMessageHeader returns the serialized form of hdr.  The extended header
+// form is used when hdr.extended is set, in which case hdr.length and
+// hdr.checksum are ignored.
+func encodeMessageHeader(hdr *messageHeader) []byte {
+	var command [CommandSize]byte
+
+	copy(command[:], hdr.command)
+
+	hb := make([]byte, 0, ExtendedMessageHeaderSize)
+	hb = binary.LittleEndian.AppendUint32(hb, uint32(hdr.magic))
+
+	if !hdr.extended {
+		hb = append(hb, command[:]...)
+		hb = binary.LittleEndian.AppendUint32(hb, hdr.length)
+
+		return append(hb, hdr.checksum[:]...)
+	}
+
+	var extCommand [CommandSize]byte
+
+	copy(extCommand[:], CmdExtMsg)
+
+	hb = append(hb, extCommand[:]...)
+	hb = binary.LittleEndian.AppendUint32(hb, uint32(extLengthMarker))
+	hb = append(hb, 0, 0, 0, 0)
+	hb = append(hb, command[:]...)
+
+	return binary.LittleEndian.AppendUint64(hb, hdr.extLength)
+}
+
 // discardInput reads n bytes from reader r in chunks and discards the read
 // bytes.  This is used to skip payloads when various errors occur and helps
 // prevent rogue nodes from causing massive memory allocation through forging
@@ -329,89 +375,150 @@ func WriteMessage(w io.Writer, msg Message, pver uint32, bsvnet BitcoinNet) erro
 func WriteMessageWithEncodingN(w io.Writer, msg Message, pver uint32,
 	bsvnet BitcoinNet, encoding MessageEncoding,
 ) (int, error) {
+	return defaultCodec.writeMessage(w, msg, codecParams{bsvnet: bsvnet, pver: pver, enc: encoding})
+}
+
+// writeMessage writes msg to w including the necessary header information
+// using the provided codec settings and returns the number of bytes written.
+func (c *Codec) writeMessage(w io.Writer, msg Message, p codecParams) (int, error) {
 	if w == nil {
 		return 0, errors.New("writer must not be nil") //nolint:err113 // needs refactoring
 	}
 
-	totalBytes := 0
+	ob := c.observe(DirectionWrite, msg.Command())
+	ob.begin()
 
-	// Enforce max command size.
-	var command [CommandSize]byte
+	var (
+		n   int
+		err error
+	)
+
+	if chain := c.writeChain(msg.Command(), p, writeBuffered); chain != nil {
+		n, err = chain(w, msg)
+	} else {
+		n, err = writeBuffered(w, msg, p)
+	}
+
+	hdrBytes := writtenHeaderBytes(n, p)
+	ob.end(hdrBytes, n-hdrBytes, err)
+
+	return n, err
+}
+
+// writeBuffered writes msg to w, encoding its payload into a buffer, and
+// returns the number of bytes written.  It is the default write handler of
+// writeMessage.
+func writeBuffered(w io.Writer, msg Message, p codecParams) (int, error) {
+	hw, payload, err := frameMessage(msg, p)
+	if err != nil {
+		return 0, err
+	}
+
+	// Write header and payload in 1 go.
+	// This w.Write() is locking, so we don't have to worry about concurrent writings.
+	return w.Write(append(hw, payload...))
+}
 
+// frameMessage encodes msg using the provided codec settings and returns the
+// serialized header and payload which together form the message on the wire.
+// It is shared by the buffered write paths so they frame messages
+// identically.
+func frameMessage(msg Message, p codecParams) ([]byte, []byte, error) {
+	// Enforce max command size.
 	cmd := msg.Command()
 	if len(cmd) > CommandSize {
 		str := fmt.Sprintf("command [%s] is too long [max %v]",
 			cmd, CommandSize)
-		return totalBytes, messageError("WriteMessage", str)
+		return nil, nil, messageError("WriteMessage", ErrInvalidCommand, str)
 	}
 
-	copy(command[:], cmd)
-
-	// Encode the message payload.
+	// Encode the message payload, preallocating the buffer when the size of
+	// the payload is known.
 	var bw bytes.Buffer
 
-	err := msg.BsvEncode(&bw, pver, encoding)
+	if ps, ok := msg.(PayloadSizer); ok {
+		bw.Grow(ps.PayloadSize(p.pver))
+	}
+
+	err := msg.BsvEncode(&bw, p.pver, p.enc)
 	if err != nil {
-		return totalBytes, err
+		return nil, nil, err
 	}
 
 	payload := bw.Bytes()
-	lenp := len(payload)
+	lenp := uint64(len(payload))
+
+	// Create header for the message.  Payloads which do not fit in the
+	// 32-bit length of the common header, including one of exactly
+	// 0xffffffff bytes since that is the extended header marker, require
+	// the extended header form.  Its checksum is always zero since
+	// calculating it over very large payloads is too expensive to be of use.
+	hdr := messageHeader{}
+	hdr.magic = p.bsvnet
+	hdr.command = cmd
+	hdr.extended = p.extended || lenp >= extLengthMarker
 
+	if err = validateWritePayload(msg, lenp, &hdr, p); err != nil {
+		return nil, nil, err
+	}
+
+	if !hdr.extended {
+		copy(hdr.checksum[:], chainhash.DoubleHashB(payload)[0:4])
+	}
+
+	return encodeMessageHeader(&hdr), payload, nil
+}
+
+// validateWritePayload enforces the payload limits on a message of the given
+// size which is about to be written with hdr, and sets the payload length of
+// hdr.  It is shared by the write paths so they reject exactly the same
+// messages.
+func validateWritePayload(msg Message, size uint64, hdr *messageHeader, p codecParams) error {
 	// Enforce maximum overall message payload.
-	if lenp > int(maxMessagePayload()) {
+	if size > p.maxMessagePayload() {
 		str := fmt.Sprintf("message payload is too large - encoded "+
 			"%d bytes, but maximum message payload is %d bytes",
-			lenp, maxMessagePayload())
+			size, p.maxMessagePayload())
 
-		return totalBytes, messageError("WriteMessage", str)
+		return messageError("WriteMessage", ErrPayloadTooLarge, str)
 	}
 
-	// Enforce maximum message payload based on the message type.
-	mpl := msg.MaxPayloadLength(pver)
-	if uint64(lenp) > mpl {
+	// Enforce the maximum payload the remote peer is willing to receive.
+	// Block-sized messages are governed by the excessive block size instead.
+	if p.maxRecvPayloadLength != 0 && size > p.maxRecvPayloadLength && !isBlockSized(msg) {
 		str := fmt.Sprintf("message payload is too large - encoded "+
-			"%d bytes, but maximum message payload size for "+
-			"messages of type [%s] is %d.", lenp, cmd, mpl)
+			"%d bytes, but the peer accepts at most %d bytes",
+			size, p.maxRecvPayloadLength)
 
-		return totalBytes, messageError("WriteMessage", str)
+		return messageError("WriteMessage", ErrPayloadTooLarge, str)
 	}
 
-	// Create header for the message.
-	hdr := messageHeader{}
-	hdr.magic = bsvnet
-	hdr.command = cmd
+	// Enforce maximum message payload based on the message type.
+	mpl := p.maxPayloadLength(msg)
+	if size > mpl {
+		str := fmt.Sprintf("message payload is too large - encoded "+
+			"%d bytes, but maximum message payload size for "+
+			"messages of type [%s] is %d.", size, hdr.command, mpl)
 
-	if lenp > math.MaxUint32 {
-		return totalBytes, err
+		return messageError("WriteMessage", ErrPayloadTooLarge, str)
 	}
 
-	lenpUint32 := uint32(lenp)
-
-	if lenpUint32 >= math.MaxUint32 { //nolint:staticcheck // skip this for now
-		hdr.length = 0xffffffff
-		hdr.extLength = uint64(lenp)
-	} else {
-		hdr.length = uint32(lenp)
+	if !hdr.extended {
+		hdr.length = uint32(size)
+		return nil
 	}
 
-	copy(hdr.checksum[:], chainhash.DoubleHashB(payload)[0:4])
+	if p.pver < ExtMsgVersion {
+		str := fmt.Sprintf("message payload of %d bytes requires "+
+			"an extended message header which is not supported "+
+			"by protocol version %d", size, p.pver)
 
-	// Encode the header for the message.  This is done to a buffer
-	// rather than directly to the writer since writeElements doesn't
-	// return the number of bytes written.
-	hw := bytes.NewBuffer(make([]byte, 0, MessageHeaderSize))
-	if err = writeElements(hw, hdr.magic, command, hdr.length, hdr.checksum); err != nil {
-		return totalBytes, err
+		return messageError("WriteMessage", ErrUnsupported, str)
 	}
 
-	// Write header and payload in 1 go.
-	// This w.Write() is locking, so we don't have to worry about concurrent writings.
-	var n int
-	n, err = w.Write(append(hw.Bytes(), payload...))
-	totalBytes += n
+	hdr.extLength = size
 
-	return totalBytes, err
+	return nil
 }
 
 // ReadMessageWithEncodingN reads, validates, and parses the next bitcoin Message
@@ -421,77 +528,130 @@ func WriteMessageWithEncodingN(w io.Writer, msg Message, pver uint32,
 // allows the caller to specify which message encoding is to consult when
 // decoding wire messages.
 func ReadMessageWithEncodingN(r io.Reader, pver uint32, bsvnet BitcoinNet, enc MessageEncoding) (int, Message, []byte, error) {
-	totalBytes := 0
-	n, hdr, err := readMessageHeader(r)
-	totalBytes += n
+	return defaultCodec.readMessage(r, codecParams{bsvnet: bsvnet, pver: pver, enc: enc})
+}
 
+// readMessage reads, validates, and parses the next bitcoin Message from r
+// using the provided codec settings.
+func (c *Codec) readMessage(r io.Reader, p codecParams) (int, Message, []byte, error) {
+	n, hdr, err := readMessageHeader(r)
 	if err != nil {
-		return totalBytes, nil, nil, err
+		return n, nil, nil, err
 	}
 
+	return c.readPayload(r, hdr, n, p)
+}
+
+// checkHeader validates hdr against the provided codec settings and returns an
+// empty message of the type it announces.  It enforces the overall and the
+// per-type maximum payload, the bitcoin network and the command.  When hdr is
+// rejected after the payload length has been validated, the payload is
+// discarded from r so the stream stays aligned at the next message.
+func (p codecParams) checkHeader(r io.Reader, hdr *messageHeader) (Message, error) {
+	// Determine effective payload length.
+	length := hdr.payloadLength()
+
 	// Enforce maximum message payload.
-	if uint64(hdr.length) > maxMessagePayload() || hdr.extLength > maxMessagePayload() {
+	if length > p.maxMessagePayload() {
 		str := fmt.Sprintf("message payload is too large - header "+
-			"indicates %d bytes (%d extended bytes), but max message payload is %d "+
-			"bytes.", hdr.length, hdr.extLength, maxMessagePayload())
+			"indicates %d bytes, but max message payload is %d "+
+			"bytes.", length, p.maxMessagePayload())
 
-		return totalBytes, nil, nil, messageError("ReadMessage", str)
+		return nil, messageError("ReadMessage", ErrPayloadTooLarge, str)
 	}
 
 	// Check for messages from the wrong bitcoin network.
-	if hdr.magic != bsvnet {
-		discardInput(r, uint64(hdr.length))
+	if hdr.magic != p.bsvnet {
+		discardInput(r, length)
 		str := fmt.Sprintf("message from other network [%v]", hdr.magic)
 
-		return totalBytes, nil, nil, messageError("ReadMessage", str)
+		return nil, messageError("ReadMessage", ErrWrongNetwork, str)
 	}
 
 	// Check for malformed commands.
 	command := hdr.command
 	if !utf8.ValidString(command) {
-		discardInput(r, uint64(hdr.length))
+		discardInput(r, length)
 
 		str := fmt.Sprintf("invalid command %v", []byte(command))
 
-		return totalBytes, nil, nil, messageError("ReadMessage", str)
+		return nil, messageError("ReadMessage", ErrInvalidCommand, str)
 	}
 
 	// Create struct of the appropriate message type based on the command.
-	msg, err := makeEmptyMessage(command)
+	msg, err := p.makeEmptyMessage(command)
 	if err != nil {
-		discardInput(r, uint64(hdr.length))
+		discardInput(r, length)
 
-		return totalBytes, nil, nil, messageError("ReadMessage",
-			err.Error())
+		return nil, messageError("ReadMessage", ErrUnknownCommand, err.Error())
 	}
 
 	// Check for maximum length based on the message type as a malicious transactionHandler
 	// could otherwise create a well-formed header and set the length to max
 	// numbers to exhaust the machine's memory.
-	mpl := msg.MaxPayloadLength(pver)
-	if uint64(hdr.length) > mpl || hdr.extLength > mpl {
-		discardInput(r, uint64(hdr.length))
+	mpl := p.maxPayloadLength(msg)
+	if length > mpl {
+		discardInput(r, length)
 		str := fmt.Sprintf("payload exceeds max length - header "+
-			"indicates %v bytes (%v extended bytes), but max payload size for "+
-			"messages of type [%v] is %v.", hdr.length, hdr.extLength, command, mpl)
+			"indicates %v bytes, but max payload size for "+
+			"messages of type [%v] is %v.", length, command, mpl)
 
-		return totalBytes, nil, nil, messageError("ReadMessage", str)
+		return nil, messageError("ReadMessage", ErrPayloadTooLarge, str)
 	}
 
-	// Read payload.
-	length := uint64(hdr.length)
-	if length == 0xffffffff {
-		length = hdr.extLength
+	return msg, nil
+}
+
+// readPayload validates hdr and reads and parses the payload which follows it
+// from r using the provided codec settings.  hdrBytes is the number of header
+// bytes already read, which is included in the returned byte count.
+func (c *Codec) readPayload(r io.Reader, hdr *messageHeader, hdrBytes int, p codecParams) (int, Message, []byte, error) {
+	ob := c.observe(DirectionRead, hdr.command)
+
+	n, msg, payload, err := c.readAndDecode(r, hdr, hdrBytes, p, ob)
+	ob.end(hdrBytes, n-hdrBytes, err)
+
+	return n, msg, payload, err
+}
+
+// readAndDecode implements readPayload, timing the decoding for ob.
+func (c *Codec) readAndDecode(r io.Reader, hdr *messageHeader, hdrBytes int, p codecParams,
+	ob *observation,
+) (int, Message, []byte, error) {
+	totalBytes := hdrBytes
+	length := hdr.payloadLength()
+
+	msg, err := p.checkHeader(r, hdr)
+	if err != nil {
+		return totalBytes, nil, nil, err
 	}
 
 	// check whether an external handler has been registered for this message type
-	if externalHandler[hdr.command] != nil {
-		return externalHandler[hdr.command](r, length, totalBytes)
+	if handler := c.handler(hdr.command); handler != nil {
+		return handler(r, length, totalBytes)
 	}
 
+	// Read payload.  When decoding from an in-memory buffer the payload
+	// aliases the buffer rather than being copied.
 	// this is VERY bad, reading the whole message into memory, instead of processing it in a streaming fashion
-	payload := make([]byte, length)
-	n, err = io.ReadFull(r, payload)
+	var (
+		payload []byte
+		n       int
+	)
+
+	sr, inMemory := r.(*sliceReader)
+	if inMemory {
+		payload, err = sr.next(length)
+		n = len(payload)
+
+		if payload == nil {
+			payload = []byte{}
+		}
+	} else {
+		payload = make([]byte, length)
+		n, err = io.ReadFull(r, payload)
+	}
+
 	totalBytes += n
 
 	if err != nil {
@@ -501,22 +661,51 @@ func ReadMessageWithEncodingN(r io.Reader, pver uint32, bsvnet BitcoinNet, enc M
 	// For extended format messages, the checksum will be set to 0x00000000 and not checked by receivers.
 	// This is due to the long time required to calculate and verify the checksum for very large
 	// data sets, and the limited utility of such a checksum.
-	if length != 0xffffffff && hdr.extLength == 0 {
+	raw, isRaw := msg.(*MsgRaw)
+	var checksumOK bool
+
+	if !hdr.extended {
 		checksum := chainhash.DoubleHashB(payload)[0:4]
-		if !bytes.Equal(checksum, hdr.checksum[:]) {
-			str := fmt.Sprintf("payload checksum failed - header "+
-				"indicates %v, but actual checksum is %v.",
-				hdr.checksum, checksum)
+		checksumOK = bytes.Equal(checksum, hdr.checksum[:])
 
-			return totalBytes, nil, nil, messageError("ReadMessage", str)
+		if !checksumOK && !isRaw {
+			return totalBytes, nil, nil, checksumError(hdr, checksum)
 		}
 	}
 
-	// Unmarshal message.  NOTE: This must be a *bytes.Buffer since the
-	// MsgVersion Bsvdecode function requires it.
-	pr := bytes.NewBuffer(payload)
+	ob.begin()
+
+	// Hand the payload to the read interceptors, if any.
+	if chain := c.readChain(hdr.command, decodeFrame); chain != nil {
+		f := newFrame(hdr, p, msg)
+		f.Payload = payload
+		f.consumed = true
+		f.checksumOK = checksumOK
+		f.alias = inMemory
+
+		if msg, err = chain(f); err != nil {
+			return totalBytes, nil, nil, err
+		}
+
+		return totalBytes, msg, f.Payload, nil
+	}
+
+	// Raw messages carry the payload as is.
+	if isRaw {
+		raw.Payload = payload
+		raw.ChecksumVerified = checksumOK
+
+		return totalBytes, raw, payload, nil
+	}
+
+	// Unmarshal message, letting messages which support it alias the
+	// in-memory buffer.
+	if d, ok := msg.(aliasDecoder); ok && inMemory {
+		err = decodeAliasPayload(d, hdr.command, payload, p)
+	} else {
+		err = decodePayload(msg, payload, p)
+	}
 
-	err = msg.Bsvdecode(pr, pver, enc)
 	if err != nil {
 		return totalBytes, nil, nil, err
 	}
@@ -542,7 +731,7 @@ func ReadMessageWithEncodingN(r io.Reader, pver uint32, bsvnet BitcoinNet, enc M
 //     declared length regardless of success or error (see point 3).
 //
 //  2. Checksum is verified incrementally. For messages on the checksummed path
-//     (length != 0xffffffff && hdr.extLength == 0) the reader is additionally
+//     (all messages not using the extended header) the reader is additionally
 //     wrapped in io.TeeReader feeding a sha256.Hash. After Bsvdecode returns,
 //     the double-SHA256 checksum is computed over the tee'd bytes and compared
 //     against the header checksum field. Mismatch returns a *MessageError with
@@ -556,95 +745,109 @@ func ReadMessageWithEncodingN(r io.Reader, pver uint32, bsvnet BitcoinNet, enc M
 //  4. External handlers registered via SetExternalHandler take priority over
 //     the streaming path. When a handler is registered for the message command,
 //     it is invoked and its (n, Message, error) values are returned directly.
-//     The []byte return from the handler signature is dropped.
+//     The []byte return from the handler signature is dropped.  Read
+//     interceptors added via Codec.InterceptRead run next, and any part of
+//     the payload they leave unread is discarded.
 //
 //  5. CmdVersion ("version") is explicitly rejected. MsgVersion.Bsvdecode
 //     type-asserts its reader argument to *bytes.Buffer. Passing a generic
 //     io.Reader would cause an immediate error or panic. Callers that need to
 //     decode version messages must use ReadMessageWithEncodingN.
 func ReadMessageStreamingN(r io.Reader, pver uint32, bsvnet BitcoinNet, enc MessageEncoding) (int, Message, error) {
-	totalBytes := 0
-	n, hdr, err := readMessageHeader(r)
-	totalBytes += n
+	return defaultCodec.readMessageStreaming(r, codecParams{bsvnet: bsvnet, pver: pver, enc: enc})
+}
 
+// readMessageStreaming reads, validates, and parses the next bitcoin Message
+// from r using the provided codec settings without buffering the payload.
+func (c *Codec) readMessageStreaming(r io.Reader, p codecParams) (int, Message, error) {
+	n, hdr, err := readMessageHeader(r)
 	if err != nil {
-		return totalBytes, nil, err
-	}
-
-	// Enforce maximum message payload.
-	if uint64(hdr.length) > maxMessagePayload() || hdr.extLength > maxMessagePayload() {
-		str := fmt.Sprintf("message payload is too large - header "+
-			"indicates %d bytes (%d extended bytes), but max message payload is %d "+
-			"bytes.", hdr.length, hdr.extLength, maxMessagePayload())
-
-		return totalBytes, nil, messageError("ReadMessage", str)
+		return n, nil, err
 	}
 
-	// Check for messages from the wrong bitcoin network.
-	if hdr.magic != bsvnet {
-		discardInput(r, uint64(hdr.length))
-		str := fmt.Sprintf("message from other network [%v]", hdr.magic)
+	ob := c.observe(DirectionRead, hdr.command)
 
-		return totalBytes, nil, messageError("ReadMessage", str)
-	}
+	totalBytes, msg, err := c.streamAndDecode(r, hdr, n, p, ob)
+	ob.end(n, totalBytes-n, err)
 
-	// Check for malformed commands.
-	command := hdr.command
-	if !utf8.ValidString(command) {
-		discardInput(r, uint64(hdr.length))
-
-		str := fmt.Sprintf("invalid command %v", []byte(command))
+	return totalBytes, msg, err
+}
 
-		return totalBytes, nil, messageError("ReadMessage", str)
+// streamAndDecode validates hdr and parses the payload which follows it from r
+// using the provided codec settings without buffering it, timing the decoding
+// for ob.  hdrBytes is the number of header bytes already read, which is
+// included in the returned byte count.
+func (c *Codec) streamAndDecode(r io.Reader, hdr *messageHeader, hdrBytes int, p codecParams,
+	ob *observation,
+) (int, Message, error) {
+	totalBytes := hdrBytes
+	length := hdr.payloadLength()
+
+	msg, err := p.checkHeader(r, hdr)
+	if err != nil {
+		return totalBytes, nil, err
 	}
 
 	// Reject CmdVersion explicitly. MsgVersion.Bsvdecode type-asserts its
 	// reader to *bytes.Buffer; streaming a generic io.Reader into it returns
 	// an immediate error. Callers needing version message decoding must use
 	// ReadMessageWithEncodingN, which buffers the full payload.
-	if command == CmdVersion {
-		discardInput(r, uint64(hdr.length))
+	if hdr.command == CmdVersion {
+		discardInput(r, length)
 		str := "ReadMessageStreamingN does not support CmdVersion; " +
 			"use ReadMessageWithEncodingN for version messages"
 
-		return totalBytes, nil, messageError("ReadMessage", str)
+		return totalBytes, nil, messageError("ReadMessage", ErrUnsupported, str)
 	}
 
-	// Create struct of the appropriate message type based on the command.
-	msg, err := makeEmptyMessage(command)
-	if err != nil {
-		discardInput(r, uint64(hdr.length))
-
-		return totalBytes, nil, messageError("ReadMessage", err.Error())
+	// Delegate to external handler if one is registered.
+	if handler := c.handler(hdr.command); handler != nil {
+		n, extMsg, _, extErr := handler(r, length, totalBytes)
+		return n, extMsg, extErr
 	}
 
-	// Check for maximum length based on the message type.
-	mpl := msg.MaxPayloadLength(pver)
-	if uint64(hdr.length) > mpl || hdr.extLength > mpl {
-		discardInput(r, uint64(hdr.length))
-		str := fmt.Sprintf("payload exceeds max length - header "+
-			"indicates %v bytes (%v extended bytes), but max payload size for "+
-			"messages of type [%v] is %v.", hdr.length, hdr.extLength, command, mpl)
+	ob.begin()
 
-		return totalBytes, nil, messageError("ReadMessage", str)
-	}
+	// Hand the payload to the read interceptors, if any.  Whatever part of
+	// the payload they leave unread is discarded so r stays aligned.
+	if chain := c.readChain(hdr.command, decodeFrame); chain != nil {
+		lr := &io.LimitedReader{R: r, N: int64(length)}
 
-	// Determine effective payload length.
-	length := uint64(hdr.length)
-	if length == 0xffffffff {
-		length = hdr.extLength
+		f := newFrame(hdr, p, msg)
+		f.r = lr
+
+		msg, err = chain(f)
+
+		_, _ = io.Copy(io.Discard, lr)
+		totalBytes += int(int64(length) - lr.N)
+
+		if err != nil {
+			return totalBytes, nil, err
+		}
+
+		return totalBytes, msg, nil
 	}
 
-	// Delegate to external handler if one is registered.
-	if externalHandler[command] != nil {
-		n, extMsg, _, extErr := externalHandler[command](r, length, totalBytes)
-		return n, extMsg, extErr
+	n, err := decodeStreamingPayload(r, hdr, msg, p)
+	totalBytes += n
+
+	if err != nil {
+		return totalBytes, nil, err
 	}
 
+	return totalBytes, msg, nil
+}
+
+// decodeStreamingPayload decodes the payload announced by hdr from r into msg
+// using the provided codec settings without buffering it, and verifies its
+// checksum unless it uses the extended header.  It returns the number of
+// payload bytes read and always leaves r positioned after the payload.
+func decodeStreamingPayload(r io.Reader, hdr *messageHeader, msg Message, p codecParams) (int, error) {
+	length := hdr.payloadLength()
+
 	// Determine whether this message uses the checksummed path. Extended
-	// format messages (length == 0xffffffff in the raw header || extLength != 0)
-	// do not carry a meaningful checksum.
-	verifyChecksum := length != 0xffffffff && hdr.extLength == 0
+	// format messages do not carry a meaningful checksum.
+	verifyChecksum := !hdr.extended
 
 	// Bind reads from r to the declared payload length. limited.N starts at
 	// int64(length) and decrements with each read; (length - limited.N) gives
@@ -669,21 +872,20 @@ func ReadMessageStreamingN(r io.Reader, pver uint32, bsvnet BitcoinNet, enc Mess
 		_, _ = io.Copy(io.Discard, limited)
 	}()
 
-	if err = msg.Bsvdecode(src, pver, enc); err != nil {
-		totalBytes += int(int64(length) - limited.N)
-		return totalBytes, nil, err
+	tr := &tracedReader{Reader: src, decodeLimits: p.limits(), recycling: recycling(p.pooled)}
+	if err := msg.Bsvdecode(tr, p.pver, p.enc); err != nil {
+		consumed := int(int64(length) - limited.N)
+		return consumed, newDecodeError(hdr.command, err, nil, consumed, &tr.fieldPath)
 	}
 
 	// Drain remaining bytes through src (tee'd into h) so all payload bytes
 	// are included in the checksum, even if Bsvdecode did not consume them all.
 	if _, copyErr := io.Copy(io.Discard, src); copyErr != nil && !errors.Is(copyErr, io.EOF) {
-		totalBytes += int(int64(length) - limited.N)
-		return totalBytes, nil, copyErr
+		return int(int64(length) - limited.N), copyErr
 	}
 
 	// At this point limited.N should be 0 (all bytes consumed via src).
 	// The deferred drain on limited is a no-op.
-	totalBytes += int(length)
 
 	// Verify checksum after all payload bytes have been tee'd.
 	if verifyChecksum {
@@ -693,16 +895,14 @@ func ReadMessageStreamingN(r io.Reader, pver uint32, bsvnet BitcoinNet, enc Mess
 		outer := sha256.Sum256(inner)
 		checksum := outer[:4]
 
-		if !bytes.Equal(checksum, hdr.checksum[:]) {
-			str := fmt.Sprintf("payload checksum failed - header "+
-				"indicates %v, but actual checksum is %v.",
-				hdr.checksum, checksum)
-
-			return totalBytes, nil, messageError("ReadMessage", str)
+		if raw, ok := msg.(*MsgRaw); ok {
+			raw.ChecksumVerified = bytes.Equal(checksum, hdr.checksum[:])
+		} else if !bytes.Equal(checksum, hdr.checksum[:]) {
+			return int(length), checksumError(hdr, checksum)
 		}
 	}
 
-	return totalBytes, msg, nil
+	return int(length), nil
 }
 
 // ReadMessageN reads, validates, and parses the next bitcoin Message from r for
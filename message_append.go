@@ -0,0 +1,76 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"sync"
+)
+
+// AppendEncoder is implemented by messages which can append their bitcoin
+// protocol encoding to a byte slice, which avoids going through an io.Writer
+// such as a bytes.Buffer when encoding into a preallocated buffer or a
+// database value.  Every message implemented by this package implements it.
+type AppendEncoder interface {
+	AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error)
+}
+
+// appendWriter is an io.Writer which appends everything written to it to a
+// byte slice.  The typed encoding primitives append to it directly.
+type appendWriter struct {
+	b []byte
+}
+
+// Write appends p to the buffer.  It is part of the io.Writer interface
+// implementation and never fails.
+func (w *appendWriter) Write(p []byte) (int, error) {
+	w.b = append(w.b, p...)
+	return len(p), nil
+}
+
+// appendWriters is a pool of appendWriters.  Since messages encode to an
+// io.Writer, the writer escapes to the heap, so pooling them is what keeps
+// repeated encoding into a reused slice allocation-free.
+var appendWriters = sync.Pool{
+	New: func() any { return new(appendWriter) },
+}
+
+// appendBsvEncode appends the bitcoin protocol encoding of msg to dst and
+// returns the extended buffer.  When msg is a PayloadSizer, dst is grown to
+// fit its encoding up front so at most one allocation takes place.  On
+// failure dst is returned unextended along with the error.
+func appendBsvEncode(dst []byte, msg Message, pver uint32, enc MessageEncoding) ([]byte, error) {
+	if ps, ok := msg.(PayloadSizer); ok {
+		dst = growExact(dst, ps.PayloadSize(pver))
+	}
+
+	aw := appendWriters.Get().(*appendWriter)
+	aw.b = dst
+
+	err := msg.BsvEncode(aw, pver, enc)
+	b := aw.b
+
+	aw.b = nil
+	appendWriters.Put(aw)
+
+	if err != nil {
+		return dst, err
+	}
+
+	return b, nil
+}
+
+// growExact returns dst with room for at least n more bytes.  Unlike
+// slices.Grow it does not round the capacity up, so a buffer sized for a
+// single encoding is not larger than needed.
+func growExact(dst []byte, n int) []byte {
+	if n <= cap(dst)-len(dst) {
+		return dst
+	}
+
+	grown := make([]byte, len(dst), len(dst)+n)
+	copy(grown, dst)
+
+	return grown
+}
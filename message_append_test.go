@@ -0,0 +1,91 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAppendBsvEncode ensures every message appends the same encoding
+// BsvEncode writes and leaves the existing contents of the buffer alone.
+func TestAppendBsvEncode(t *testing.T) {
+	samples := sampleMessages(t)
+
+	for _, cmd := range RegisteredCommands() {
+		msg, ok := samples[cmd]
+		require.True(t, ok, "no sample message for %s", cmd)
+
+		enc, ok := msg.(AppendEncoder)
+		require.True(t, ok, "%s does not implement AppendEncoder", cmd)
+
+		var buf bytes.Buffer
+		require.NoError(t, msg.BsvEncode(&buf, ProtocolVersion, BaseEncoding), cmd)
+
+		got, err := enc.AppendBsvEncode([]byte("prefix"), ProtocolVersion, BaseEncoding)
+		require.NoError(t, err, cmd)
+		assert.Equal(t, append([]byte("prefix"), buf.Bytes()...), got, cmd)
+	}
+
+	// Failures leave the buffer unextended.
+	dst := []byte("prefix")
+
+	got, err := NewMsgPing(1).AppendBsvEncode(dst, BIP0031Version-1, BaseEncoding)
+	require.NoError(t, err)
+	assert.Equal(t, dst, got)
+
+	got, err = NewMsgFilterAdd(make([]byte, MaxFilterAddDataSize+1)).AppendBsvEncode(dst, ProtocolVersion, BaseEncoding)
+	require.Error(t, err)
+	assert.Equal(t, dst, got)
+}
+
+// TestAppendSerialize ensures transactions, blocks and block headers append
+// the same encoding Serialize writes, and that appending into a reused buffer
+// does not allocate.
+func TestAppendSerialize(t *testing.T) {
+	tests := []struct {
+		name      string
+		serialize func(buf *bytes.Buffer) error
+		append    func(dst []byte) ([]byte, error)
+	}{
+		{
+			"MsgTx",
+			func(buf *bytes.Buffer) error { return multiTx.Serialize(buf) },
+			multiTx.AppendSerialize,
+		},
+		{
+			"MsgBlock",
+			func(buf *bytes.Buffer) error { return blockOne.Serialize(buf) },
+			blockOne.AppendSerialize,
+		},
+		{
+			"BlockHeader",
+			func(buf *bytes.Buffer) error { return blockOne.Header.Serialize(buf) },
+			func(dst []byte) ([]byte, error) { return blockOne.Header.AppendSerialize(dst), nil },
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, test.serialize(&buf))
+
+			got, err := test.append(nil)
+			require.NoError(t, err)
+			assert.Equal(t, buf.Bytes(), got)
+			assert.Equal(t, buf.Len(), cap(got), "buffer not sized exactly")
+
+			dst := make([]byte, 0, buf.Len())
+			allocs := testing.AllocsPerRun(100, func() {
+				dst, _ = test.append(dst[:0])
+			})
+			assert.Zero(t, allocs)
+			assert.Equal(t, buf.Bytes(), dst)
+		})
+	}
+}
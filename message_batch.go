@@ -0,0 +1,267 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+)
+
+// BatchError describes a failed batched write and identifies the message
+// which caused it by its index within the batch.
+type BatchError struct {
+	Index int   // Index of the message within the batch
+	Err   error // Underlying error
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("message %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BatchWriter frames several messages up front and writes them to a writer at
+// once, which saves a write call per message when relaying bursts of small
+// messages such as inv, tx and headers.
+//
+// Messages are framed exactly like WriteMessageWithEncodingN frames them when
+// they are added.  The header and payload of every message are kept as
+// separate buffers and, when the writer is a *net.TCPConn or *net.UnixConn,
+// flushed with a single vectored write (writev) via net.Buffers.  Other writers
+// receive the batch coalesced into a single Write call.
+//
+// A BatchWriter is not safe for concurrent access.
+type BatchWriter struct {
+	codec *Codec
+	p     *codecParams
+	bufs  net.Buffers
+	sizes []int
+	obs   []*observation
+}
+
+// NewBatchWriter returns an empty BatchWriter which frames messages for the
+// provided protocol version, bitcoin network and encoding using the
+// package-wide limits.
+func NewBatchWriter(pver uint32, bsvnet BitcoinNet, enc MessageEncoding) *BatchWriter {
+	return &BatchWriter{
+		codec: defaultCodec,
+		p:     &codecParams{bsvnet: bsvnet, pver: pver, enc: enc},
+	}
+}
+
+// NewBatchWriter returns an empty BatchWriter which frames messages using the
+// settings of the codec at the time each message is added.
+func (c *Codec) NewBatchWriter() *BatchWriter {
+	return &BatchWriter{codec: c}
+}
+
+// WriteMessagesN frames msgs for the provided protocol version, bitcoin network
+// and encoding and writes them to w at once.  See Codec.WriteBatch for
+// details.
+func WriteMessagesN(w io.Writer, msgs []Message, pver uint32, bsvnet BitcoinNet,
+	enc MessageEncoding,
+) ([]int, error) {
+	return NewBatchWriter(pver, bsvnet, enc).write(w, msgs)
+}
+
+// WriteBatch frames msgs using the settings of the codec and writes them to w
+// at once.  It returns the number of bytes written for each message.
+//
+// When a message cannot be framed nothing is written and a *BatchError
+// identifying it is returned.  When the write fails, the *BatchError
+// identifies the first message which was not written completely, and the
+// returned counts show how far the write got.
+func (c *Codec) WriteBatch(w io.Writer, msgs ...Message) ([]int, error) {
+	return c.NewBatchWriter().write(w, msgs)
+}
+
+// write adds msgs to the batch and flushes it to w.
+func (b *BatchWriter) write(w io.Writer, msgs []Message) ([]int, error) {
+	for i, msg := range msgs {
+		if err := b.Add(msg); err != nil {
+			return make([]int, len(msgs)), &BatchError{Index: i, Err: err}
+		}
+	}
+
+	return b.Flush(w)
+}
+
+// Add frames msg and appends it to the batch.  An error is returned, and the
+// batch left unchanged, when msg cannot be framed.
+//
+// The write interceptors of the codec apply to msg.  The writer they are
+// passed collects the bytes of the message for the batch, so whatever they
+// write is flushed along with the other messages.
+func (b *BatchWriter) Add(msg Message) error {
+	p := b.params()
+
+	ob := b.codec.observe(DirectionWrite, msg.Command())
+	ob.begin()
+
+	var (
+		hw, payload []byte
+		hdrBytes    int
+		err         error
+	)
+
+	if chain := b.codec.writeChain(msg.Command(), p, writeBuffered); chain != nil {
+		var buf bytes.Buffer
+
+		_, err = chain(&buf, msg)
+		hw = buf.Bytes()
+		hdrBytes = writtenHeaderBytes(len(hw), p)
+	} else {
+		hw, payload, err = frameMessage(msg, p)
+		hdrBytes = len(hw)
+	}
+
+	if err != nil {
+		ob.end(0, 0, err)
+		return err
+	}
+
+	if ob != nil {
+		ob.stop()
+		ob.ev.HeaderBytes = hdrBytes
+	}
+
+	b.bufs = append(b.bufs, hw, payload)
+	b.sizes = append(b.sizes, len(hw)+len(payload))
+	b.obs = append(b.obs, ob)
+
+	return nil
+}
+
+// params returns the settings used to frame messages.
+func (b *BatchWriter) params() codecParams {
+	if b.p != nil {
+		return *b.p
+	}
+
+	return b.codec.snapshot()
+}
+
+// Len returns the number of messages in the batch.
+func (b *BatchWriter) Len() int {
+	return len(b.sizes)
+}
+
+// Size returns the total number of bytes of the framed messages in the batch.
+func (b *BatchWriter) Size() int {
+	total := 0
+	for _, size := range b.sizes {
+		total += size
+	}
+
+	return total
+}
+
+// Reset discards all messages in the batch.
+func (b *BatchWriter) Reset() {
+	clear(b.bufs)
+	clear(b.obs)
+	b.bufs = b.bufs[:0]
+	b.sizes = b.sizes[:0]
+	b.obs = b.obs[:0]
+}
+
+// Flush writes every message in the batch to w and resets the batch.  It
+// returns the number of bytes written for each message, in the order the
+// messages were added.  When the write fails, a *BatchError identifies the
+// first message which was not written completely.
+func (b *BatchWriter) Flush(w io.Writer) ([]int, error) {
+	if w == nil {
+		return nil, errors.New("writer must not be nil") //nolint:err113 // needs refactoring
+	}
+
+	defer b.Reset()
+
+	counts := make([]int, len(b.sizes))
+	if len(b.sizes) == 0 {
+		return counts, nil
+	}
+
+	var (
+		n   int64
+		err error
+	)
+
+	switch w.(type) {
+	case *net.TCPConn, *net.UnixConn:
+		// WriteTo consumes the buffers it writes, so write a copy of the
+		// slice headers to keep b.bufs intact for Reset.
+		bufs := append(net.Buffers(nil), b.bufs...)
+		n, err = bufs.WriteTo(w)
+
+	default:
+		buf := make([]byte, 0, b.Size())
+		for _, part := range b.bufs {
+			buf = append(buf, part...)
+		}
+
+		var written int
+
+		written, err = w.Write(buf)
+		n = int64(written)
+	}
+
+	// Attribute the written bytes to the messages in order.
+	failed := -1
+
+	for i, size := range b.sizes {
+		counts[i] = int(min(n, int64(size)))
+		n -= int64(counts[i])
+
+		if counts[i] < size && failed < 0 {
+			failed = i
+		}
+	}
+
+	var batchErr *BatchError
+
+	switch {
+	case err != nil && failed < 0:
+		batchErr = &BatchError{Index: len(counts) - 1, Err: err}
+
+	case err != nil:
+		batchErr = &BatchError{Index: failed, Err: err}
+
+	case failed >= 0:
+		batchErr = &BatchError{Index: failed, Err: io.ErrShortWrite}
+	}
+
+	b.observeFlush(counts, batchErr)
+
+	if batchErr != nil {
+		return counts, batchErr
+	}
+
+	return counts, nil
+}
+
+// observeFlush notifies the observers of the flushed messages, attributing
+// batchErr, if any, to the message it identifies and those after it.
+func (b *BatchWriter) observeFlush(counts []int, batchErr *BatchError) {
+	for i, ob := range b.obs {
+		if ob == nil {
+			continue
+		}
+
+		var err error
+		if batchErr != nil && i >= batchErr.Index {
+			err = batchErr.Err
+		}
+
+		hdrBytes := min(counts[i], ob.ev.HeaderBytes)
+		ob.end(hdrBytes, counts[i]-hdrBytes, err)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCounter is an io.Writer which records every write it receives and
+// fails once more than limit bytes have been written, after writing as many
+// bytes as fit.  A negative limit never fails.
+type writeCounter struct {
+	buf    bytes.Buffer
+	writes int
+	limit  int
+}
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	w.writes++
+
+	if w.limit >= 0 && w.buf.Len()+len(p) > w.limit {
+		n, _ := w.buf.Write(p[:w.limit-w.buf.Len()])
+		return n, io.ErrClosedPipe
+	}
+
+	return w.buf.Write(p)
+}
+
+// batchTestMessages returns the messages used by the batch tests along with
+// their expected encoding.
+func batchTestMessages(t *testing.T) ([]Message, [][]byte) {
+	t.Helper()
+
+	msgs := []Message{NewMsgPing(1), multiTx, NewMsgVerAck(), &blockOne}
+	frames := make([][]byte, len(msgs))
+
+	for i, msg := range msgs {
+		var buf bytes.Buffer
+
+		_, err := WriteMessageWithEncodingN(&buf, msg, ProtocolVersion, MainNet, BaseEncoding)
+		require.NoError(t, err)
+
+		frames[i] = buf.Bytes()
+	}
+
+	return msgs, frames
+}
+
+// TestWriteMessagesN ensures a batch produces exactly the same bytes as
+// writing the messages individually, using a single write.
+func TestWriteMessagesN(t *testing.T) {
+	msgs, frames := batchTestMessages(t)
+
+	w := &writeCounter{limit: -1}
+
+	counts, err := WriteMessagesN(w, msgs, ProtocolVersion, MainNet, BaseEncoding)
+	require.NoError(t, err)
+	assert.Equal(t, bytes.Join(frames, nil), w.buf.Bytes())
+	assert.Equal(t, 1, w.writes)
+
+	for i, frame := range frames {
+		assert.Equal(t, len(frame), counts[i])
+	}
+
+	// The codec variant applies the codec settings.
+	codec := NewCodec(MainNet, ProtocolVersion)
+	codec.SetExtendedMessages(true)
+
+	var got bytes.Buffer
+
+	_, err = codec.WriteBatch(&got, NewMsgVerAck())
+	require.NoError(t, err)
+	assert.Len(t, got.Bytes(), ExtendedMessageHeaderSize)
+}
+
+// TestBatchWriter ensures messages accumulate until flushed and the batch is
+// reset afterwards.
+func TestBatchWriter(t *testing.T) {
+	msgs, frames := batchTestMessages(t)
+
+	b := NewCodec(MainNet, ProtocolVersion).NewBatchWriter()
+
+	for _, msg := range msgs {
+		require.NoError(t, b.Add(msg))
+	}
+
+	assert.Equal(t, len(msgs), b.Len())
+	assert.Equal(t, len(bytes.Join(frames, nil)), b.Size())
+
+	// A message which cannot be framed leaves the batch unchanged.
+	var msgErr *MessageError
+	require.ErrorAs(t, b.Add(&fakeMessage{command: "somethingtoolong"}), &msgErr)
+	assert.Equal(t, len(msgs), b.Len())
+
+	var got bytes.Buffer
+
+	_, err := b.Flush(&got)
+	require.NoError(t, err)
+	assert.Equal(t, bytes.Join(frames, nil), got.Bytes())
+	assert.Equal(t, 0, b.Len())
+
+	// An empty batch writes nothing.
+	counts, err := b.Flush(&got)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+
+	_, err = b.Flush(nil)
+	require.Error(t, err)
+
+	// Reset discards the batch.
+	require.NoError(t, b.Add(NewMsgVerAck()))
+	b.Reset()
+	assert.Equal(t, 0, b.Len())
+}
+
+// TestWriteMessagesNErrors ensures errors are attributed to the message which
+// caused them.
+func TestWriteMessagesNErrors(t *testing.T) {
+	msgs, frames := batchTestMessages(t)
+
+	// A message which cannot be framed prevents the whole batch.
+	w := &writeCounter{limit: -1}
+	bad := append(append([]Message{}, msgs[:2]...), &fakeMessage{command: "fake", forceEncodeErr: true})
+
+	_, err := WriteMessagesN(w, bad, ProtocolVersion, MainNet, BaseEncoding)
+
+	var batchErr *BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 2, batchErr.Index)
+	assert.Equal(t, 0, w.writes)
+
+	// A failing write reports the first incomplete message along with the
+	// bytes written for each message.
+	limit := len(frames[0]) + len(frames[1]) + 5
+	w = &writeCounter{limit: limit}
+
+	counts, err := WriteMessagesN(w, msgs, ProtocolVersion, MainNet, BaseEncoding)
+	require.ErrorAs(t, err, &batchErr)
+	require.ErrorIs(t, err, io.ErrClosedPipe)
+	assert.Equal(t, 2, batchErr.Index)
+	assert.Equal(t, []int{len(frames[0]), len(frames[1]), 5, 0}, counts)
+	assert.Equal(t, "message 2: io: read/write on closed pipe", batchErr.Error())
+
+	// A write which fails after writing everything is attributed to the
+	// last message.
+	counts, err = WriteMessagesN(&erroringWriter{}, msgs[:1], ProtocolVersion, MainNet, BaseEncoding)
+	require.ErrorAs(t, err, &batchErr)
+	assert.Equal(t, 0, batchErr.Index)
+	assert.Equal(t, []int{len(frames[0])}, counts)
+}
+
+// erroringWriter is an io.Writer which accepts every byte but still reports
+// an error.
+type erroringWriter struct{}
+
+func (erroringWriter) Write(p []byte) (int, error) {
+	return len(p), errors.New("late failure")
+}
+
+// TestWriteMessagesNTCP ensures batches written to a TCP connection, which
+// uses a vectored write, read back intact.
+func TestWriteMessagesNTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("unable to listen on loopback: %v", err)
+	}
+	defer ln.Close()
+
+	msgs, frames := batchTestMessages(t)
+
+	go func() {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		_, _ = WriteMessagesN(conn, msgs, ProtocolVersion, MainNet, BaseEncoding)
+	}()
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	got, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, bytes.Join(frames, nil), got)
+}
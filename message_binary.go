@@ -0,0 +1,103 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"io"
+	"math"
+)
+
+// The functions in this file back the encoding.BinaryMarshaler,
+// encoding.BinaryUnmarshaler, io.WriterTo and io.ReaderFrom implementations of
+// the messages and of BlockHeader, OutPoint, NetAddress and InvVect.  They all
+// use the bitcoin protocol encoding at the latest protocol version, so
+// MarshalBinary produces the same bytes as BsvEncode with ProtocolVersion and
+// LatestEncoding and the values can be stored, hashed or copied with the
+// standard library without going through a message header.
+
+// marshalMessage returns the bitcoin protocol encoding of msg at the latest
+// protocol version.
+func marshalMessage(msg Message) ([]byte, error) {
+	return appendBsvEncode(nil, msg, ProtocolVersion, LatestEncoding)
+}
+
+// unmarshalMessage decodes the bitcoin protocol encoding of msg at the latest
+// protocol version from data.  Decoding failures are returned as a
+// *DecodeError like for the read functions, and data must not hold anything
+// after the encoding.
+func unmarshalMessage(msg Message, data []byte) error {
+	rest, err := decodePayloadRest(msg, data, codecParams{pver: ProtocolVersion, enc: LatestEncoding})
+	if err != nil {
+		return err
+	}
+
+	return checkTrailing(rest, msg.Command()+" message")
+}
+
+// decodeExact decodes data with decode and fails when it leaves anything
+// unread.  what names the decoded value in the error.
+func decodeExact(data []byte, what string, decode func(r io.Reader) error) error {
+	buf := bytes.NewBuffer(data)
+	if err := decode(buf); err != nil {
+		return err
+	}
+
+	return checkTrailing(buf.Len(), what)
+}
+
+// checkTrailing returns an error when rest bytes were left after decoding
+// what.
+func checkTrailing(rest int, what string) error {
+	if rest == 0 {
+		return nil
+	}
+
+	str := fmt.Sprintf("%d trailing bytes after %s", rest, what)
+
+	return messageError("UnmarshalBinary", ErrMalformedMessage, str)
+}
+
+// writeTo writes the encoding returned by the MarshalBinary method of v to w
+// with a single call to w.Write, so nothing is written when encoding fails,
+// and returns the number of bytes written.
+func writeTo(v encoding.BinaryMarshaler, w io.Writer) (int64, error) {
+	b, err := v.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(b)
+
+	return int64(n), err
+}
+
+// readFrom reads r until EOF and decodes what it read with the UnmarshalBinary
+// method of v, returning the number of bytes read.  Since r must hold exactly
+// one encoding, reading stops with an error once more than limit bytes have
+// been read.
+func readFrom(v encoding.BinaryUnmarshaler, r io.Reader, limit uint64) (int64, error) {
+	// Read one byte past the limit to detect oversized input.
+	maxRead := int64(math.MaxInt64)
+	if limit < math.MaxInt64 {
+		maxRead = int64(limit) + 1
+	}
+
+	var buf bytes.Buffer
+
+	n, err := buf.ReadFrom(io.LimitReader(r, maxRead))
+	if err != nil {
+		return n, err
+	}
+
+	if uint64(n) > limit {
+		str := fmt.Sprintf("encoding exceeds the maximum of %d bytes", limit)
+		return n, messageError("ReadFrom", ErrPayloadTooLarge, str)
+	}
+
+	return n, v.UnmarshalBinary(buf.Bytes())
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// binaryCodec is the set of standard library interfaces implemented by the
+// messages and the other encodable types.
+type binaryCodec interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+	io.WriterTo
+	io.ReaderFrom
+}
+
+// TestMessageBinary ensures every message marshals to its encoding at the
+// latest protocol version and round-trips through UnmarshalBinary, WriteTo and
+// ReadFrom with exact byte counts.
+func TestMessageBinary(t *testing.T) {
+	for cmd, msg := range sampleMessages(t) {
+		t.Run(cmd, func(t *testing.T) {
+			var want bytes.Buffer
+			require.NoError(t, msg.BsvEncode(&want, ProtocolVersion, LatestEncoding))
+
+			bc, ok := msg.(binaryCodec)
+			require.True(t, ok)
+
+			got, err := bc.MarshalBinary()
+			require.NoError(t, err)
+			assert.Equal(t, want.Bytes(), got)
+
+			// WriteTo reports every byte written.
+			var buf bytes.Buffer
+
+			n, err := bc.WriteTo(&buf)
+			require.NoError(t, err)
+			assert.Equal(t, int64(want.Len()), n)
+			assert.Equal(t, want.Bytes(), buf.Bytes())
+
+			// UnmarshalBinary must reproduce the encoding.
+			decoded, err := makeEmptyMessage(cmd)
+			require.NoError(t, err)
+			require.NoError(t, decoded.(binaryCodec).UnmarshalBinary(got))
+
+			again, err := decoded.(binaryCodec).MarshalBinary()
+			require.NoError(t, err)
+			assert.Equal(t, got, again)
+
+			// ReadFrom reports every byte read.
+			read, err := makeEmptyMessage(cmd)
+			require.NoError(t, err)
+
+			n, err = read.(binaryCodec).ReadFrom(&buf)
+			require.NoError(t, err)
+			assert.Equal(t, int64(want.Len()), n)
+
+			again, err = read.(binaryCodec).MarshalBinary()
+			require.NoError(t, err)
+			assert.Equal(t, want.Bytes(), again)
+		})
+	}
+}
+
+// TestTypeBinary ensures the encodable types other than messages round-trip
+// through the standard library interfaces with exact byte counts.
+func TestTypeBinary(t *testing.T) {
+	header := blockOne.Header
+	na := NewNetAddressTimestamp(time.Unix(0x495fab29, 0), SFNodeNetwork,
+		net.ParseIP("127.0.0.1"), 8333)
+
+	tests := []struct {
+		name  string
+		in    binaryCodec
+		empty func() binaryCodec
+		size  int
+	}{
+		{"BlockHeader", &header, func() binaryCodec { return &BlockHeader{} }, blockHeaderLen},
+		{"OutPoint", NewOutPoint(&mainNetGenesisHash, 7), func() binaryCodec { return &OutPoint{} }, outPointLen},
+		{"NetAddress", na, func() binaryCodec { return &NetAddress{} }, 30},
+		{"InvVect", NewInvVect(InvTypeBlock, &mainNetGenesisHash), func() binaryCodec { return &InvVect{} }, maxInvVectPayload},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := test.in.MarshalBinary()
+			require.NoError(t, err)
+			require.Len(t, b, test.size)
+
+			decoded := test.empty()
+			require.NoError(t, decoded.UnmarshalBinary(b))
+			assert.Equal(t, test.in, decoded)
+
+			// Several values written back to back add up.
+			var buf bytes.Buffer
+
+			var total int64
+
+			for range 3 {
+				n, err := test.in.WriteTo(&buf)
+				require.NoError(t, err)
+
+				total += n
+			}
+
+			assert.Equal(t, int64(buf.Len()), total)
+
+			// ReadFrom consumes exactly one value.
+			read := test.empty()
+
+			n, err := read.ReadFrom(io.LimitReader(&buf, int64(test.size)))
+			require.NoError(t, err)
+			assert.Equal(t, int64(test.size), n)
+			assert.Equal(t, test.in, read)
+
+			// Trailing bytes are rejected.
+			err = decoded.UnmarshalBinary(append(b, 0x00))
+			require.ErrorIs(t, err, ErrMalformedMessage)
+
+			n, err = read.ReadFrom(&buf)
+			require.ErrorIs(t, err, ErrPayloadTooLarge)
+			assert.Equal(t, int64(test.size)+1, n)
+
+			// Truncated input is reported like by the read functions.
+			require.ErrorIs(t, decoded.UnmarshalBinary(b[:len(b)-1]), io.ErrUnexpectedEOF)
+		})
+	}
+}
+
+// TestMessageBinaryErrors ensures malformed input and failing writers are
+// reported with the number of bytes actually processed.
+func TestMessageBinaryErrors(t *testing.T) {
+	ping := NewMsgPing(123123)
+
+	b, err := ping.MarshalBinary()
+	require.NoError(t, err)
+
+	// Trailing bytes are rejected.
+	err = new(MsgPing).UnmarshalBinary(append(b, 0x01))
+	require.ErrorIs(t, err, ErrMalformedMessage)
+
+	// Decoding failures locate the failing field.
+	var de *DecodeError
+
+	err = new(MsgPing).UnmarshalBinary(b[:4])
+	require.ErrorAs(t, err, &de)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// ReadFrom counts what it read before failing.
+	n, err := new(MsgPing).ReadFrom(bytes.NewReader(b[:4]))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, int64(4), n)
+
+	// WriteTo counts what the writer accepted.
+	n, err = ping.WriteTo(newFixedWriter(4))
+	require.ErrorIs(t, err, io.ErrShortWrite)
+	assert.Equal(t, int64(0), n)
+
+	n, err = ping.WriteTo(erroringWriter{})
+	require.Error(t, err)
+	assert.Equal(t, int64(len(b)), n)
+
+	// Encoding failures write nothing.
+	var buf bytes.Buffer
+
+	inv := &MsgInv{InvList: make([]*InvVect, MaxInvPerMsg+1)}
+
+	n, err = inv.WriteTo(&buf)
+	require.Error(t, err)
+	assert.Equal(t, int64(0), n)
+	assert.Zero(t, buf.Len())
+
+	// Read errors are returned as is.
+	readErr := errors.New("read failure")
+
+	_, err = new(MsgPing).ReadFrom(io.MultiReader(bytes.NewReader(b[:2]), iotest.ErrReader(readErr)))
+	require.ErrorIs(t, err, readErr)
+}
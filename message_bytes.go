@@ -0,0 +1,142 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"io"
+)
+
+// sliceReader is an io.Reader over an in-memory buffer which is also able to
+// return portions of the buffer without copying them.  It allows the regular
+// decoding functions, such as ReadVarInt and readScriptLength, to be used
+// while decoding directly from a byte slice, so the same limits and
+// canonical encoding checks apply.  It records field paths for DecodeError and
+// carries the limits and pooling of the codec decoding from it.
+type sliceReader struct {
+	buf []byte
+	off int
+	fieldPath
+	decodeLimits
+	recycling
+}
+
+// Read reads up to len(p) bytes into p.  It is part of the io.Reader
+// interface implementation.
+func (r *sliceReader) Read(p []byte) (int, error) {
+	if r.off >= len(r.buf) {
+		if len(p) == 0 {
+			return 0, nil
+		}
+
+		return 0, io.EOF
+	}
+
+	n := copy(p, r.buf[r.off:])
+	r.off += n
+
+	return n, nil
+}
+
+// Len returns the number of unread bytes.
+func (r *sliceReader) Len() int {
+	return len(r.buf) - r.off
+}
+
+// next returns the next n bytes of the buffer without copying them and
+// advances past them.  The returned slice has cap == len so appending to it
+// never overwrites the remainder of the buffer, and next(0) returns nil.
+//
+// Mirroring io.ReadFull, io.EOF is returned when no bytes remain and
+// io.ErrUnexpectedEOF when fewer than n remain, in which case the remaining
+// bytes are consumed and returned.
+func (r *sliceReader) next(n uint64) ([]byte, error) {
+	if n == 0 {
+		return nil, nil
+	}
+
+	remaining := uint64(r.Len())
+	if remaining == 0 {
+		return nil, io.EOF
+	}
+
+	var err error
+	if n > remaining {
+		n, err = remaining, io.ErrUnexpectedEOF
+	}
+
+	start := r.off
+	end := start + int(n)
+	r.off = end
+
+	return r.buf[start:end:end], err
+}
+
+// aliasDecoder is implemented by messages which are able to decode from an
+// in-memory buffer with their variable length fields aliasing the buffer.
+type aliasDecoder interface {
+	decodeAlias(r *sliceReader, pver uint32) error
+}
+
+// DecodeMessageBytes validates and parses the bitcoin message at the start of
+// b for the provided protocol version and bitcoin network.  It performs the
+// same validation as ReadMessageWithEncodingN and returns the number of bytes
+// the message occupies in b in addition to the parsed Message and the raw
+// payload.  Bytes following the message are left untouched, so concatenated
+// messages are decoded by repeatedly advancing b by the returned count.
+//
+// Unlike ReadMessageWithEncodingN, nothing is copied out of b: the returned
+// payload is a sub-slice of b and the scripts of transactions and blocks alias
+// b as well (see MsgTx.FromBytes), so decoding even a very large block only
+// allocates the message structs themselves.  Fixed-size fields such as hashes
+// are stored by value and are therefore copied.
+//
+// The caller retains b: it must not be modified for as long as the returned
+// message or payload is in use, and any retained script keeps all of b from
+// being garbage collected.  Use MsgTx.Copy to detach a transaction from b.
+func DecodeMessageBytes(b []byte, pver uint32, bsvnet BitcoinNet, enc MessageEncoding) (int, Message, []byte, error) {
+	return defaultCodec.readMessage(&sliceReader{buf: b}, codecParams{bsvnet: bsvnet, pver: pver, enc: enc})
+}
+
+// DecodeBytes validates and parses the bitcoin message at the start of b using
+// the settings of the codec without copying out of b.  See DecodeMessageBytes
+// for details and the retain contract.
+func (c *Codec) DecodeBytes(b []byte) (int, Message, []byte, error) {
+	return c.readMessage(&sliceReader{buf: b}, c.snapshot())
+}
+
+// readScript reads a variable length byte array that represents a transaction
+// script, returning a slice which aliases the buffer of the receiver.  The
+// script is always read from the receiver, which must be the reader r the
+// transaction is decoded from, and its length is validated exactly like the
+// readScript function.  This is part of the scriptSource interface
+// implementation.
+func (r *sliceReader) readScript(_ io.Reader, pver uint32, maxAllowed uint64, fieldName string) ([]byte, error) {
+	count, err := readScriptLength(r, pver, maxAllowed, fieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.next(count)
+}
+
+// checkRemainingCount returns an error when r is an in-memory buffer which
+// cannot possibly hold count items of at least minSize bytes each.  This
+// prevents a tiny malicious buffer from causing a huge allocation up front.
+// Other readers are only bounded by the limits of the decoders.
+func checkRemainingCount(r io.Reader, count, minSize uint64, funcName, itemName string) error {
+	sr, ok := r.(*sliceReader)
+	if !ok {
+		return nil
+	}
+
+	if remaining := uint64(sr.Len()); count > remaining/minSize {
+		str := fmt.Sprintf("too many %s for the remaining buffer "+
+			"[count %d, remaining bytes %d]", itemName, count, remaining)
+		return messageError(funcName, ErrTooManyItems, str)
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// aliases reports whether the non-empty slice s points into buf.
+func aliases(s, buf []byte) bool {
+	if len(s) == 0 || len(buf) == 0 {
+		return false
+	}
+
+	start := uintptr(unsafe.Pointer(unsafe.SliceData(buf)))
+	p := uintptr(unsafe.Pointer(unsafe.SliceData(s)))
+
+	return p >= start && p+uintptr(len(s)) <= start+uintptr(len(buf))
+}
+
+// assertTxAliases ensures every script of tx aliases buf with cap == len.
+func assertTxAliases(t *testing.T, tx *MsgTx, buf []byte) {
+	t.Helper()
+
+	for _, ti := range tx.TxIn {
+		assert.True(t, aliases(ti.SignatureScript, buf))
+		assert.Equal(t, len(ti.SignatureScript), cap(ti.SignatureScript))
+	}
+
+	for _, to := range tx.TxOut {
+		assert.True(t, aliases(to.PkScript, buf))
+		assert.Equal(t, len(to.PkScript), cap(to.PkScript))
+	}
+}
+
+// TestTxFromBytes ensures a transaction decoded from a byte slice matches the
+// regular decoder while its scripts alias the slice.
+func TestTxFromBytes(t *testing.T) {
+	buf := bytes.Clone(multiTxEncoded)
+
+	var tx MsgTx
+	require.NoError(t, tx.FromBytes(buf))
+	assert.Equal(t, multiTx, &tx)
+	assertTxAliases(t, &tx, buf)
+
+	// Appending to a script must not overwrite the rest of the buffer.
+	_ = append(tx.TxIn[0].SignatureScript, 0xff)
+	assert.Equal(t, multiTxEncoded, buf)
+
+	// The retain contract: modifying the buffer is visible in the scripts.
+	buf[len(buf)-5] ^= 0xff
+	assert.NotEqual(t, multiTx.TxOut[1].PkScript, tx.TxOut[1].PkScript)
+
+	// Copy detaches the transaction from the buffer.
+	detached := tx.Copy()
+	buf[len(buf)-5] ^= 0xff
+	assert.NotEqual(t, tx.TxOut[1].PkScript, detached.TxOut[1].PkScript)
+}
+
+// TestBlockFromBytes ensures a block decoded from a byte slice matches the
+// regular decoder while the scripts of its transactions alias the slice.
+func TestBlockFromBytes(t *testing.T) {
+	buf := bytes.Clone(blockOneBytes)
+
+	var block MsgBlock
+	require.NoError(t, block.FromBytes(buf))
+	assert.Equal(t, &blockOne, &block)
+
+	for _, tx := range block.Transactions {
+		assertTxAliases(t, tx, buf)
+	}
+}
+
+// TestFromBytesErrors ensures decoding from a byte slice applies the same
+// limits as the regular decoder and rejects truncated and trailing data.
+func TestFromBytesErrors(t *testing.T) {
+	var msgErr *MessageError
+
+	// Reuse the oversized count and script length cases of the regular
+	// decoder, which must fail the same way.
+	overflows := [][]byte{
+		{0x01, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		{0x01, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		append(bytes.Clone(multiTxEncoded[:41]), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff),
+	}
+
+	for i, buf := range overflows {
+		var tx, regular MsgTx
+
+		err := tx.FromBytes(buf)
+		require.ErrorAs(t, err, &msgErr, "test #%d", i)
+
+		want := regular.Bsvdecode(bytes.NewReader(buf), 0, BaseEncoding)
+		require.EqualError(t, err, want.Error(), "test #%d", i)
+	}
+
+	// A count which is within the limits but cannot fit in the buffer is
+	// rejected before allocating.
+	var tx MsgTx
+
+	err := tx.FromBytes([]byte{0x01, 0x00, 0x00, 0x00, 0xfe, 0x00, 0x00, 0x01, 0x00})
+	require.ErrorAs(t, err, &msgErr)
+
+	// Non-canonical varint for the input count.
+	err = tx.FromBytes([]byte{0x01, 0x00, 0x00, 0x00, 0xfd, 0x01, 0x00})
+	require.ErrorAs(t, err, &msgErr)
+
+	// Truncated transaction.  Depending on where the data ends, the error is
+	// either a short read or a count which no longer fits.
+	for _, n := range []int{0, 3, 41, 50, 60} {
+		err = tx.FromBytes(multiTxEncoded[:n])
+		require.Error(t, err, "truncated at %d", n)
+	}
+
+	err = tx.FromBytes(multiTxEncoded[:len(multiTxEncoded)-1])
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// Trailing data.
+	err = tx.FromBytes(append(bytes.Clone(multiTxEncoded), 0x00))
+	require.ErrorAs(t, err, &msgErr)
+
+	var block MsgBlock
+
+	err = block.FromBytes(append(bytes.Clone(blockOneBytes), 0x00))
+	require.ErrorAs(t, err, &msgErr)
+
+	err = block.FromBytes(blockOneBytes[:len(blockOneBytes)-1])
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// A block claiming more transactions than fit in the buffer.
+	buf := bytes.Clone(blockOneBytes[:MaxBlockHeaderPayload])
+	buf = append(buf, 0xfe, 0x00, 0x00, 0x01, 0x00)
+
+	err = block.FromBytes(buf)
+	require.ErrorAs(t, err, &msgErr)
+}
+
+// TestDecodeMessageBytes ensures messages decoded from a byte slice match the
+// regular reader, alias the slice and report the bytes consumed.
+func TestDecodeMessageBytes(t *testing.T) {
+	msgs := []Message{&blockOne, multiTx, NewMsgPing(7), NewMsgVerAck(), multiExtendedTx}
+
+	var stream bytes.Buffer
+
+	for _, msg := range msgs {
+		_, err := WriteMessageN(&stream, msg, ProtocolVersion, MainNet)
+		require.NoError(t, err)
+	}
+
+	buf := stream.Bytes()
+	b := buf
+
+	for _, want := range msgs {
+		wantN, wantMsg, wantPayload, err := ReadMessageN(bytes.NewReader(b), ProtocolVersion, MainNet)
+		require.NoError(t, err)
+
+		n, msg, payload, err := DecodeMessageBytes(b, ProtocolVersion, MainNet, BaseEncoding)
+		require.NoError(t, err)
+		assert.Equal(t, wantN, n)
+		assert.Equal(t, wantMsg, msg)
+		assert.Equal(t, want, msg)
+		assert.Equal(t, wantPayload, payload)
+
+		if len(payload) > 0 {
+			assert.True(t, aliases(payload, buf))
+		}
+
+		switch m := msg.(type) {
+		case *MsgTx:
+			assertTxAliases(t, m, buf)
+		case *MsgBlock:
+			for _, tx := range m.Transactions {
+				assertTxAliases(t, tx, buf)
+			}
+		}
+
+		b = b[n:]
+	}
+
+	assert.Empty(t, b)
+
+	// Truncated payload reports the bytes consumed.
+	n, _, _, err := DecodeMessageBytes(buf[:MessageHeaderSize+10], ProtocolVersion, MainNet, BaseEncoding)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, MessageHeaderSize+10, n)
+
+	// Checksum and network are still verified.
+	corrupt := bytes.Clone(buf)
+	corrupt[MessageHeaderSize] ^= 0xff
+
+	var msgErr *MessageError
+
+	_, _, _, err = DecodeMessageBytes(corrupt, ProtocolVersion, MainNet, BaseEncoding)
+	require.ErrorAs(t, err, &msgErr)
+
+	_, _, _, err = DecodeMessageBytes(buf, ProtocolVersion, TestNet, BaseEncoding)
+	require.ErrorAs(t, err, &msgErr)
+
+	// The codec variant applies the codec settings.
+	codec := NewCodec(MainNet, ProtocolVersion)
+
+	n, msg, _, err := codec.DecodeBytes(buf)
+	require.NoError(t, err)
+	assert.Equal(t, &blockOne, msg)
+	assert.Equal(t, MessageHeaderSize+blockOne.SerializeSize(), n)
+
+	codec.SetLimits(1000)
+
+	_, _, _, err = codec.DecodeBytes(buf)
+	require.ErrorAs(t, err, &msgErr)
+}
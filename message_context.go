@@ -0,0 +1,320 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// MessageStage identifies the stage of reading or writing a message.
+type MessageStage int
+
+// These constants define the stages reported by TimeoutError.
+const (
+	// StageHeader is the stage of reading the message header.
+	StageHeader MessageStage = iota
+
+	// StagePayload is the stage of reading the message payload.
+	StagePayload
+
+	// StageWrite is the stage of writing the message.
+	StageWrite
+)
+
+// Map of message stages back to their constant names for pretty printing.
+var messageStageStrings = map[MessageStage]string{
+	StageHeader:  "StageHeader",
+	StagePayload: "StagePayload",
+	StageWrite:   "StageWrite",
+}
+
+// String returns the MessageStage in human-readable form.
+func (s MessageStage) String() string {
+	if str, ok := messageStageStrings[s]; ok {
+		return str
+	}
+
+	return fmt.Sprintf("Unknown MessageStage (%d)", int(s))
+}
+
+// TimeoutError describes a context-aware read or write which did not complete
+// because its context was done or a stage timeout of the codec expired.
+//
+// Err is the error of the context when it is done, otherwise the deadline
+// error reported by the connection, which matches os.ErrDeadlineExceeded.  The
+// caller can therefore use errors.Is with context.Canceled,
+// context.DeadlineExceeded or os.ErrDeadlineExceeded to determine the cause.
+type TimeoutError struct {
+	Stage MessageStage // Stage which did not complete
+	Err   error        // Underlying cause
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Stage, e.Err)
+}
+
+// Unwrap returns the underlying cause.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout returns whether the error was caused by a deadline rather than the
+// cancellation of the context.  It makes TimeoutError satisfy net.Error.
+func (e *TimeoutError) Timeout() bool {
+	return !errors.Is(e.Err, context.Canceled)
+}
+
+// Temporary returns false.  It makes TimeoutError satisfy net.Error.
+func (e *TimeoutError) Temporary() bool {
+	return false
+}
+
+// readDeadliner is implemented by readers, such as net.Conn, which support read
+// deadlines.
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// writeDeadliner is implemented by writers, such as net.Conn, which support
+// write deadlines.
+type writeDeadliner interface {
+	SetWriteDeadline(t time.Time) error
+}
+
+// ReadMessageContext reads, validates, and parses the next bitcoin Message
+// from r the same way as ReadMessageWithEncodingN while honouring ctx.  See
+// Codec.ReadContext for details.
+func ReadMessageContext(ctx context.Context, r io.Reader, pver uint32, bsvnet BitcoinNet,
+	enc MessageEncoding,
+) (int, Message, []byte, error) {
+	return defaultCodec.readMessageContext(ctx, r, codecParams{bsvnet: bsvnet, pver: pver, enc: enc})
+}
+
+// WriteMessageContext writes a bitcoin Message to w the same way as
+// WriteMessageWithEncodingN while honouring ctx.  See Codec.WriteContext for
+// details.
+func WriteMessageContext(ctx context.Context, w io.Writer, msg Message, pver uint32, bsvnet BitcoinNet,
+	enc MessageEncoding,
+) (int, error) {
+	return defaultCodec.writeMessageContext(ctx, w, msg, codecParams{bsvnet: bsvnet, pver: pver, enc: enc})
+}
+
+// ReadContext reads, validates, and parses the next bitcoin Message from r
+// the same way as Read while honouring ctx and the read timeouts of the codec.
+//
+// When r supports read deadlines, as net.Conn does, the header is read with a
+// deadline of the header timeout and, once it has been read, the payload with
+// a deadline of the payload timeout, each capped by the deadline of ctx.
+// Cancelling ctx interrupts a blocked read.  Any deadline previously set on r
+// is replaced and cleared on return.  When r does not support deadlines, ctx is
+// only checked before each stage and the timeouts do not apply.
+//
+// A read which does not complete in time returns a *TimeoutError identifying
+// the stage together with the number of bytes read.  When the error occurs in
+// StageHeader with no bytes read, the stream is untouched and the read may be
+// retried.  Otherwise the stream is left partway through the message and the
+// connection must be closed.
+func (c *Codec) ReadContext(ctx context.Context, r io.Reader) (int, Message, []byte, error) {
+	return c.readMessageContext(ctx, r, c.snapshot())
+}
+
+// WriteContext writes msg to w including the necessary header information the
+// same way as Write while honouring ctx and the write timeout of the codec.
+//
+// When w supports write deadlines, as net.Conn does, the message is written
+// with a deadline of the write timeout capped by the deadline of ctx, and
+// cancelling ctx interrupts a blocked write.  Any deadline previously set on w
+// is replaced and cleared on return.  When w does not support deadlines, ctx is
+// only checked before writing and the timeout does not apply.
+//
+// A write which does not complete in time returns a *TimeoutError in
+// StageWrite together with the number of bytes written.  Unless no bytes were
+// written, a partial message is left on w and the connection must be closed.
+func (c *Codec) WriteContext(ctx context.Context, w io.Writer, msg Message) (int, error) {
+	return c.writeMessageContext(ctx, w, msg, c.snapshot())
+}
+
+// readMessageContext reads the next message from r in two stages using the
+// provided codec settings while honouring ctx.
+func (c *Codec) readMessageContext(ctx context.Context, r io.Reader, p codecParams) (int, Message, []byte, error) {
+	var setDeadline func(time.Time) error
+	if d, ok := r.(readDeadliner); ok {
+		setDeadline = d.SetReadDeadline
+	}
+
+	g, err := newDeadlineGuard(ctx, setDeadline)
+	if err != nil {
+		return 0, nil, nil, &TimeoutError{Stage: StageHeader, Err: err}
+	}
+	defer g.release()
+
+	g.begin(p.headerTimeout)
+
+	n, hdr, err := readMessageHeader(r)
+	if err != nil {
+		return n, nil, nil, g.wrap(StageHeader, err)
+	}
+
+	if err = ctx.Err(); err != nil {
+		return n, nil, nil, &TimeoutError{Stage: StagePayload, Err: err}
+	}
+
+	g.begin(p.payloadTimeout)
+
+	n, msg, payload, err := c.readPayload(r, hdr, n, p)
+	if err != nil {
+		return n, nil, nil, g.wrap(StagePayload, err)
+	}
+
+	return n, msg, payload, nil
+}
+
+// writeMessageContext writes msg to w using the provided codec settings while
+// honouring ctx.
+func (c *Codec) writeMessageContext(ctx context.Context, w io.Writer, msg Message, p codecParams) (int, error) {
+	var setDeadline func(time.Time) error
+	if d, ok := w.(writeDeadliner); ok {
+		setDeadline = d.SetWriteDeadline
+	}
+
+	g, err := newDeadlineGuard(ctx, setDeadline)
+	if err != nil {
+		return 0, &TimeoutError{Stage: StageWrite, Err: err}
+	}
+	defer g.release()
+
+	g.begin(p.writeTimeout)
+
+	n, err := c.writeMessage(w, msg, p)
+	if err != nil {
+		return n, g.wrap(StageWrite, err)
+	}
+
+	return n, nil
+}
+
+// deadlineGuard applies stage deadlines to a connection and interrupts it when
+// the context is done.
+type deadlineGuard struct {
+	ctx         context.Context
+	setDeadline func(time.Time) error
+	stop        func() bool
+	fired       chan struct{}
+	used        bool
+
+	// mtx serialises applying the deadline of a stage with the interrupt,
+	// so a stage never replaces the deadline in the past the interrupt
+	// sets.
+	mtx sync.Mutex
+}
+
+// newDeadlineGuard returns a deadlineGuard for ctx which applies deadlines via
+// setDeadline, which is nil when the connection does not support deadlines.
+// It returns the error of ctx when ctx is already done.
+func newDeadlineGuard(ctx context.Context, setDeadline func(time.Time) error) (*deadlineGuard, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	g := &deadlineGuard{ctx: ctx, setDeadline: setDeadline}
+
+	if setDeadline != nil && ctx.Done() != nil {
+		g.fired = make(chan struct{})
+		g.stop = context.AfterFunc(ctx, func() {
+			g.mtx.Lock()
+			defer g.mtx.Unlock()
+
+			// A deadline in the past unblocks any pending operation.
+			_ = setDeadline(time.Unix(1, 0))
+			close(g.fired)
+		})
+	}
+
+	return g, nil
+}
+
+// begin applies the deadline for a stage which may take at most timeout,
+// capped by the deadline of the context.  A zero timeout only applies the
+// deadline of the context.  Once the context has interrupted the connection,
+// its deadline in the past is kept.
+func (g *deadlineGuard) begin(timeout time.Duration) {
+	if g.setDeadline == nil {
+		return
+	}
+
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	if g.interrupted() {
+		return
+	}
+
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	if d, ok := g.ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+
+	if deadline.IsZero() && !g.used {
+		return
+	}
+
+	g.used = true
+	_ = g.setDeadline(deadline)
+}
+
+// wrap converts err into a *TimeoutError for stage when it was caused by the
+// context or a deadline, and returns it unchanged otherwise.
+func (g *deadlineGuard) wrap(stage MessageStage, err error) error {
+	if ctxErr := g.ctx.Err(); ctxErr != nil && (g.interrupted() || errors.Is(err, os.ErrDeadlineExceeded)) {
+		return &TimeoutError{Stage: stage, Err: ctxErr}
+	}
+
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return &TimeoutError{Stage: stage, Err: err}
+	}
+
+	return err
+}
+
+// interrupted returns whether the context interrupted the connection.
+func (g *deadlineGuard) interrupted() bool {
+	if g.fired == nil {
+		return false
+	}
+
+	select {
+	case <-g.fired:
+		return true
+	default:
+		return false
+	}
+}
+
+// release stops watching the context and clears any deadline applied to the
+// connection.
+func (g *deadlineGuard) release() {
+	if g.stop != nil && !g.stop() {
+		// The interrupt has started, so wait for it to finish before
+		// clearing the deadline it set.
+		<-g.fired
+
+		g.used = true
+	}
+
+	if g.used {
+		_ = g.setDeadline(time.Time{})
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPipe returns both ends of a synchronous in-memory connection which
+// supports deadlines and closes them when the test completes.
+func newPipe(t *testing.T) (net.Conn, net.Conn) {
+	t.Helper()
+
+	local, remote := net.Pipe()
+	t.Cleanup(func() {
+		_ = local.Close()
+		_ = remote.Close()
+	})
+
+	return local, remote
+}
+
+// requireTimeout ensures err is a *TimeoutError for stage.
+func requireTimeout(t *testing.T, err error, stage MessageStage) *TimeoutError {
+	t.Helper()
+
+	var timeoutErr *TimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, stage, timeoutErr.Stage)
+
+	return timeoutErr
+}
+
+// TestReadWriteContext ensures messages round-trip through the context-aware
+// functions and that deadlines are cleared afterwards.
+func TestReadWriteContext(t *testing.T) {
+	local, remote := newPipe(t)
+
+	codec := NewCodec(MainNet, ProtocolVersion)
+	codec.SetReadTimeouts(100*time.Millisecond, 200*time.Millisecond)
+	codec.SetWriteTimeout(100 * time.Millisecond)
+
+	header, payload := codec.ReadTimeouts()
+	assert.Equal(t, 100*time.Millisecond, header)
+	assert.Equal(t, 200*time.Millisecond, payload)
+	assert.Equal(t, 100*time.Millisecond, codec.WriteTimeout())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	go func() {
+		_, _ = codec.WriteContext(ctx, remote, &blockOne)
+
+		// Write after the deadlines of both ends would have expired had
+		// they not been cleared.
+		time.Sleep(300 * time.Millisecond)
+
+		_, _ = WriteMessageContext(context.Background(), remote, NewMsgPing(5), ProtocolVersion, MainNet,
+			BaseEncoding)
+	}()
+
+	n, msg, _, err := codec.ReadContext(ctx, local)
+	require.NoError(t, err)
+	assert.Equal(t, MessageHeaderSize+blockOne.SerializeSize(), n)
+	assert.Equal(t, &blockOne, msg)
+
+	_, msg, _, err = ReadMessageN(local, ProtocolVersion, MainNet)
+	require.NoError(t, err)
+	assert.Equal(t, NewMsgPing(5), msg)
+
+	// Readers and writers without deadlines are supported.
+	var buf bytes.Buffer
+
+	_, err = codec.WriteContext(ctx, &buf, NewMsgVerAck())
+	require.NoError(t, err)
+
+	_, msg, _, err = ReadMessageContext(ctx, &buf, ProtocolVersion, MainNet, BaseEncoding)
+	require.NoError(t, err)
+	assert.Equal(t, NewMsgVerAck(), msg)
+}
+
+// TestReadContextTimeouts ensures the header and payload timeouts interrupt
+// reads and report the stage and number of bytes read.
+func TestReadContextTimeouts(t *testing.T) {
+	t.Run("header", func(t *testing.T) {
+		local, _ := newPipe(t)
+
+		codec := NewCodec(MainNet, ProtocolVersion)
+		codec.SetReadTimeouts(20*time.Millisecond, 0)
+
+		n, _, _, err := codec.ReadContext(context.Background(), local)
+		timeoutErr := requireTimeout(t, err, StageHeader)
+		require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+		assert.True(t, timeoutErr.Timeout())
+		assert.Equal(t, 0, n)
+	})
+
+	t.Run("payload", func(t *testing.T) {
+		local, remote := newPipe(t)
+
+		codec := NewCodec(MainNet, ProtocolVersion)
+		codec.SetReadTimeouts(0, 50*time.Millisecond)
+
+		// Trickle only part of the payload, as a slow peer would.
+		frame := encodeFrame(t, codec, &blockOne)
+
+		go func() {
+			_, _ = remote.Write(frame[:MessageHeaderSize+10])
+		}()
+
+		n, _, _, err := codec.ReadContext(context.Background(), local)
+		requireTimeout(t, err, StagePayload)
+		require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+		assert.Equal(t, MessageHeaderSize+10, n)
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		local, _ := newPipe(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, _, _, err := ReadMessageContext(ctx, local, ProtocolVersion, MainNet, BaseEncoding)
+		timeoutErr := requireTimeout(t, err, StageHeader)
+		assert.True(t, timeoutErr.Timeout())
+	})
+
+	t.Run("other errors are unchanged", func(t *testing.T) {
+		local, remote := newPipe(t)
+
+		go func() {
+			_, _ = remote.Write([]byte{0x01, 0x02})
+			_ = remote.Close()
+		}()
+
+		_, _, _, err := ReadMessageContext(context.Background(), local, ProtocolVersion, MainNet,
+			BaseEncoding)
+		require.Error(t, err)
+
+		var timeoutErr *TimeoutError
+		assert.False(t, errors.As(err, &timeoutErr))
+	})
+}
+
+// TestContextCancellation ensures cancelling the context interrupts blocked
+// reads and writes.
+func TestContextCancellation(t *testing.T) {
+	local, _ := newPipe(t)
+	codec := NewCodec(MainNet, ProtocolVersion)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, _, _, err := codec.ReadContext(ctx, local)
+	timeoutErr := requireTimeout(t, err, StageHeader)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, timeoutErr.Timeout())
+
+	// An already cancelled context fails without touching the stream.
+	n, _, _, err := codec.ReadContext(ctx, &bytes.Buffer{})
+	requireTimeout(t, err, StageHeader)
+	assert.Equal(t, 0, n)
+
+	n, err = codec.WriteContext(ctx, &bytes.Buffer{}, NewMsgVerAck())
+	requireTimeout(t, err, StageWrite)
+	assert.Equal(t, 0, n)
+
+	// A blocked write is interrupted.
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err = codec.WriteContext(ctx, local, NewMsgVerAck())
+	requireTimeout(t, err, StageWrite)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// TestWriteContextTimeout ensures the write timeout interrupts writes to a
+// peer which does not read.
+// deadlineHookConn is a net.Conn which calls hook with every read deadline
+// before applying it.
+type deadlineHookConn struct {
+	net.Conn
+	hook func(deadline time.Time)
+}
+
+// SetReadDeadline calls the hook and applies the deadline.
+func (c *deadlineHookConn) SetReadDeadline(deadline time.Time) error {
+	c.hook(deadline)
+	return c.Conn.SetReadDeadline(deadline)
+}
+
+func TestContextCancellationBeforePayload(t *testing.T) {
+	local, remote := newPipe(t)
+
+	codec := NewCodec(MainNet, ProtocolVersion)
+	codec.SetReadTimeouts(0, 5*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel while the payload deadline is being applied, after the context
+	// was last checked, and give the interrupt the chance to run first.
+	interrupted := make(chan struct{}, 1)
+
+	var once sync.Once
+
+	conn := &deadlineHookConn{Conn: local, hook: func(deadline time.Time) {
+		switch {
+		case deadline.Equal(time.Unix(1, 0)):
+			interrupted <- struct{}{}
+		case !deadline.IsZero():
+			once.Do(func() {
+				cancel()
+
+				select {
+				case <-interrupted:
+				case <-time.After(100 * time.Millisecond):
+				}
+			})
+		}
+	}}
+
+	frame := encodeFrame(t, codec, &blockOne)
+
+	go func() {
+		_, _ = remote.Write(frame[:MessageHeaderSize])
+	}()
+
+	start := time.Now()
+	_, _, _, err := codec.ReadContext(ctx, conn)
+	requireTimeout(t, err, StagePayload)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestWriteContextTimeout(t *testing.T) {
+	local, _ := newPipe(t)
+
+	codec := NewCodec(MainNet, ProtocolVersion)
+	codec.SetWriteTimeout(20 * time.Millisecond)
+
+	n, err := codec.WriteContext(context.Background(), local, &blockOne)
+	requireTimeout(t, err, StageWrite)
+	require.ErrorIs(t, err, os.ErrDeadlineExceeded)
+	assert.Equal(t, 0, n)
+}
+
+// TestMessageStageStringer tests the stringized output for the MessageStage
+// type.
+func TestMessageStageStringer(t *testing.T) {
+	tests := []struct {
+		in   MessageStage
+		want string
+	}{
+		{StageHeader, "StageHeader"},
+		{StagePayload, "StagePayload"},
+		{StageWrite, "StageWrite"},
+		{0xff, "Unknown MessageStage (255)"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, test.in.String())
+	}
+
+	err := &TimeoutError{Stage: StagePayload, Err: context.Canceled}
+	assert.Equal(t, "StagePayload: context canceled", err.Error())
+	assert.False(t, err.Temporary())
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cmdSink is the command used by msgSink in the extended message tests.
+const cmdSink = "xsink"
+
+// msgSink is a message which consumes its entire payload without storing it
+// and encodes Length zero bytes, allowing payloads larger than 4GB to be read
+// and written in tests.
+type msgSink struct {
+	Length uint64
+}
+
+func (msg *msgSink) Bsvdecode(r io.Reader, _ uint32, _ MessageEncoding) error {
+	n, err := io.Copy(io.Discard, r)
+	msg.Length = uint64(n)
+
+	return err
+}
+
+func (msg *msgSink) BsvEncode(w io.Writer, _ uint32, _ MessageEncoding) error {
+	_, err := io.CopyN(w, zeroReader{}, int64(msg.Length))
+	return err
+}
+
+func (msg *msgSink) PayloadSize(_ uint32) int {
+	return int(msg.Length)
+}
+
+func (msg *msgSink) Command() string {
+	return cmdSink
+}
+
+func (msg *msgSink) MaxPayloadLength(_ uint32) uint64 {
+	return MaxExtMsgPayload
+}
+
+// zeroReader is an io.Reader which produces an endless stream of zeros.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// TestMessageHeaderExtendedBoundary ensures headers around the 4GB boundary
+// round-trip through encodeMessageHeader and readMessageHeader.
+func TestMessageHeaderExtendedBoundary(t *testing.T) {
+	tests := []struct {
+		name     string
+		length   uint64
+		extended bool
+	}{
+		{"max common length", math.MaxUint32 - 1, false},
+		{"extended marker length", math.MaxUint32, true},
+		{"just over 4GB", math.MaxUint32 + 1, true},
+		{"small extended payload", 81, true},
+		{"very large payload", 1 << 40, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hdr := messageHeader{magic: MainNet, command: CmdBlock, extended: tt.extended}
+			if tt.extended {
+				hdr.extLength = tt.length
+			} else {
+				hdr.length = uint32(tt.length)
+				hdr.checksum = [4]byte{0x01, 0x02, 0x03, 0x04}
+			}
+
+			encoded := encodeMessageHeader(&hdr)
+
+			wantSize := MessageHeaderSize
+			if tt.extended {
+				wantSize = ExtendedMessageHeaderSize
+			}
+
+			require.Len(t, encoded, wantSize)
+
+			n, got, err := readMessageHeader(bytes.NewReader(encoded))
+			require.NoError(t, err)
+			assert.Equal(t, wantSize, n)
+			assert.Equal(t, CmdBlock, got.command)
+			assert.Equal(t, tt.extended, got.extended)
+			assert.Equal(t, tt.length, got.payloadLength())
+		})
+	}
+}
+
+// TestExtendedMessageRoundTrip ensures messages written with the extended
+// header are read back by both read paths.
+func TestExtendedMessageRoundTrip(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	codec.SetExtendedMessages(true)
+	assert.True(t, codec.ExtendedMessages())
+
+	var buf bytes.Buffer
+	n, err := codec.Write(&buf, &blockOne)
+	require.NoError(t, err)
+	require.Equal(t, buf.Len(), n)
+
+	encoded := buf.Bytes()
+	payloadLen := blockOne.SerializeSize()
+	require.Len(t, encoded, ExtendedMessageHeaderSize+payloadLen)
+
+	// Verify the header layout: common header with the extmsg command, the
+	// length marker and a zero checksum, followed by the actual command and
+	// the 64-bit payload length.
+	assert.Equal(t, CmdExtMsg, string(bytes.TrimRight(encoded[4:16], "\x00")))
+	assert.Equal(t, uint32(math.MaxUint32), binary.LittleEndian.Uint32(encoded[16:20]))
+	assert.Equal(t, []byte{0, 0, 0, 0}, encoded[20:24])
+	assert.Equal(t, CmdBlock, string(bytes.TrimRight(encoded[24:36], "\x00")))
+	assert.Equal(t, uint64(payloadLen), binary.LittleEndian.Uint64(encoded[36:44]))
+
+	n, msg, payload, err := ReadMessageN(bytes.NewReader(encoded), ProtocolVersion, MainNet)
+	require.NoError(t, err)
+	assert.Equal(t, len(encoded), n)
+	assert.Equal(t, &blockOne, msg)
+	assert.Equal(t, encoded[ExtendedMessageHeaderSize:], payload)
+
+	n, msg, err = ReadMessageStreamingN(bytes.NewReader(encoded), ProtocolVersion, MainNet, BaseEncoding)
+	require.NoError(t, err)
+	assert.Equal(t, len(encoded), n)
+	assert.Equal(t, &blockOne, msg)
+
+	// A truncated extended header is reported as a short read.
+	n, _, _, err = ReadMessageN(bytes.NewReader(encoded[:30]), ProtocolVersion, MainNet)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Equal(t, 30, n)
+}
+
+// TestExtendedMessageProtocolVersion ensures the extended header is not
+// written for peers which do not support it.
+func TestExtendedMessageProtocolVersion(t *testing.T) {
+	codec := NewCodec(MainNet, ExtMsgVersion-1)
+	codec.SetExtendedMessages(true)
+
+	_, err := codec.Write(io.Discard, &blockOne)
+
+	var msgErr *MessageError
+	require.ErrorAs(t, err, &msgErr)
+}
+
+// readSink reads a sink message of length bytes followed by a ping from r with
+// codec.
+func readSink(t *testing.T, codec *Codec, r io.Reader, length uint64) {
+	t.Helper()
+
+	n, msg, err := codec.ReadStreaming(r)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(ExtendedMessageHeaderSize)+length, uint64(n))
+	require.IsType(t, &msgSink{}, msg)
+	assert.Equal(t, length, msg.(*msgSink).Length)
+
+	_, msg, _, err = codec.Read(r)
+	require.NoError(t, err)
+	assert.Equal(t, NewMsgPing(42), msg)
+}
+
+// TestExtendedMessageStreamingOver4GB ensures payloads around and beyond the
+// 4GB boundary are written and read using a synthetic streaming payload, so
+// the test does not need to hold the payload in memory.
+func TestExtendedMessageStreamingOver4GB(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping 4GB payload test in short mode")
+	}
+
+	require.NoError(t, RegisterMessage(cmdSink, func() Message { return &msgSink{} }))
+	t.Cleanup(func() {
+		UnregisterMessage(cmdSink)
+	})
+
+	codec := NewCodec(MainNet, ProtocolVersion)
+	codec.SetLimits(4 * 1024 * 1024 * 1024)
+
+	tests := []struct {
+		name   string
+		length uint64
+	}{
+		{"extended marker length", math.MaxUint32},
+		{"just over 4GB", math.MaxUint32 + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hdr := messageHeader{magic: MainNet, command: cmdSink, extended: true, extLength: tt.length}
+
+			// Follow the payload with a ping to ensure the reader is left
+			// positioned at the next message.
+			var next bytes.Buffer
+			_, err := codec.Write(&next, NewMsgPing(42))
+			require.NoError(t, err)
+
+			r := io.MultiReader(
+				bytes.NewReader(encodeMessageHeader(&hdr)),
+				io.LimitReader(zeroReader{}, int64(tt.length)),
+				&next,
+			)
+
+			readSink(t, codec, r, tt.length)
+
+			// The codec writes the same frame, which reads back the
+			// same way.
+			pr, pw := io.Pipe()
+			defer pr.Close()
+
+			go func() {
+				_, err := codec.WriteStreaming(pw, &msgSink{Length: tt.length})
+				if err == nil {
+					_, err = codec.Write(pw, NewMsgPing(42))
+				}
+
+				pw.CloseWithError(err)
+			}()
+
+			written := make([]byte, ExtendedMessageHeaderSize)
+			_, err = io.ReadFull(pr, written)
+			require.NoError(t, err)
+			assert.Equal(t, encodeMessageHeader(&hdr), written)
+
+			readSink(t, codec, io.MultiReader(bytes.NewReader(written), pr), tt.length)
+
+			_, err = pr.Read(make([]byte, 1))
+			require.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
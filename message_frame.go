@@ -0,0 +1,414 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+// Frame is a bitcoin message whose header has been read and validated but
+// whose payload has not been decoded yet.  It lets routers and load balancers
+// act on the command and length of a message and only decode the messages
+// they need via Decode.
+//
+// Frames are returned by ReadFrame, which reads the payload into memory, and
+// ReadFrameStreaming, which leaves the payload on the reader.  Either way the
+// header is subject to exactly the same checks as ReadMessageWithEncodingN:
+// the network, the command, the overall maximum payload and the maximum
+// payload of the message type, including the extended header handling.
+//
+// When the codec has an observer, a frame is observed once its payload fails to
+// be read or verified, or otherwise once it is first decoded, discarded or its
+// payload reader is obtained.  Frames which are never consumed in one of these
+// ways are not observed.
+type Frame struct {
+	// Magic is the bitcoin network the message was sent on.
+	Magic BitcoinNet
+
+	// Command is the command of the message.  For messages using the
+	// extended header this is the actual command rather than extmsg.
+	Command string
+
+	// Length is the length of the payload in bytes.
+	Length uint64
+
+	// Checksum is the checksum from the header.  It is zero for messages
+	// using the extended header.
+	Checksum [4]byte
+
+	// Extended reports whether the message uses the extended header.
+	Extended bool
+
+	// Payload is the raw payload of frames returned by ReadFrame.  It is nil
+	// for frames returned by ReadFrameStreaming.
+	Payload []byte
+
+	hdr        messageHeader
+	hdrBytes   int
+	p          codecParams
+	r          io.Reader
+	msg        Message
+	ob         *observation
+	consumed   bool
+	checksumOK bool
+	alias      bool
+}
+
+// ReadFrame reads and validates the next bitcoin message from r for the
+// provided protocol version and bitcoin network without decoding its payload.
+// See Codec.ReadFrame for details.
+func ReadFrame(r io.Reader, pver uint32, bsvnet BitcoinNet) (int, *Frame, error) {
+	return defaultCodec.readFrame(r, codecParams{bsvnet: bsvnet, pver: pver, enc: BaseEncoding})
+}
+
+// ReadFrameStreaming reads and validates the header of the next bitcoin
+// message from r for the provided protocol version and bitcoin network,
+// leaving the payload on r.  See Codec.ReadFrameStreaming for details.
+func ReadFrameStreaming(r io.Reader, pver uint32, bsvnet BitcoinNet) (int, *Frame, error) {
+	return defaultCodec.readFrameStreaming(r, codecParams{bsvnet: bsvnet, pver: pver, enc: BaseEncoding})
+}
+
+// ReadFrame reads and validates the next bitcoin message from r using the
+// settings of the codec, reading its payload into memory without decoding it.
+// It returns the number of bytes read in addition to the frame.
+//
+// The checksum is verified before the frame is returned, so the errors and the
+// state r is left in match ReadMessageWithEncodingN.  The frame may be decoded
+// any number of times.  External handlers and interceptors are not consulted.
+func (c *Codec) ReadFrame(r io.Reader) (int, *Frame, error) {
+	return c.readFrame(r, c.snapshot())
+}
+
+// ReadFrameStreaming reads and validates the header of the next bitcoin
+// message from r using the settings of the codec and returns the number of
+// header bytes read in addition to the frame.  The payload is left on r.
+//
+// Exactly one of Decode, Reader or Discard must then be used to consume the
+// payload before the next message is read from r.  Decode verifies the
+// checksum the same way as ReadMessageStreamingN.  External handlers and
+// interceptors are not consulted.
+func (c *Codec) ReadFrameStreaming(r io.Reader) (int, *Frame, error) {
+	return c.readFrameStreaming(r, c.snapshot())
+}
+
+// readFrame reads and validates the next message from r including its payload
+// using the provided codec settings.
+func (c *Codec) readFrame(r io.Reader, p codecParams) (int, *Frame, error) {
+	n, f, msg, err := c.readFrameHeader(r, p)
+	if err != nil {
+		return n, nil, err
+	}
+
+	payload := make([]byte, f.Length)
+
+	read, err := io.ReadFull(r, payload)
+	n += read
+
+	if err != nil {
+		f.observed(read, err)
+		return n, nil, err
+	}
+
+	f.Payload = payload
+	f.consumed = true
+
+	if !f.Extended {
+		err = verifyChecksum(&f.hdr, payload)
+		f.checksumOK = err == nil
+
+		if _, isRaw := msg.(*MsgRaw); err != nil && !isRaw {
+			f.observed(read, err)
+			return n, nil, err
+		}
+	}
+
+	return n, f, nil
+}
+
+// readFrameStreaming reads and validates the header of the next message from
+// r using the provided codec settings.
+func (c *Codec) readFrameStreaming(r io.Reader, p codecParams) (int, *Frame, error) {
+	n, f, _, err := c.readFrameHeader(r, p)
+	if err != nil {
+		return n, nil, err
+	}
+
+	f.r = r
+
+	return n, f, nil
+}
+
+// readFrameHeader reads and validates a message header from r and returns a
+// frame for it along with an empty message of the announced type.  The
+// observation of the message starts once the header has been read and is
+// carried by the frame until it is consumed.
+func (c *Codec) readFrameHeader(r io.Reader, p codecParams) (int, *Frame, Message, error) {
+	n, hdr, err := readMessageHeader(r)
+	if err != nil {
+		return n, nil, nil, err
+	}
+
+	ob := c.observe(DirectionRead, hdr.command)
+
+	msg, err := p.checkHeader(r, hdr)
+	if err != nil {
+		ob.end(n, 0, err)
+		return n, nil, nil, err
+	}
+
+	f := newFrame(hdr, p, msg)
+	f.hdrBytes = n
+	f.ob = ob
+
+	return n, f, msg, nil
+}
+
+// newFrame returns a frame for the validated header hdr which was read using
+// the provided codec settings.  msg is the empty message returned by
+// checkHeader for hdr, which the frame is first decoded into so that pooled
+// messages are only acquired once.
+func newFrame(hdr *messageHeader, p codecParams, msg Message) *Frame {
+	f := &Frame{
+		Magic:    hdr.magic,
+		Command:  hdr.command,
+		Length:   hdr.payloadLength(),
+		Extended: hdr.extended,
+		hdr:      *hdr,
+		p:        p,
+		msg:      msg,
+	}
+
+	if !hdr.extended {
+		f.Checksum = hdr.checksum
+	}
+
+	return f
+}
+
+// Decode decodes the payload of the frame into a message of the type
+// announced by its command using the provided protocol version and encoding.
+// The maximum payload of the message type is checked again for pver.
+//
+// Frames returned by ReadFrameStreaming are decoded from the reader, after
+// which the payload has been consumed and the frame can not be decoded again.
+// A checksum mismatch is reported after decoding, as by ReadMessageStreamingN,
+// and r is left positioned after the payload even when decoding fails.
+func (f *Frame) Decode(pver uint32, enc MessageEncoding) (Message, error) {
+	ob := f.ob
+	f.ob = nil
+
+	ob.begin()
+	msg, n, err := f.decode(pver, enc)
+	ob.end(f.hdrBytes, n, err)
+
+	return msg, err
+}
+
+// decode implements Decode, additionally returning the number of payload bytes
+// read.
+func (f *Frame) decode(pver uint32, enc MessageEncoding) (Message, int, error) {
+	p := f.p
+	p.pver = pver
+	p.enc = enc
+
+	if f.Payload == nil && f.consumed {
+		return nil, 0, messageError("Frame.Decode", ErrUnsupported, "payload already consumed")
+	}
+
+	// payloadBytes is the number of payload bytes read from the frame.
+	payloadBytes := len(f.Payload)
+
+	// The first decode uses the message created when the header was
+	// checked.
+	msg := f.msg
+	f.msg = nil
+
+	var err error
+
+	if msg == nil {
+		msg, err = p.makeEmptyMessage(f.Command)
+		if err != nil {
+			n, _ := f.Discard()
+			return nil, payloadBytes + n, messageError("Frame.Decode", ErrUnknownCommand, err.Error())
+		}
+	}
+
+	if mpl := p.maxPayloadLength(msg); f.Length > mpl {
+		n, _ := f.Discard()
+		str := fmt.Sprintf("payload exceeds max length - header "+
+			"indicates %v bytes, but max payload size for "+
+			"messages of type [%v] is %v.", f.Length, f.Command, mpl)
+
+		return nil, payloadBytes + n, messageError("Frame.Decode", ErrPayloadTooLarge, str)
+	}
+
+	// Version messages must be decoded from a *bytes.Buffer, so buffer
+	// their payload, which is small, and verify it like ReadFrame does.
+	if _, isVersion := msg.(*MsgVersion); isVersion && f.Payload == nil {
+		payload := make([]byte, f.Length)
+
+		n, err := io.ReadFull(f.Reader(), payload)
+		if err != nil {
+			return nil, n, err
+		}
+
+		if !f.Extended {
+			if err = verifyChecksum(&f.hdr, payload); err != nil {
+				return nil, n, err
+			}
+		}
+
+		if err = decodePayload(msg, payload, p); err != nil {
+			return nil, n, err
+		}
+
+		return msg, n, nil
+	}
+
+	if f.Payload == nil {
+		f.consumed = true
+
+		n, err := decodeStreamingPayload(f.r, &f.hdr, msg, p)
+		if err != nil {
+			return nil, n, err
+		}
+
+		return msg, n, nil
+	}
+
+	if raw, ok := msg.(*MsgRaw); ok {
+		raw.Payload = f.Payload
+		raw.ChecksumVerified = f.checksumOK
+
+		return raw, payloadBytes, nil
+	}
+
+	if d, ok := msg.(aliasDecoder); ok && f.alias {
+		err = decodeAliasPayload(d, f.Command, f.Payload, p)
+	} else {
+		err = decodePayload(msg, f.Payload, p)
+	}
+
+	if err != nil {
+		return nil, payloadBytes, err
+	}
+
+	return msg, payloadBytes, nil
+}
+
+// Reader returns a reader over the raw payload of the frame.  For frames
+// returned by ReadFrameStreaming it reads the payload from the underlying
+// reader, bounded to Length bytes, and consumes the frame; the caller must read
+// it to the end before reading the next message.  Unless the frame uses the
+// extended header, the payload is verified against the checksum once it has
+// been read in full, and a mismatch is returned instead of io.EOF.
+func (f *Frame) Reader() io.Reader {
+	f.observed(int(f.Length), nil)
+
+	if f.Payload != nil {
+		return bytes.NewReader(f.Payload)
+	}
+
+	if f.consumed {
+		return bytes.NewReader(nil)
+	}
+
+	f.consumed = true
+
+	lr := &io.LimitedReader{R: f.r, N: int64(f.Length)}
+	if f.Extended {
+		return lr
+	}
+
+	return &checksumReader{lr: lr, h: sha256.New(), hdr: &f.hdr}
+}
+
+// Discard skips the payload of a frame returned by ReadFrameStreaming and
+// returns the number of bytes skipped, leaving the underlying reader
+// positioned at the next message.  It does nothing for frames whose payload has
+// already been read or consumed.
+func (f *Frame) Discard() (int, error) {
+	if f.Payload != nil || f.consumed {
+		f.observed(len(f.Payload), nil)
+		return 0, nil
+	}
+
+	f.consumed = true
+
+	n, err := io.Copy(io.Discard, io.LimitReader(f.r, int64(f.Length)))
+	if err == nil && uint64(n) < f.Length {
+		err = io.ErrUnexpectedEOF
+	}
+
+	f.observed(int(n), err)
+
+	return int(n), err
+}
+
+// observed notifies the observer of the codec the frame was read by of the
+// frame with the provided payload byte count and error, unless it has already
+// been notified.
+func (f *Frame) observed(payloadBytes int, err error) {
+	f.ob.end(f.hdrBytes, payloadBytes, err)
+	f.ob = nil
+}
+
+// checksumReader reads a streamed payload and verifies it against the checksum
+// of its header once it has been read in full.
+type checksumReader struct {
+	lr  *io.LimitedReader
+	h   hash.Hash
+	hdr *messageHeader
+}
+
+// Read reads from the payload, returning io.ErrUnexpectedEOF when it is
+// truncated and a *MessageError when its checksum does not match.
+func (cr *checksumReader) Read(p []byte) (int, error) {
+	n, err := cr.lr.Read(p)
+	cr.h.Write(p[:n])
+
+	if !errors.Is(err, io.EOF) {
+		return n, err
+	}
+
+	if cr.lr.N > 0 {
+		return n, io.ErrUnexpectedEOF
+	}
+
+	checksum := sha256.Sum256(cr.h.Sum(nil))
+	if !bytes.Equal(checksum[0:4], cr.hdr.checksum[:]) {
+		return n, checksumError(cr.hdr, checksum[0:4])
+	}
+
+	return n, io.EOF
+}
+
+// verifyChecksum returns an error describing the mismatch when the checksum
+// of payload does not match hdr.
+func verifyChecksum(hdr *messageHeader, payload []byte) error {
+	checksum := chainhash.DoubleHashB(payload)[0:4]
+	if !bytes.Equal(checksum, hdr.checksum[:]) {
+		return checksumError(hdr, checksum)
+	}
+
+	return nil
+}
+
+// checksumError returns an error describing the mismatch between the checksum
+// of hdr and the actual checksum of its payload.
+func checksumError(hdr *messageHeader, checksum []byte) error {
+	str := fmt.Sprintf("payload checksum failed - header "+
+		"indicates %v, but actual checksum is %v.",
+		hdr.checksum, checksum)
+
+	return messageError("ReadMessage", ErrChecksumMismatch, str)
+}
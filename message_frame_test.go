@@ -0,0 +1,194 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadFrame ensures frames expose the header fields and decode to the
+// same messages as the eager read paths.
+func TestReadFrame(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+
+	extCodec := NewCodec(MainNet, ProtocolVersion)
+	extCodec.SetExtendedMessages(true)
+
+	version := NewMsgVersion(&NetAddress{}, &NetAddress{}, 123, 0)
+
+	tests := []struct {
+		name  string
+		frame []byte
+		msg   Message
+	}{
+		{"ping", encodeFrame(t, codec, NewMsgPing(5)), NewMsgPing(5)},
+		{"verack", encodeFrame(t, codec, NewMsgVerAck()), NewMsgVerAck()},
+		{"block", encodeFrame(t, codec, &blockOne), &blockOne},
+		{"version", encodeFrame(t, codec, version), version},
+		{"extended tx", encodeFrame(t, extCodec, multiTx), multiTx},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, want, payload, err := ReadMessageN(bytes.NewReader(tt.frame), ProtocolVersion, MainNet)
+			require.NoError(t, err)
+
+			// Buffered frames.
+			r := bytes.NewReader(tt.frame)
+
+			n, f, err := ReadFrame(r, ProtocolVersion, MainNet)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.frame), n)
+			assert.Equal(t, MainNet, f.Magic)
+			assert.Equal(t, tt.msg.Command(), f.Command)
+			assert.Equal(t, uint64(len(payload)), f.Length)
+			assert.Equal(t, payload, f.Payload)
+			assert.Equal(t, 0, r.Len())
+
+			if f.Extended {
+				assert.Equal(t, [4]byte{}, f.Checksum)
+			} else {
+				assert.Equal(t, tt.frame[20:24], f.Checksum[:])
+			}
+
+			for range 2 {
+				msg, err := f.Decode(ProtocolVersion, BaseEncoding)
+				require.NoError(t, err)
+				assert.Equal(t, want, msg)
+			}
+
+			// Streaming frames.
+			r = bytes.NewReader(tt.frame)
+
+			n, f, err = codec.ReadFrameStreaming(r)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.frame)-len(payload), n)
+			assert.Nil(t, f.Payload)
+			assert.Equal(t, len(payload), r.Len())
+
+			msg, err := f.Decode(ProtocolVersion, BaseEncoding)
+			require.NoError(t, err)
+			assert.Equal(t, want, msg)
+			assert.Equal(t, 0, r.Len())
+
+			_, err = f.Decode(ProtocolVersion, BaseEncoding)
+
+			var msgErr *MessageError
+			require.ErrorAs(t, err, &msgErr)
+		})
+	}
+}
+
+// TestReadFrameStreamingPayload ensures the payload of streaming frames can be
+// read raw or skipped, leaving the reader at the next message.
+func TestReadFrameStreamingPayload(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	block := encodeFrame(t, codec, &blockOne)
+	ping := encodeFrame(t, codec, NewMsgPing(7))
+
+	r := bytes.NewReader(concat(block, block, ping))
+
+	// Raw payload.
+	_, f, err := codec.ReadFrameStreaming(r)
+	require.NoError(t, err)
+
+	raw, err := io.ReadAll(f.Reader())
+	require.NoError(t, err)
+	assert.Equal(t, block[MessageHeaderSize:], raw)
+
+	skipped, err := f.Discard()
+	require.NoError(t, err)
+	assert.Equal(t, 0, skipped)
+
+	// Skipped payload.
+	_, f, err = codec.ReadFrameStreaming(r)
+	require.NoError(t, err)
+
+	skipped, err = f.Discard()
+	require.NoError(t, err)
+	assert.Equal(t, int(f.Length), skipped)
+
+	_, msg, _, err := codec.Read(r)
+	require.NoError(t, err)
+	assert.Equal(t, NewMsgPing(7), msg)
+
+	// A truncated payload is reported by Discard.
+	_, f, err = codec.ReadFrameStreaming(bytes.NewReader(block[:len(block)-1]))
+	require.NoError(t, err)
+
+	_, err = f.Discard()
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+// TestReadFrameErrors ensures frames are validated exactly like the eager read
+// path.
+func TestReadFrameErrors(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	ping := encodeFrame(t, codec, NewMsgPing(7))
+
+	badChecksum := bytes.Clone(ping)
+	badChecksum[MessageHeaderSize] ^= 0xff
+
+	unknown := encodeFrame(t, codec, &fakeMessage{command: "xunknown", payload: []byte{0x01}})
+	testNet := encodeFrame(t, NewCodec(TestNet, ProtocolVersion), NewMsgPing(7))
+
+	limited := NewCodec(MainNet, ProtocolVersion)
+	limited.SetLimits(1000)
+
+	block := encodeFrame(t, codec, &blockOne)
+
+	tests := []struct {
+		name  string
+		codec *Codec
+		frame []byte
+	}{
+		{"bad checksum", codec, badChecksum},
+		{"unknown command", codec, unknown},
+		{"other network", codec, testNet},
+		{"payload too large", limited, block},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, wantErr := tt.codec.Read(bytes.NewReader(tt.frame))
+			require.Error(t, wantErr)
+
+			_, _, err := tt.codec.ReadFrame(bytes.NewReader(tt.frame))
+			assert.Equal(t, wantErr, err)
+		})
+	}
+
+	// Checksum failures of streaming frames are reported by Decode.
+	_, f, err := codec.ReadFrameStreaming(bytes.NewReader(badChecksum))
+	require.NoError(t, err)
+
+	_, err = f.Decode(ProtocolVersion, BaseEncoding)
+
+	var msgErr *MessageError
+	require.ErrorAs(t, err, &msgErr)
+
+	// The per-type limit is checked again for the decoding protocol version.
+	_, f, err = ReadFrame(bytes.NewReader(encodeFrame(t, codec, NewMsgReject(CmdTx, RejectInvalid, "bad"))),
+		ProtocolVersion, MainNet)
+	require.NoError(t, err)
+
+	_, err = f.Decode(RejectVersion-1, BaseEncoding)
+	require.ErrorAs(t, err, &msgErr)
+
+	// Unknown commands decode to raw messages with passthrough enabled.
+	codec.SetUnknownPassthrough(true)
+
+	_, f, err = codec.ReadFrame(bytes.NewReader(unknown))
+	require.NoError(t, err)
+
+	msg, err := f.Decode(ProtocolVersion, BaseEncoding)
+	require.NoError(t, err)
+	assert.Equal(t, &MsgRaw{Cmd: "xunknown", Payload: []byte{0x01}, ChecksumVerified: true}, msg)
+}
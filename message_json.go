@@ -0,0 +1,375 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+// The types and functions in this file back the MarshalJSON and UnmarshalJSON
+// methods of the messages and of the types they are made of.  The JSON
+// representation follows the conventions of bitcoind's RPC interface: field
+// names are lower case, hashes are byte-reversed hex strings, scripts and other
+// byte arrays are hex strings, amounts are in BSV with eight decimals, and
+// service flags, inventory types and reject codes are named.  Every field which
+// is encoded on the wire is represented, so unmarshalling the JSON of a message
+// reproduces its BsvEncode bytes.  Fields which are computed from the others,
+// such as the txid of a transaction, are ignored when unmarshalling.
+
+// jsonMessage is the JSON representation of a message along with its command
+// used by MarshalMessageJSON and UnmarshalMessageJSON.
+type jsonMessage struct {
+	Command string          `json:"command"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// MarshalMessageJSON returns the JSON representation of msg wrapped in an
+// object along with its command, such as
+//
+//	{"command":"ping","payload":{"nonce":42}}
+//
+// so it can be turned back into a message of the right type by
+// UnmarshalMessageJSON.
+func MarshalMessageJSON(msg Message) ([]byte, error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jsonMessage{Command: msg.Command(), Payload: payload})
+}
+
+// UnmarshalMessageJSON returns the message represented by data, as produced by
+// MarshalMessageJSON.  The message type is looked up in the message registry
+// by its command, so commands added with RegisterMessage are supported as long
+// as their message type implements json.Unmarshaler or is otherwise
+// unmarshallable by encoding/json.
+func UnmarshalMessageJSON(data []byte) (Message, error) {
+	var jm jsonMessage
+	if err := json.Unmarshal(data, &jm); err != nil {
+		return nil, err
+	}
+
+	msg, err := makeEmptyMessage(jm.Command)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(jm.Payload) != 0 {
+		if err := json.Unmarshal(jm.Payload, msg); err != nil {
+			return nil, err
+		}
+	}
+
+	return msg, nil
+}
+
+// jsonError returns a MessageError for a JSON value which is well-formed but
+// does not represent a valid field.
+func jsonError(desc string) error {
+	return messageError("UnmarshalJSON", ErrMalformedMessage, desc)
+}
+
+// nonNil returns list, or an empty list when it is nil, so lists are
+// represented as JSON arrays rather than null.
+func nonNil[T any](list []T) []T {
+	if list == nil {
+		return []T{}
+	}
+
+	return list
+}
+
+// jsonHash is a hash represented in JSON as the byte-reversed hex string
+// returned by chainhash.Hash.String.
+type jsonHash chainhash.Hash
+
+// MarshalJSON returns the hash as a JSON string.
+func (h jsonHash) MarshalJSON() ([]byte, error) {
+	return json.Marshal(chainhash.Hash(h).String())
+}
+
+// UnmarshalJSON decodes the hash from a JSON string.
+func (h *jsonHash) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if len(s) != chainhash.MaxHashStringSize {
+		return jsonError(fmt.Sprintf("hash %q is not %d hex characters",
+			s, chainhash.MaxHashStringSize))
+	}
+
+	hash, err := chainhash.NewHashFromStr(s)
+	if err != nil {
+		return jsonError(fmt.Sprintf("invalid hash %q: %v", s, err))
+	}
+
+	*h = jsonHash(*hash)
+
+	return nil
+}
+
+// toJSONHashes converts a list of hashes to their JSON representation.  A nil
+// list is represented as an empty list.
+func toJSONHashes(hashes []*chainhash.Hash) []jsonHash {
+	list := make([]jsonHash, len(hashes))
+	for i, h := range hashes {
+		list[i] = jsonHash(*h)
+	}
+
+	return list
+}
+
+// fromJSONHashes converts a list of hashes from their JSON representation.
+func fromJSONHashes(list []jsonHash) []*chainhash.Hash {
+	backing := make([]chainhash.Hash, len(list))
+	hashes := make([]*chainhash.Hash, len(list))
+
+	for i := range list {
+		backing[i] = chainhash.Hash(list[i])
+		hashes[i] = &backing[i]
+	}
+
+	return hashes
+}
+
+// jsonHex is a byte array represented in JSON as a hex string.
+type jsonHex []byte
+
+// MarshalJSON returns the bytes as a JSON hex string.
+func (b jsonHex) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(b))
+}
+
+// UnmarshalJSON decodes the bytes from a JSON hex string.
+func (b *jsonHex) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	decoded, err := hex.DecodeString(s)
+	if err != nil {
+		return jsonError(fmt.Sprintf("invalid hex string %q: %v", s, err))
+	}
+
+	*b = decoded
+
+	return nil
+}
+
+// jsonScript is a script represented in JSON as an object holding its hex
+// encoding, like the scriptSig and scriptPubKey of bitcoind.
+type jsonScript struct {
+	Hex jsonHex `json:"hex"`
+}
+
+// jsonAmount is an amount in satoshis represented in JSON as a number of BSV
+// with eight decimals.  It is formatted and parsed without going through a
+// float so every amount round-trips exactly.
+type jsonAmount int64
+
+// MarshalJSON returns the amount as a JSON number of BSV.
+func (a jsonAmount) MarshalJSON() ([]byte, error) {
+	sign := ""
+	u := uint64(a)
+
+	if a < 0 {
+		sign = "-"
+		u = -u
+	}
+
+	return fmt.Appendf(nil, "%s%d.%08d", sign, u/1e8, u%1e8), nil
+}
+
+// UnmarshalJSON decodes the amount from a JSON number of BSV with at most
+// eight decimals.
+func (a *jsonAmount) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+
+	s := string(n)
+	if strings.ContainsAny(s, "eE") {
+		return jsonError(fmt.Sprintf("amount %s uses an exponent", s))
+	}
+
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > 8 {
+		return jsonError(fmt.Sprintf("amount %s has more than 8 decimals", n))
+	}
+
+	frac += strings.Repeat("0", 8-len(frac))
+
+	// The magnitude of the most negative amount is one larger than that of
+	// the most positive one.
+	limit := uint64(math.MaxInt64)
+	if neg {
+		limit++
+	}
+
+	sats, err := strconv.ParseUint(whole+frac, 10, 64)
+	if err != nil || sats > limit {
+		return jsonError(fmt.Sprintf("amount %s out of range", n))
+	}
+
+	*a = jsonAmount(sats)
+	if neg {
+		*a = -*a
+	}
+
+	return nil
+}
+
+// jsonServices is a set of service flags represented in JSON as a list of
+// their names, such as ["SFNodeNetwork","SFNodeBloom"].  Flags without a name
+// are represented as a single hex string such as "0x400".
+type jsonServices ServiceFlag
+
+// MarshalJSON returns the service flags as a JSON list of names.
+func (f jsonServices) MarshalJSON() ([]byte, error) {
+	names := make([]string, 0, len(orderedSFStrings)+1)
+
+	rest := ServiceFlag(f)
+	for _, flag := range orderedSFStrings {
+		if rest&flag == flag {
+			names = append(names, sfStrings[flag])
+			rest &^= flag
+		}
+	}
+
+	if rest != 0 {
+		names = append(names, "0x"+strconv.FormatUint(uint64(rest), 16))
+	}
+
+	return json.Marshal(names)
+}
+
+// UnmarshalJSON decodes the service flags from a JSON list of names.
+func (f *jsonServices) UnmarshalJSON(data []byte) error {
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+
+	var flags ServiceFlag
+
+	for _, name := range names {
+		if hexFlags, ok := strings.CutPrefix(name, "0x"); ok {
+			v, err := strconv.ParseUint(hexFlags, 16, 64)
+			if err != nil {
+				return jsonError(fmt.Sprintf("invalid service flags %q", name))
+			}
+
+			flags |= ServiceFlag(v)
+
+			continue
+		}
+
+		flag, ok := lookupName(sfStrings, name)
+		if !ok {
+			return jsonError(fmt.Sprintf("unknown service flag %q", name))
+		}
+
+		flags |= flag
+	}
+
+	*f = jsonServices(flags)
+
+	return nil
+}
+
+// jsonInvType is an inventory type represented in JSON by its name, such as
+// "MSG_TX", or by its number when it has no name.
+type jsonInvType InvType
+
+// MarshalJSON returns the inventory type as a JSON name or number.
+func (t jsonInvType) MarshalJSON() ([]byte, error) {
+	return marshalNamed(InvType(t), ivStrings)
+}
+
+// UnmarshalJSON decodes the inventory type from a JSON name or number.
+func (t *jsonInvType) UnmarshalJSON(data []byte) error {
+	return unmarshalNamed(data, (*InvType)(t), ivStrings, "inv type")
+}
+
+// jsonRejectCode is a reject code represented in JSON by its name, such as
+// "REJECT_DUPLICATE", or by its number when it has no name.
+type jsonRejectCode RejectCode
+
+// MarshalJSON returns the reject code as a JSON name or number.
+func (c jsonRejectCode) MarshalJSON() ([]byte, error) {
+	return marshalNamed(RejectCode(c), rejectCodeStrings)
+}
+
+// UnmarshalJSON decodes the reject code from a JSON name or number.
+func (c *jsonRejectCode) UnmarshalJSON(data []byte) error {
+	return unmarshalNamed(data, (*RejectCode)(c), rejectCodeStrings, "reject code")
+}
+
+// marshalNamed returns v as its name in names or, when it has none, as a
+// number.
+func marshalNamed[T ~uint8 | ~uint32](v T, names map[T]string) ([]byte, error) {
+	if name, ok := names[v]; ok {
+		return json.Marshal(name)
+	}
+
+	return json.Marshal(uint64(v))
+}
+
+// unmarshalNamed decodes v from either its name in names or a number.  what
+// names the kind of value in errors.
+func unmarshalNamed[T ~uint8 | ~uint32](data []byte, v *T, names map[T]string, what string) error {
+	var n uint64
+	if err := json.Unmarshal(data, &n); err == nil {
+		if uint64(T(n)) != n {
+			return jsonError(fmt.Sprintf("%s %d out of range", what, n))
+		}
+
+		*v = T(n)
+
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	named, ok := lookupName(names, name)
+	if !ok {
+		return jsonError(fmt.Sprintf("unknown %s %q", what, name))
+	}
+
+	*v = named
+
+	return nil
+}
+
+// lookupName returns the value which has name in names.
+func lookupName[T comparable](names map[T]string, name string) (T, bool) {
+	for v, n := range names {
+		if n == name {
+			return v, true
+		}
+	}
+
+	var zero T
+
+	return zero, false
+}
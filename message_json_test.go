@@ -0,0 +1,280 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMessageJSON ensures every message round-trips through its JSON
+// representation to identical BsvEncode bytes and an identical
+// representation.
+func TestMessageJSON(t *testing.T) {
+	msgs := sampleMessages(t)
+	msgs["raw"] = &MsgRaw{Cmd: "custom", Payload: []byte{0x01, 0x02}}
+
+	for name, msg := range msgs {
+		t.Run(name, func(t *testing.T) {
+			var want bytes.Buffer
+			require.NoError(t, msg.BsvEncode(&want, ProtocolVersion, LatestEncoding))
+
+			b, err := json.Marshal(msg)
+			require.NoError(t, err)
+			require.True(t, json.Valid(b))
+
+			decoded := newMessageOfType(t, msg)
+			require.NoError(t, json.Unmarshal(b, decoded))
+
+			var got bytes.Buffer
+			require.NoError(t, decoded.BsvEncode(&got, ProtocolVersion, LatestEncoding))
+			assert.Equal(t, want.Bytes(), got.Bytes())
+
+			again, err := json.Marshal(decoded)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(b), string(again))
+		})
+	}
+}
+
+// newMessageOfType returns a new zero message of the concrete type of msg.
+func newMessageOfType(t *testing.T, msg Message) Message {
+	t.Helper()
+
+	if _, ok := msg.(*MsgRaw); ok {
+		return &MsgRaw{}
+	}
+
+	empty, err := makeEmptyMessage(msg.Command())
+	require.NoError(t, err)
+
+	return empty
+}
+
+// TestMarshalMessageJSON ensures messages wrapped along with their command
+// are turned back into messages of the right type.
+func TestMarshalMessageJSON(t *testing.T) {
+	b, err := MarshalMessageJSON(NewMsgPing(42))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"command":"ping","payload":{"nonce":42}}`, string(b))
+
+	msg, err := UnmarshalMessageJSON(b)
+	require.NoError(t, err)
+	assert.Equal(t, NewMsgPing(42), msg)
+
+	msg, err = UnmarshalMessageJSON([]byte(`{"command":"verack"}`))
+	require.NoError(t, err)
+	assert.Equal(t, NewMsgVerAck(), msg)
+
+	_, err = UnmarshalMessageJSON([]byte(`{"command":"bogus","payload":{}}`))
+	require.ErrorIs(t, err, ErrUnknownCommand)
+
+	_, err = UnmarshalMessageJSON([]byte(`{"command":"ping","payload":{"nonce":"x"}}`))
+	require.Error(t, err)
+}
+
+// TestMsgTxJSON ensures transactions are represented like by the
+// decoderawtransaction RPC of bitcoind.
+func TestMsgTxJSON(t *testing.T) {
+	b, err := json.Marshal(blockOne.Transactions[0])
+	require.NoError(t, err)
+
+	want := `{
+		"txid": "0e3e2357e806b6cdb1f70b54c3a3a17b6714ee1f0e68bebb44a74b1efd512098",
+		"hash": "0e3e2357e806b6cdb1f70b54c3a3a17b6714ee1f0e68bebb44a74b1efd512098",
+		"version": 1,
+		"size": 134,
+		"locktime": 0,
+		"vin": [{"coinbase": "04ffff001d0104", "sequence": 4294967295}],
+		"vout": [{
+			"value": 50.00000000,
+			"n": 0,
+			"scriptPubKey": {"hex": "410496b538e853519c726a2c91e61ec11600ae1390813a627c66fb8be7947be63c52da7589379515d4e0a604f8141781e62294721166bf621e73a82cbf2342c858eeac"}
+		}]
+	}`
+	assert.JSONEq(t, want, string(b))
+
+	// Inputs spending a regular outpoint carry it along with their script.
+	b, err = json.Marshal(NewTxIn(NewOutPoint(&mainNetGenesisHash, 1), []byte{0x51}))
+	require.NoError(t, err)
+
+	var in map[string]any
+	require.NoError(t, json.Unmarshal(b, &in))
+	assert.Contains(t, in, "txid")
+	assert.Contains(t, in, "vout")
+	assert.Contains(t, in, "scriptSig")
+	assert.NotContains(t, in, "coinbase")
+
+	// Inputs must have either a coinbase or an outpoint.
+	var ti TxIn
+	require.ErrorIs(t, json.Unmarshal([]byte(`{"sequence":1}`), &ti), ErrMalformedMessage)
+}
+
+// TestTypeJSON ensures the types messages are made of have readable,
+// round-trippable representations.
+func TestTypeJSON(t *testing.T) {
+	hash := mainNetGenesisHash
+
+	tests := []struct {
+		name  string
+		in    any
+		want  string
+		empty func() any
+	}{
+		{
+			name: "InvVect",
+			in:   NewInvVect(InvTypeBlock, &hash),
+			want: `{"type":"MSG_BLOCK","hash":"` + hash.String() + `"}`,
+			empty: func() any {
+				return &InvVect{}
+			},
+		},
+		{
+			name: "InvVect unknown type",
+			in:   NewInvVect(InvType(99), &hash),
+			want: `{"type":99,"hash":"` + hash.String() + `"}`,
+			empty: func() any {
+				return &InvVect{}
+			},
+		},
+		{
+			name: "OutPoint",
+			in:   NewOutPoint(&hash, 3),
+			want: `{"txid":"` + hash.String() + `","vout":3}`,
+			empty: func() any {
+				return &OutPoint{}
+			},
+		},
+		{
+			name: "NetAddress",
+			in: &NetAddress{
+				Timestamp: time.Unix(0, 0),
+				Services:  SFNodeNetwork | SFNodeBloom | 1<<20,
+				IP:        []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0xff, 0xff, 127, 0, 0, 1},
+				Port:      8333,
+			},
+			want: `{"timestamp":0,"services":["SFNodeNetwork","SFNodeBloom","0x100000"],` +
+				`"ip":"127.0.0.1","port":8333}`,
+			empty: func() any {
+				return &NetAddress{}
+			},
+		},
+		{
+			name: "TxOut",
+			in:   NewTxOut(1, []byte{0x51}),
+			want: `{"value":0.00000001,"scriptPubKey":{"hex":"51"}}`,
+			empty: func() any {
+				return &TxOut{}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b, err := json.Marshal(test.in)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.want, string(b))
+
+			out := test.empty()
+			require.NoError(t, json.Unmarshal(b, out))
+
+			again, err := json.Marshal(out)
+			require.NoError(t, err)
+			assert.JSONEq(t, test.want, string(again))
+		})
+	}
+
+	// Block headers follow getblockheader and compute their hash.
+	header := blockOne.Header
+
+	b, err := json.Marshal(header)
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(b, &fields))
+	assert.Equal(t, header.BlockHash().String(), fields["hash"])
+	assert.Equal(t, "1d00ffff", fields["bits"])
+
+	var decoded BlockHeader
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, header.BlockHash(), decoded.BlockHash())
+}
+
+// TestJSONAmount ensures amounts are represented in BSV and parsed exactly.
+func TestJSONAmount(t *testing.T) {
+	tests := []struct {
+		sats int64
+		want string
+	}{
+		{0, "0.00000000"},
+		{1, "0.00000001"},
+		{-1, "-0.00000001"},
+		{5000000000, "50.00000000"},
+		{2100000000000000, "21000000.00000000"},
+		{math.MaxInt64, "92233720368.54775807"},
+		{math.MinInt64, "-92233720368.54775808"},
+	}
+
+	for _, test := range tests {
+		b, err := json.Marshal(jsonAmount(test.sats))
+		require.NoError(t, err)
+		assert.Equal(t, test.want, string(b))
+
+		var a jsonAmount
+		require.NoError(t, json.Unmarshal(b, &a))
+		assert.Equal(t, test.sats, int64(a))
+	}
+
+	// Fewer decimals are allowed.
+	parsed := map[string]int64{"50": 5000000000, "0.1": 10000000, "-2.5": -250000000}
+	for in, want := range parsed {
+		var a jsonAmount
+		require.NoError(t, json.Unmarshal([]byte(in), &a))
+		assert.Equal(t, want, int64(a), in)
+	}
+
+	for _, in := range []string{"1e8", "0.000000001", "92233720368.54775808", "true"} {
+		var a jsonAmount
+		require.Error(t, json.Unmarshal([]byte(in), &a), in)
+	}
+}
+
+// TestJSONErrors ensures malformed field values are rejected.
+func TestJSONErrors(t *testing.T) {
+	hash := mainNetGenesisHash.String()
+
+	tests := []struct {
+		name string
+		data string
+		msg  any
+	}{
+		{"short hash", `{"type":"MSG_TX","hash":"00"}`, &InvVect{}},
+		{"bad hash", `{"type":"MSG_TX","hash":"` + hash[:63] + `z"}`, &InvVect{}},
+		{"unknown inv type", `{"type":"MSG_BOGUS","hash":"` + hash + `"}`, &InvVect{}},
+		{"inv type out of range", `{"type":4294967296,"hash":"` + hash + `"}`, &InvVect{}},
+		{"unknown service", `{"services":["SFNodeBogus"],"ip":"::","port":1}`, &NetAddress{}},
+		{"bad service bits", `{"services":["0xzz"],"ip":"::","port":1}`, &NetAddress{}},
+		{"bad ip", `{"services":[],"ip":"1.2.3","port":1}`, &NetAddress{}},
+		{"bad hex", `{"data":"0g"}`, &MsgFilterAdd{}},
+		{"bad bits", `{"hash":"` + hash + `","previousblockhash":"` + hash +
+			`","merkleroot":"` + hash + `","bits":"xyz"}`, &BlockHeader{}},
+		{"unknown reject code", `{"command":"tx","code":"REJECT_BOGUS","reason":""}`, &MsgReject{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(test.data), test.msg)
+			require.ErrorIs(t, err, ErrMalformedMessage)
+		})
+	}
+
+	require.Error(t, json.Unmarshal([]byte(`[]`), &MsgVerAck{}))
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// payloadSizeVersions are the protocol versions at which the payload size of
+// messages is checked: every version which changed an encoding, the versions
+// right before them and the oldest.
+var payloadSizeVersions = []uint32{
+	0,
+	MultipleAddressVersion - 1, MultipleAddressVersion,
+	NetAddressTimeVersion - 1, NetAddressTimeVersion,
+	BIP0031Version, BIP0031Version + 1,
+	BIP0035Version - 1, BIP0035Version,
+	BIP0037Version - 1, BIP0037Version,
+	RejectVersion - 1, RejectVersion,
+	BIP0111Version, SendHeadersVersion - 1, SendHeadersVersion,
+	FeeFilterVersion - 1, FeeFilterVersion,
+	ExtMsgVersion - 1, ExtMsgVersion,
+}
+
+// boundaryMessages returns messages whose lists and variable length fields
+// are sized to cross the boundaries of the variable length integer encoding.
+func boundaryMessages(t *testing.T) []Message {
+	t.Helper()
+
+	var msgs []Message
+
+	hash := mainNetGenesisHash
+	long := strings.Repeat("x", 0xfd)
+
+	for _, count := range []int{0, 1, 0xfc, 0xfd} {
+		inv := NewMsgInv()
+		getData := NewMsgGetData()
+		notFound := NewMsgNotFound()
+		addr := NewMsgAddr()
+		getBlocks := NewMsgGetBlocks(&hash)
+		getHeaders := NewMsgGetHeaders()
+		headers := NewMsgHeaders()
+		cfHeaders := NewMsgCFHeaders()
+		cfCheckpt := NewMsgCFCheckpt(GCSFilterRegular, &hash, count)
+		merkleBlock := NewMsgMerkleBlock(&blockOne.Header)
+		merkleBlock.Flags = make([]byte, count)
+		block := NewMsgBlock(&blockOne.Header)
+
+		for i := 0; i < count; i++ {
+			require.NoError(t, inv.AddInvVect(NewInvVect(InvTypeTx, &hash)))
+			require.NoError(t, getData.AddInvVect(NewInvVect(InvTypeTx, &hash)))
+			require.NoError(t, notFound.AddInvVect(NewInvVect(InvTypeTx, &hash)))
+			require.NoError(t, addr.AddAddress(NewNetAddressIPPort(net.ParseIP("::1"), 8333, 0)))
+			require.NoError(t, cfHeaders.AddCFHash(&hash))
+			require.NoError(t, cfCheckpt.AddCFHeader(&hash))
+			require.NoError(t, merkleBlock.AddTxHash(&hash))
+			require.NoError(t, block.AddTransaction(multiTx))
+
+			if i < MaxBlockLocatorsPerMsg {
+				require.NoError(t, getBlocks.AddBlockLocatorHash(&hash))
+				require.NoError(t, getHeaders.AddBlockLocatorHash(&hash))
+			}
+
+			if i < MaxBlockHeadersPerMsg {
+				require.NoError(t, headers.AddBlockHeader(&blockOne.Header))
+			}
+		}
+
+		msgs = append(msgs, inv, getData, notFound, addr, getBlocks, getHeaders,
+			headers, cfHeaders, cfCheckpt, merkleBlock, block)
+	}
+
+	for _, s := range []string{"", "x", long[:0xfc], long} {
+		b := []byte(s)
+
+		version := NewMsgVersion(&NetAddress{}, &NetAddress{}, 1, 1)
+		version.UserAgent = s
+		version.AssociationID = b
+
+		reject := NewMsgReject(CmdTx, RejectInvalid, s)
+		protoconf := NewMsgProtoconf(0, len(s)%2 == 0)
+		protoconf.StreamPolicies = append(protoconf.StreamPolicies, s)
+
+		msgs = append(msgs, version, reject, protoconf,
+			NewMsgReject(CmdInv, RejectInvalid, s),
+			NewMsgFilterAdd(b),
+			NewMsgFilterLoad(b, 1, 2, BloomUpdateNone),
+			NewMsgCFilter(GCSFilterRegular, &hash, b),
+			NewMsgCreateStream(append([]byte{0x01}, b[:min(len(b), 0x7f)]...), StreamTypeGeneral, s),
+			NewMsgStreamAck(b, StreamTypeGeneral),
+			NewMsgAuthch(s),
+			NewMsgRaw(CmdTx, b),
+		)
+	}
+
+	return append(msgs, &MsgProtoconf{NumberOfFields: 1, MaxRecvPayloadLength: 1})
+}
+
+// TestPayloadSize ensures the payload size of every message equals the length
+// of its encoding at every protocol version it can be encoded at.
+func TestPayloadSize(t *testing.T) {
+	msgs := boundaryMessages(t)
+	for _, msg := range sampleMessages(t) {
+		msgs = append(msgs, msg)
+	}
+
+	checked := make(map[string]bool)
+
+	for _, msg := range msgs {
+		ps, ok := msg.(PayloadSizer)
+		require.True(t, ok, "%s does not implement PayloadSizer", msg.Command())
+
+		for _, pver := range payloadSizeVersions {
+			var buf bytes.Buffer
+			if err := msg.BsvEncode(&buf, pver, BaseEncoding); err != nil {
+				continue
+			}
+
+			assert.Equal(t, buf.Len(), ps.PayloadSize(pver),
+				"%s at protocol version %d", msg.Command(), pver)
+
+			checked[msg.Command()] = true
+		}
+	}
+
+	for _, cmd := range RegisteredCommands() {
+		assert.True(t, checked[cmd], "payload size of %s not checked", cmd)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"io"
+	"reflect"
+	"sync"
+)
+
+// Resetter is implemented by messages which can be reset to their zero state
+// while keeping the memory they allocated, so codecs with message pooling
+// enabled can decode into them again without allocating.  Every message
+// implemented by this package implements it.
+type Resetter interface {
+	Reset()
+}
+
+// messagePool is a pool of messages of a single concrete type.
+type messagePool struct {
+	typ  reflect.Type
+	pool sync.Pool
+}
+
+// messagePools maps each command to the pool of its messages.  Pools are
+// created on first use and dropped when the registration of their command
+// changes.
+var messagePools sync.Map // map[string]*messagePool
+
+// acquireMessage returns a message for command from its pool, falling back to
+// makeEmptyMessage when the pool is empty.
+func acquireMessage(command string) (Message, error) {
+	if mp, ok := messagePools.Load(command); ok {
+		if msg, ok := mp.(*messagePool).pool.Get().(Message); ok {
+			return msg, nil
+		}
+	}
+
+	msg, err := makeEmptyMessage(command)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := msg.(Resetter); ok {
+		messagePools.LoadOrStore(command, &messagePool{typ: reflect.TypeOf(msg)})
+	}
+
+	return msg, nil
+}
+
+// Release resets msg and returns it to the pool used by codecs with message
+// pooling enabled, so a later read can reuse it along with the memory it
+// allocated.  See Codec.SetMessagePooling.
+//
+// The caller must not use msg, nor anything it references such as its
+// inventory vectors or transaction inputs, after releasing it.  Messages which
+// do not implement Resetter, or whose command has not been read by a pooling
+// codec, are left to the garbage collector.
+//
+// This function is safe for concurrent access.
+func Release(msg Message) {
+	r, ok := msg.(Resetter)
+	if !ok {
+		return
+	}
+
+	mp, ok := messagePools.Load(msg.Command())
+	if !ok || mp.(*messagePool).typ != reflect.TypeOf(msg) {
+		return
+	}
+
+	r.Reset()
+	mp.(*messagePool).pool.Put(msg)
+}
+
+// dropMessagePool drops the pool of command so messages created by a previous
+// registration are not handed out for it.
+func dropMessagePool(command string) {
+	messagePools.Delete(command)
+}
+
+// recycling is embedded by the readers payloads are decoded from and is set
+// when the message decoded from them was taken from its pool, which makes the
+// readers implement the recycler interface.
+type recycling bool
+
+// recycler is implemented by readers which report whether the message decoded
+// from them may reuse the elements its lists kept on Reset.
+type recycler interface {
+	recycles() bool
+}
+
+// recycles returns r.  This is part of the recycler interface implementation.
+func (r recycling) recycles() bool {
+	return bool(r)
+}
+
+// recyclingOf returns whether the message decoded from r may reuse the
+// elements its lists kept on Reset.  Only messages read by codecs with message
+// pooling enabled do, since any other message may have been truncated by a
+// caller which still holds its elements.
+func recyclingOf(r io.Reader) bool {
+	rc, ok := r.(recycler)
+	return ok && rc.recycles()
+}
+
+// recycleList returns a list of count elements to decode into.  When recycle
+// is set and list is empty but has room for count elements, as kept by Reset,
+// the list and the elements it points to beyond its length are reset and
+// reused; any elements still missing are allocated contiguously to reduce the
+// number of allocations.  Otherwise a new list is allocated.
+func recycleList[T any](list []*T, count uint64, recycle bool) []*T {
+	if !recycle || len(list) != 0 || cap(list) == 0 || uint64(cap(list)) < count {
+		backing := make([]T, count)
+		list = make([]*T, count)
+
+		for i := range list {
+			list[i] = &backing[i]
+		}
+
+		return list
+	}
+
+	list = list[:count]
+
+	var missing int
+
+	for _, e := range list {
+		if e == nil {
+			missing++
+			continue
+		}
+
+		if r, ok := any(e).(Resetter); ok {
+			r.Reset()
+		} else {
+			var zero T
+			*e = zero
+		}
+	}
+
+	if missing != 0 {
+		backing := make([]T, missing)
+
+		for i, e := range list {
+			if e == nil {
+				list[i] = &backing[0]
+				backing = backing[1:]
+			}
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,261 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testInv returns an inv message with count transaction inventory vectors.
+func testInv(t *testing.T, count int) *MsgInv {
+	t.Helper()
+
+	inv := NewMsgInv()
+
+	for i := 0; i < count; i++ {
+		hash := chainhash.Hash{byte(i + 1)}
+		require.NoError(t, inv.AddInvVect(NewInvVect(InvTypeTx, &hash)))
+	}
+
+	return inv
+}
+
+// TestMessageReset ensures every registered message implements Resetter and
+// is reset to its zero state.
+func TestMessageReset(t *testing.T) {
+	for _, cmd := range RegisteredCommands() {
+		msg, err := makeEmptyMessage(cmd)
+		require.NoError(t, err)
+
+		r, ok := msg.(Resetter)
+		require.True(t, ok, "%s does not implement Resetter", cmd)
+
+		r.Reset()
+		assert.Equal(t, cmd, msg.Command())
+	}
+
+	tx := multiTx.Copy()
+	tx.Reset()
+	assert.Zero(t, tx.Version)
+	assert.Empty(t, tx.TxIn)
+	assert.Empty(t, tx.TxOut)
+	assert.Equal(t, len(multiTx.TxIn), cap(tx.TxIn))
+
+	// The kept capacity is the only state a reset leaves behind.
+	assert.Equal(t, &MsgTx{TxIn: tx.TxIn, TxOut: tx.TxOut}, tx)
+
+	version := NewMsgVersion(&NetAddress{}, &NetAddress{}, 123, 0)
+	version.Reset()
+	assert.Equal(t, &MsgVersion{}, version)
+}
+
+// TestMessageResetReuse ensures only pooled decodes into a reset message reuse
+// the elements it kept, while any other decode never touches elements which
+// may still be referenced.
+func TestMessageResetReuse(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, testInv(t, 2).BsvEncode(&buf, ProtocolVersion, BaseEncoding))
+	small := bytes.Clone(buf.Bytes())
+
+	buf.Reset()
+	require.NoError(t, testInv(t, 3).BsvEncode(&buf, ProtocolVersion, BaseEncoding))
+	large := bytes.Clone(buf.Bytes())
+
+	buf.Reset()
+	blockInv := NewMsgInv()
+	require.NoError(t, blockInv.AddInvVect(NewInvVect(InvTypeBlock, &chainhash.Hash{0xbb})))
+	require.NoError(t, blockInv.BsvEncode(&buf, ProtocolVersion, BaseEncoding))
+	other := bytes.Clone(buf.Bytes())
+
+	pooled := codecParams{pver: ProtocolVersion, enc: BaseEncoding, pooled: true}
+
+	var inv MsgInv
+
+	require.NoError(t, inv.Bsvdecode(bytes.NewReader(large), ProtocolVersion, BaseEncoding))
+	first := inv.InvList[0]
+	firstHash := first.Hash
+
+	// Decoding again without a reset leaves the previous elements alone.
+	require.NoError(t, inv.Bsvdecode(bytes.NewReader(small), ProtocolVersion, BaseEncoding))
+	assert.NotSame(t, first, inv.InvList[0])
+	assert.Equal(t, firstHash, first.Hash)
+
+	// So does decoding after the list was truncated or the message reset
+	// outside of a pooled read.
+	truncations := map[string]func(){
+		"truncate": func() { inv.InvList = inv.InvList[:0] },
+		"reset":    inv.Reset,
+	}
+
+	for name, truncate := range truncations {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, inv.Bsvdecode(bytes.NewReader(small), ProtocolVersion, BaseEncoding))
+			held := inv.InvList[0]
+			want := *held
+
+			truncate()
+			require.NoError(t, inv.Bsvdecode(bytes.NewReader(other), ProtocolVersion, BaseEncoding))
+			assert.NotSame(t, held, inv.InvList[0])
+			assert.Equal(t, want, *held)
+			assert.Equal(t, blockInv, &inv)
+		})
+	}
+
+	// Pooled decodes after a reset reuse them.
+	require.NoError(t, inv.Bsvdecode(bytes.NewReader(small), ProtocolVersion, BaseEncoding))
+	kept := inv.InvList[0]
+
+	inv.Reset()
+	require.NoError(t, decodePayload(&inv, small, pooled))
+	assert.Same(t, kept, inv.InvList[0])
+	assert.Equal(t, testInv(t, 2), &inv)
+
+	// Lists which outgrew the kept capacity are allocated afresh.
+	inv.Reset()
+	require.NoError(t, decodePayload(&inv, large, pooled))
+	assert.NotSame(t, kept, inv.InvList[0])
+	assert.Equal(t, testInv(t, 3), &inv)
+
+	// Elements added after a reset are never reused.
+	own := NewInvVect(InvTypeBlock, &chainhash.Hash{0xff})
+
+	inv.Reset()
+	require.NoError(t, inv.AddInvVect(own))
+	require.NoError(t, decodePayload(&inv, small, pooled))
+	assert.Equal(t, InvTypeBlock, own.Type)
+	assert.Equal(t, testInv(t, 2), &inv)
+}
+
+// TestMessageResetReuseNested ensures blocks and transactions reuse their
+// transactions, inputs and outputs after a reset on every pooled decode path.
+func TestMessageResetReuseNested(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, blockOne.BsvEncode(&buf, ProtocolVersion, BaseEncoding))
+	blockBytes := bytes.Clone(buf.Bytes())
+
+	buf.Reset()
+	require.NoError(t, multiTx.BsvEncode(&buf, ProtocolVersion, BaseEncoding))
+	txBytes := buf.Bytes()
+
+	pooled := codecParams{pver: ProtocolVersion, enc: BaseEncoding, pooled: true}
+
+	var block MsgBlock
+
+	require.NoError(t, block.Bsvdecode(bytes.NewReader(blockBytes), ProtocolVersion, BaseEncoding))
+	tx, txIn := block.Transactions[0], block.Transactions[0].TxIn[0]
+
+	// Decoding without pooling allocates afresh.
+	block.Reset()
+	require.NoError(t, block.FromBytes(blockBytes))
+	assert.NotSame(t, tx, block.Transactions[0])
+	assert.Equal(t, &blockOne, &block)
+
+	tx, txIn = block.Transactions[0], block.Transactions[0].TxIn[0]
+
+	block.Reset()
+	require.NoError(t, decodeAliasPayload(&block, CmdBlock, blockBytes, pooled))
+	assert.Same(t, tx, block.Transactions[0])
+	assert.Same(t, txIn, block.Transactions[0].TxIn[0])
+	assert.Equal(t, &blockOne, &block)
+
+	block.Reset()
+	require.NoError(t, decodePayload(&block, blockBytes, pooled))
+	assert.Same(t, tx, block.Transactions[0])
+	assert.Same(t, txIn, block.Transactions[0].TxIn[0])
+	assert.Equal(t, &blockOne, &block)
+
+	var msg MsgTx
+
+	require.NoError(t, msg.Bsvdecode(bytes.NewReader(txBytes), ProtocolVersion, BaseEncoding))
+	txOut := msg.TxOut[1]
+
+	msg.Reset()
+	require.NoError(t, decodePayload(&msg, txBytes, pooled))
+	assert.Same(t, txOut, msg.TxOut[1])
+	assert.Equal(t, multiTx, &msg)
+}
+
+// TestMessagePooling ensures codecs with message pooling enabled read into
+// released messages.
+func TestMessagePooling(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	codec.SetMessagePooling(true)
+	assert.True(t, codec.MessagePooling())
+
+	frame := encodeFrame(t, codec, testInv(t, 3))
+
+	intercepted := NewCodec(MainNet, ProtocolVersion)
+	intercepted.SetMessagePooling(true)
+	intercepted.InterceptRead(func(f *Frame, next ReadHandler) (Message, error) {
+		return next(f)
+	})
+
+	reads := map[string]func() (Message, error){
+		"DecodeBytes": func() (Message, error) {
+			_, msg, _, err := codec.DecodeBytes(frame)
+			return msg, err
+		},
+		"ReadStreaming": func() (Message, error) {
+			_, msg, err := codec.ReadStreaming(bytes.NewReader(frame))
+			return msg, err
+		},
+		"ReadFrame": func() (Message, error) {
+			_, f, err := codec.ReadFrame(bytes.NewReader(frame))
+			require.NoError(t, err)
+
+			return f.Decode(ProtocolVersion, BaseEncoding)
+		},
+		"ReadFrameStreaming": func() (Message, error) {
+			_, f, err := codec.ReadFrameStreaming(bytes.NewReader(frame))
+			require.NoError(t, err)
+
+			return f.Decode(ProtocolVersion, BaseEncoding)
+		},
+		"interceptor": func() (Message, error) {
+			_, msg, _, err := intercepted.Read(bytes.NewReader(frame))
+			return msg, err
+		},
+		"streaming interceptor": func() (Message, error) {
+			_, msg, err := intercepted.ReadStreaming(bytes.NewReader(frame))
+			return msg, err
+		},
+	}
+
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			// sync.Pool may drop released messages at any time, so
+			// retry.
+			reused := false
+
+			for i := 0; i < 20 && !reused; i++ {
+				msg, err := read()
+				require.NoError(t, err)
+				assert.Equal(t, testInv(t, 3), msg)
+
+				Release(msg)
+
+				again, err := read()
+				require.NoError(t, err)
+				assert.Equal(t, testInv(t, 3), again)
+
+				reused = again == msg
+			}
+
+			assert.True(t, reused)
+		})
+	}
+
+	// Messages of other types are not pooled for the command.
+	Release(&MsgRaw{Cmd: CmdInv})
+
+	_, msg, _, err := codec.DecodeBytes(frame)
+	require.NoError(t, err)
+	assert.IsType(t, &MsgInv{}, msg)
+}
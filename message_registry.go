@@ -0,0 +1,153 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"unicode/utf8"
+)
+
+// MessageConstructor returns a new, empty Message of a concrete type which is
+// ready to have a payload decoded into it via Bsvdecode.
+type MessageConstructor func() Message
+
+// registryMtx protects messageRegistry.
+var registryMtx sync.RWMutex
+
+// messageRegistry maps each known command to the constructor used to create
+// an empty message of the appropriate concrete type when reading messages.
+// It is pre-populated with every message implemented by this package and may
+// be extended at runtime via RegisterMessage.
+var messageRegistry = map[string]MessageConstructor{
+	CmdVersion:      func() Message { return &MsgVersion{} },
+	CmdVerAck:       func() Message { return &MsgVerAck{} },
+	CmdGetAddr:      func() Message { return &MsgGetAddr{} },
+	CmdAddr:         func() Message { return &MsgAddr{} },
+	CmdGetBlocks:    func() Message { return &MsgGetBlocks{} },
+	CmdBlock:        func() Message { return &MsgBlock{} },
+	CmdInv:          func() Message { return &MsgInv{} },
+	CmdGetData:      func() Message { return &MsgGetData{} },
+	CmdNotFound:     func() Message { return &MsgNotFound{} },
+	CmdTx:           func() Message { return &MsgTx{} },
+	CmdExtendedTx:   func() Message { return &MsgExtendedTx{} },
+	CmdPing:         func() Message { return &MsgPing{} },
+	CmdPong:         func() Message { return &MsgPong{} },
+	CmdGetHeaders:   func() Message { return &MsgGetHeaders{} },
+	CmdHeaders:      func() Message { return &MsgHeaders{} },
+	CmdMemPool:      func() Message { return &MsgMemPool{} },
+	CmdFilterAdd:    func() Message { return &MsgFilterAdd{} },
+	CmdFilterClear:  func() Message { return &MsgFilterClear{} },
+	CmdFilterLoad:   func() Message { return &MsgFilterLoad{} },
+	CmdMerkleBlock:  func() Message { return &MsgMerkleBlock{} },
+	CmdReject:       func() Message { return &MsgReject{} },
+	CmdSendHeaders:  func() Message { return &MsgSendHeaders{} },
+	CmdFeeFilter:    func() Message { return &MsgFeeFilter{} },
+	CmdGetCFilters:  func() Message { return &MsgGetCFilters{} },
+	CmdGetCFHeaders: func() Message { return &MsgGetCFHeaders{} },
+	CmdGetCFCheckpt: func() Message { return &MsgGetCFCheckpt{} },
+	CmdCFilter:      func() Message { return &MsgCFilter{} },
+	CmdCFHeaders:    func() Message { return &MsgCFHeaders{} },
+	CmdCFCheckpt:    func() Message { return &MsgCFCheckpt{} },
+	CmdProtoconf:    func() Message { return &MsgProtoconf{} },
+	CmdExtMsg:       func() Message { return &MsgExtMsg{} },
+	CmdAuthch:       func() Message { return &MsgAuthch{} },
+	CmdAuthresp:     func() Message { return &MsgAuthresp{} },
+	CmdSendcmpct:    func() Message { return &MsgSendcmpct{} },
+	CmdCreateStream: func() Message { return &MsgCreateStream{} },
+	CmdStreamAck:    func() Message { return &MsgStreamAck{} },
+}
+
+// RegisterMessage registers constructor as the way to create an empty message
+// for command, so that ReadMessage and friends are able to decode messages
+// with that command.  This allows callers to add private, experimental or
+// newer messages without modifying this package.
+//
+// The command must be a non-empty, valid UTF-8 string no longer than
+// CommandSize, must not already be registered, and the message returned by
+// the constructor must report the same command from its Command method so it
+// round-trips through WriteMessage.  Use UnregisterMessage first to replace an
+// existing registration.
+//
+// This function is safe for concurrent access.
+func RegisterMessage(command string, constructor MessageConstructor) error {
+	if command == "" || len(command) > CommandSize || !utf8.ValidString(command) {
+		str := fmt.Sprintf("invalid command [%s] - must be valid utf-8 "+
+			"and between 1 and %d bytes", command, CommandSize)
+		return messageError("RegisterMessage", ErrInvalidCommand, str)
+	}
+
+	if constructor == nil {
+		str := fmt.Sprintf("nil constructor for command [%s]", command)
+		return messageError("RegisterMessage", ErrInvalidArgument, str)
+	}
+
+	if got := constructor().Command(); got != command {
+		str := fmt.Sprintf("constructor for command [%s] creates a "+
+			"message with command [%s]", command, got)
+		return messageError("RegisterMessage", ErrInvalidArgument, str)
+	}
+
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+
+	if _, ok := messageRegistry[command]; ok {
+		str := fmt.Sprintf("command [%s] is already registered", command)
+		return messageError("RegisterMessage", ErrInvalidArgument, str)
+	}
+
+	messageRegistry[command] = constructor
+	dropMessagePool(command)
+
+	return nil
+}
+
+// UnregisterMessage removes the registration for command, including those of
+// the messages implemented by this package, and reports whether the command
+// was registered.  Messages with an unregistered command are rejected when
+// read.
+//
+// This function is safe for concurrent access.
+func UnregisterMessage(command string) bool {
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+
+	_, ok := messageRegistry[command]
+	delete(messageRegistry, command)
+	dropMessagePool(command)
+
+	return ok
+}
+
+// LookupMessage returns the constructor registered for command and whether
+// one was found.
+//
+// This function is safe for concurrent access.
+func LookupMessage(command string) (MessageConstructor, bool) {
+	registryMtx.RLock()
+	constructor, ok := messageRegistry[command]
+	registryMtx.RUnlock()
+
+	return constructor, ok
+}
+
+// RegisteredCommands returns a sorted list of all commands which currently
+// have a registered constructor.
+//
+// This function is safe for concurrent access.
+func RegisteredCommands() []string {
+	registryMtx.RLock()
+	commands := make([]string, 0, len(messageRegistry))
+
+	for command := range messageRegistry {
+		commands = append(commands, command)
+	}
+	registryMtx.RUnlock()
+
+	sort.Strings(commands)
+
+	return commands
+}
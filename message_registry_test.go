@@ -0,0 +1,135 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cmdCustom is the command used by msgCustom in the registry tests.
+const cmdCustom = "xcustom"
+
+// msgCustom is an out-of-tree style message used to verify that registered
+// messages round-trip through the read and write paths.
+type msgCustom struct {
+	Data []byte
+}
+
+func (msg *msgCustom) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
+	var err error
+	msg.Data, err = ReadVarBytes(r, pver, msg.MaxPayloadLength(pver), "custom data")
+
+	return err
+}
+
+func (msg *msgCustom) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error {
+	return WriteVarBytes(w, pver, msg.Data)
+}
+
+func (msg *msgCustom) Command() string {
+	return cmdCustom
+}
+
+func (msg *msgCustom) MaxPayloadLength(_ uint32) uint64 {
+	return 1024
+}
+
+// registerCustom registers msgCustom for the duration of the test.
+func registerCustom(t *testing.T) {
+	t.Helper()
+
+	require.NoError(t, RegisterMessage(cmdCustom, func() Message { return &msgCustom{} }))
+	t.Cleanup(func() {
+		UnregisterMessage(cmdCustom)
+	})
+}
+
+// TestRegisterMessageRoundTrip ensures a registered message is decoded by both
+// the buffered and the streaming read paths.
+func TestRegisterMessageRoundTrip(t *testing.T) {
+	registerCustom(t)
+
+	in := &msgCustom{Data: []byte{0x01, 0x02, 0x03}}
+
+	var buf bytes.Buffer
+	_, err := WriteMessageN(&buf, in, ProtocolVersion, MainNet)
+	require.NoError(t, err)
+
+	_, msg, _, err := ReadMessageN(bytes.NewReader(buf.Bytes()), ProtocolVersion, MainNet)
+	require.NoError(t, err)
+	assert.Equal(t, in, msg)
+
+	_, msg, err = ReadMessageStreamingN(bytes.NewReader(buf.Bytes()), ProtocolVersion, MainNet, BaseEncoding)
+	require.NoError(t, err)
+	assert.Equal(t, in, msg)
+}
+
+// TestRegisterMessageErrors ensures invalid registrations are rejected.
+func TestRegisterMessageErrors(t *testing.T) {
+	custom := func() Message { return &msgCustom{} }
+
+	tests := []struct {
+		name        string
+		command     string
+		constructor MessageConstructor
+	}{
+		{"empty command", "", custom},
+		{"command too long", "waytoolongcommand", custom},
+		{"invalid utf-8", "\x81bad", custom},
+		{"nil constructor", cmdCustom, nil},
+		{"command mismatch", "other", custom},
+		{"already registered", CmdTx, func() Message { return &MsgTx{} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterMessage(tt.command, tt.constructor)
+
+			var msgErr *MessageError
+			require.ErrorAs(t, err, &msgErr)
+		})
+	}
+}
+
+// TestUnregisterMessage ensures unregistered commands are rejected on read.
+func TestUnregisterMessage(t *testing.T) {
+	registerCustom(t)
+
+	var buf bytes.Buffer
+	_, err := WriteMessageN(&buf, &msgCustom{}, ProtocolVersion, MainNet)
+	require.NoError(t, err)
+
+	assert.True(t, UnregisterMessage(cmdCustom))
+	assert.False(t, UnregisterMessage(cmdCustom))
+
+	_, ok := LookupMessage(cmdCustom)
+	assert.False(t, ok)
+
+	_, _, _, err = ReadMessageN(bytes.NewReader(buf.Bytes()), ProtocolVersion, MainNet)
+
+	var msgErr *MessageError
+	require.ErrorAs(t, err, &msgErr)
+}
+
+// TestRegisteredCommands ensures the built-in messages are registered and the
+// returned list is sorted.
+func TestRegisteredCommands(t *testing.T) {
+	commands := RegisteredCommands()
+
+	assert.IsIncreasing(t, commands)
+
+	for _, command := range allDecodeCommands {
+		assert.Contains(t, commands, command)
+
+		constructor, ok := LookupMessage(command)
+		require.True(t, ok, command)
+		assert.NotNil(t, constructor())
+	}
+}
@@ -240,16 +240,14 @@ func TestReadMessageStreamingN_ExternalHandlerWins(t *testing.T) {
 // undergo checksum verification, even when the checksum field in the header
 // contains a deliberately wrong value.
 //
-// Because the extended-format path in readMessageHeader has a known parsing
-// limitation (the inner-command / extLength are read from an already-exhausted
-// buffer), we exercise the checksum-skip condition by registering an external
-// handler that returns before the checksum check is reached. This confirms that
-// the dispatch logic in ReadMessageStreamingN correctly delegates to the
-// external handler and never attempts to compute or verify a checksum for an
-// extended message.
+// We exercise the checksum-skip condition by registering an external handler
+// that returns before the checksum check is reached. This confirms that the
+// dispatch logic in ReadMessageStreamingN correctly delegates to the external
+// handler and never attempts to compute or verify a checksum for an extended
+// message.
 //
-// If a future fix makes the full extmsg header parsing work, this test can be
-// replaced with a true end-to-end round-trip.
+// See TestExtendedMessageRoundTrip for an end-to-end round-trip of the
+// extended header.
 func TestReadMessageStreamingN_ExtMsgChecksumSkipped(t *testing.T) {
 	pver := ProtocolVersion
 	bsvnet := MainNet
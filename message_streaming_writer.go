@@ -0,0 +1,194 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// streamingWriteBufferSize is the size of the buffer used to batch the many
+// small writes made by BsvEncode before they reach the underlying writer.
+const streamingWriteBufferSize = 64 * 1024
+
+// countingWriter is an io.Writer which counts the bytes written to the
+// underlying writer.  A nil underlying writer discards the bytes, while a
+// non-nil hash is additionally fed every byte written.
+type countingWriter struct {
+	w io.Writer
+	h hash.Hash
+	n uint64
+}
+
+// Write writes p to the underlying writer and hash.
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n := len(p)
+
+	if cw.w != nil {
+		var err error
+
+		n, err = cw.w.Write(p)
+		if err != nil {
+			cw.n += uint64(n)
+			return n, err
+		}
+	}
+
+	if cw.h != nil {
+		_, _ = cw.h.Write(p[:n])
+	}
+
+	cw.n += uint64(n)
+
+	return n, nil
+}
+
+// WriteMessageStreamingN writes a bitcoin Message to w including the necessary
+// header information and returns the number of bytes written, without ever
+// holding the encoded payload in memory.  It produces exactly the same bytes as
+// WriteMessageWithEncodingN.
+//
+// The payload size must be known before the header is written:
+//
+//  1. Messages with a payload of 4GB or larger use the extended header, which
+//     carries no checksum.  When the message implements PayloadSizer its size
+//     is taken from PayloadSize, otherwise the message is encoded once to a
+//     byte counter.  The header is then written, followed by the payload which
+//     is encoded directly to w.
+//
+//  2. All other messages carry a checksum of the payload.  The message is
+//     encoded twice: the first pass only hashes and counts the payload and the
+//     second pass writes it to w behind the header.
+//
+// Writes to w are batched through a small fixed-size buffer so peak memory
+// stays bounded regardless of the size of the message.  Since the payload is
+// written as it is encoded, an encoding error or a message which encodes to a
+// different size than it reported leaves a partial message on w, after which
+// the connection must be closed.
+func WriteMessageStreamingN(w io.Writer, msg Message, pver uint32, bsvnet BitcoinNet, enc MessageEncoding) (int, error) {
+	p := codecParams{bsvnet: bsvnet, pver: pver, enc: enc, twoPass: true}
+	return defaultCodec.writeMessageStreaming(w, msg, p)
+}
+
+// writeMessageStreaming writes msg to w including the necessary header
+// information using the provided codec settings without buffering the
+// payload.  When p.twoPass is unset, messages which require a checksummed
+// header are written by writeBuffered instead.
+func (c *Codec) writeMessageStreaming(w io.Writer, msg Message, p codecParams) (int, error) {
+	if w == nil {
+		return 0, errors.New("writer must not be nil") //nolint:err113 // needs refactoring
+	}
+
+	ob := c.observe(DirectionWrite, msg.Command())
+	ob.begin()
+
+	var (
+		n   int
+		err error
+	)
+
+	if chain := c.writeChain(msg.Command(), p, writeStreamed); chain != nil {
+		n, err = chain(w, msg)
+	} else {
+		n, err = writeStreamed(w, msg, p)
+	}
+
+	hdrBytes := writtenHeaderBytes(n, p)
+	ob.end(hdrBytes, n-hdrBytes, err)
+
+	return n, err
+}
+
+// writeStreamed writes msg to w without buffering its payload and returns the
+// number of bytes written.  It is the default write handler of
+// writeMessageStreaming.
+func writeStreamed(w io.Writer, msg Message, p codecParams) (int, error) {
+	// Enforce max command size.
+	cmd := msg.Command()
+	if len(cmd) > CommandSize {
+		str := fmt.Sprintf("command [%s] is too long [max %v]",
+			cmd, CommandSize)
+		return 0, messageError("WriteMessageStreaming", ErrInvalidCommand, str)
+	}
+
+	hdr := messageHeader{magic: p.bsvnet, command: cmd, extended: p.extended}
+
+	// Determine the payload size, taking it from the message when possible.
+	var (
+		size  uint64
+		sized bool
+	)
+
+	if ps, ok := msg.(PayloadSizer); ok {
+		size, sized = uint64(ps.PayloadSize(p.pver)), true
+	}
+
+	if sized && size >= extLengthMarker {
+		hdr.extended = true
+	}
+
+	// Without two-pass mode, checksummed frames are written by encoding the
+	// payload into a buffer once.
+	if !hdr.extended && !p.twoPass {
+		return writeBuffered(w, msg, p)
+	}
+
+	// First pass: count the payload, and hash it when the frame carries a
+	// checksum.
+	if !hdr.extended || !sized {
+		cw := &countingWriter{}
+		if !hdr.extended {
+			cw.h = sha256.New()
+		}
+
+		if err := msg.BsvEncode(cw, p.pver, p.enc); err != nil {
+			return 0, err
+		}
+
+		size = cw.n
+		if size >= extLengthMarker {
+			hdr.extended = true
+		}
+
+		if !hdr.extended {
+			checksum := sha256.Sum256(cw.h.Sum(nil))
+			copy(hdr.checksum[:], checksum[0:4])
+		}
+	}
+
+	if err := validateWritePayload(msg, size, &hdr, p); err != nil {
+		return 0, err
+	}
+
+	// Second pass: write the header followed by the payload, encoded
+	// directly to the writer.
+	hw := encodeMessageHeader(&hdr)
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriterSize(cw, streamingWriteBufferSize)
+
+	_, _ = bw.Write(hw)
+
+	if err := msg.BsvEncode(bw, p.pver, p.enc); err != nil {
+		_ = bw.Flush()
+		return int(cw.n), err
+	}
+
+	if err := bw.Flush(); err != nil {
+		return int(cw.n), err
+	}
+
+	if written := cw.n - uint64(len(hw)); written != size {
+		str := fmt.Sprintf("message of type [%s] encoded %d bytes, but "+
+			"its header declares %d bytes", cmd, written, size)
+
+		return int(cw.n), messageError("WriteMessageStreaming", ErrMalformedMessage, str)
+	}
+
+	return int(cw.n), nil
+}
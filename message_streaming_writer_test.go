@@ -0,0 +1,173 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// maxWriteRecorder is an io.Writer which discards all bytes while recording the
+// total number of bytes and the largest single write it received.
+type maxWriteRecorder struct {
+	n        uint64
+	maxWrite int
+}
+
+func (w *maxWriteRecorder) Write(p []byte) (int, error) {
+	w.n += uint64(len(p))
+	w.maxWrite = max(w.maxWrite, len(p))
+
+	return len(p), nil
+}
+
+// msgWrongSize is a msgSink which reports a size that differs from the number
+// of bytes it encodes.
+type msgWrongSize struct {
+	msgSink
+}
+
+func (msg *msgWrongSize) PayloadSize(_ uint32) int {
+	return int(msg.Length) + 1
+}
+
+// TestWriteMessageStreamingN ensures the streaming writer produces exactly the
+// same bytes as the buffered writer in every mode.
+func TestWriteMessageStreamingN(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  Message
+	}{
+		{"block", &blockOne},
+		{"tx", multiTx},
+		{"extended tx", multiExtendedTx},
+		{"ping", NewMsgPing(123)},
+		{"verack", NewMsgVerAck()},
+		{"reject", NewMsgReject(CmdTx, RejectInvalid, "bad")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var want bytes.Buffer
+			_, err := WriteMessageN(&want, tt.msg, ProtocolVersion, MainNet)
+			require.NoError(t, err)
+
+			var got bytes.Buffer
+			n, err := WriteMessageStreamingN(&got, tt.msg, ProtocolVersion, MainNet, BaseEncoding)
+			require.NoError(t, err)
+			assert.Equal(t, want.Len(), n)
+			assert.Equal(t, want.Bytes(), got.Bytes())
+
+			for _, twoPass := range []bool{false, true} {
+				codec := NewCodec(MainNet, ProtocolVersion)
+				codec.SetTwoPassChecksum(twoPass)
+				assert.Equal(t, twoPass, codec.TwoPassChecksum())
+
+				got.Reset()
+				n, err = codec.WriteStreaming(&got, tt.msg)
+				require.NoError(t, err)
+				assert.Equal(t, want.Len(), n)
+				assert.Equal(t, want.Bytes(), got.Bytes())
+			}
+
+			// Extended messages are streamed regardless of the two-pass
+			// setting.
+			codec := NewCodec(MainNet, ProtocolVersion)
+			codec.SetExtendedMessages(true)
+
+			want.Reset()
+			_, err = codec.Write(&want, tt.msg)
+			require.NoError(t, err)
+
+			got.Reset()
+			n, err = codec.WriteStreaming(&got, tt.msg)
+			require.NoError(t, err)
+			assert.Equal(t, want.Len(), n)
+			assert.Equal(t, want.Bytes(), got.Bytes())
+		})
+	}
+}
+
+// TestWriteMessageStreamingNErrors ensures the streaming writer rejects the
+// same messages as the buffered writer and reports write failures.
+func TestWriteMessageStreamingNErrors(t *testing.T) {
+	var msgErr *MessageError
+
+	// Payload exceeds the maximum payload of the message type.
+	_, err := WriteMessageStreamingN(io.Discard, &fakeMessage{command: "fake", payload: []byte{0x01}, forceLenErr: true},
+		ProtocolVersion, MainNet, BaseEncoding)
+	require.ErrorAs(t, err, &msgErr)
+
+	// Encoding failure.
+	_, err = WriteMessageStreamingN(io.Discard, &fakeMessage{command: "fake", forceEncodeErr: true},
+		ProtocolVersion, MainNet, BaseEncoding)
+	require.ErrorAs(t, err, &msgErr)
+
+	// Command too long.
+	_, err = WriteMessageStreamingN(io.Discard, &fakeMessage{command: "somethingtoolong"},
+		ProtocolVersion, MainNet, BaseEncoding)
+	require.ErrorAs(t, err, &msgErr)
+
+	// Nil writer.
+	_, err = WriteMessageStreamingN(nil, &blockOne, ProtocolVersion, MainNet, BaseEncoding)
+	require.Error(t, err)
+
+	// Short write.
+	_, err = WriteMessageStreamingN(newFixedWriter(10), &blockOne, ProtocolVersion, MainNet, BaseEncoding)
+	require.ErrorIs(t, err, io.ErrShortWrite)
+
+	// A message which encodes a different number of bytes than it reports
+	// is detected once written.
+	codec := NewCodec(MainNet, ProtocolVersion)
+	codec.SetExtendedMessages(true)
+
+	_, err = codec.WriteStreaming(io.Discard, &msgWrongSize{msgSink{Length: 100}})
+	require.ErrorAs(t, err, &msgErr)
+}
+
+// TestWriteMessageStreamingNOver4GB ensures a payload larger than 4GB is
+// written with the extended header without being buffered, and reads back
+// through the streaming reader.
+func TestWriteMessageStreamingNOver4GB(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping 4GB payload test in short mode")
+	}
+
+	require.NoError(t, RegisterMessage(cmdSink, func() Message { return &msgSink{} }))
+	t.Cleanup(func() {
+		UnregisterMessage(cmdSink)
+	})
+
+	codec := NewCodec(MainNet, ProtocolVersion)
+	codec.SetLimits(4 * 1024 * 1024 * 1024)
+
+	msg := &msgSink{Length: math.MaxUint32 + 1}
+
+	// The payload must reach the writer in small chunks.
+	rec := &maxWriteRecorder{}
+	n, err := codec.WriteStreaming(rec, msg)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(ExtendedMessageHeaderSize)+msg.Length, uint64(n))
+	assert.Equal(t, uint64(n), rec.n)
+	assert.LessOrEqual(t, rec.maxWrite, streamingWriteBufferSize)
+
+	// Round-trip the message through a pipe.
+	pr, pw := io.Pipe()
+
+	go func() {
+		_, err := codec.WriteStreaming(pw, msg)
+		_ = pw.CloseWithError(err)
+	}()
+
+	n, got, err := codec.ReadStreaming(pr)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(ExtendedMessageHeaderSize)+msg.Length, uint64(n))
+	assert.Equal(t, msg, got)
+}
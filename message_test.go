@@ -358,6 +358,14 @@ func TestReadMessageWireErrors(t *testing.T) {
 		r := newFixedReader(test.max, test.buf)
 
 		nr, _, _, err := ReadMessageN(r, test.pver, test.bsvnet)
+
+		// Payload decoding failures are located by a DecodeError which
+		// wraps the expected error.
+		var decodeErr *DecodeError
+		if errors.As(err, &decodeErr) {
+			err = decodeErr.Err
+		}
+
 		if reflect.TypeOf(err) != reflect.TypeOf(test.readErr) {
 			t.Errorf(wrongReadErrorFmt+"want: %T", i, err, err, test.readErr)
 			continue
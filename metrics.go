@@ -0,0 +1,181 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"encoding/json"
+	"expvar"
+	"maps"
+	"sync"
+	"time"
+)
+
+// maxMetricsCommands is the maximum number of distinct commands Metrics
+// tracks per direction.  Messages with further commands are aggregated under
+// MetricsOtherCommand so peers sending arbitrary commands can not grow the
+// metrics without bound.
+const maxMetricsCommands = 128
+
+// MetricsOtherCommand is the command under which Metrics aggregates messages
+// once the maximum number of distinct commands has been reached.
+const MetricsOtherCommand = "<other>"
+
+// CommandStats holds the aggregated statistics of the messages with a single
+// command travelling in a single direction.
+type CommandStats struct {
+	// Messages is the number of messages observed.
+	Messages uint64 `json:"messages"`
+
+	// HeaderBytes is the total number of header bytes read or written.
+	HeaderBytes uint64 `json:"header_bytes"`
+
+	// PayloadBytes is the total number of payload bytes read or written.
+	PayloadBytes uint64 `json:"payload_bytes"`
+
+	// Duration is the total time spent decoding or encoding the messages.
+	Duration time.Duration `json:"duration_ns"`
+
+	// MaxDuration is the longest time spent decoding or encoding a single
+	// message.
+	MaxDuration time.Duration `json:"max_duration_ns"`
+
+	// Errors is the number of messages which failed.
+	Errors uint64 `json:"errors"`
+
+	// ErrorKinds counts the failures by the name of their ErrorKind.
+	// Failures which are not a *MessageError, such as io.ErrUnexpectedEOF,
+	// are counted under "other".
+	ErrorKinds map[string]uint64 `json:"error_kinds,omitempty"`
+}
+
+// MetricsSnapshot is a point-in-time copy of the statistics aggregated by
+// Metrics, keyed by command.
+type MetricsSnapshot struct {
+	Read  map[string]CommandStats `json:"read"`
+	Write map[string]CommandStats `json:"write"`
+}
+
+// Metrics is an Observer which aggregates the messages read and written by
+// one or more codecs in memory, by direction and command.  It implements
+// expvar.Var, so it can be published via Publish or expvar.Publish and served
+// as JSON on /debug/vars, and its Snapshot is a convenient source for
+// adapters to other monitoring systems such as Prometheus.
+//
+// Metrics is safe for concurrent access.
+type Metrics struct {
+	mtx   sync.Mutex
+	read  map[string]*CommandStats
+	write map[string]*CommandStats
+}
+
+// Ensure Metrics implements the Observer and expvar.Var interfaces.
+var (
+	_ Observer   = (*Metrics)(nil)
+	_ expvar.Var = (*Metrics)(nil)
+)
+
+// NewMetrics returns a new empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		read:  make(map[string]*CommandStats),
+		write: make(map[string]*CommandStats),
+	}
+}
+
+// ObserveMessage adds ev to the statistics of its direction and command.  This
+// is part of the Observer interface implementation.
+func (m *Metrics) ObserveMessage(ev MessageEvent) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	stats := m.read
+	if ev.Direction == DirectionWrite {
+		stats = m.write
+	}
+
+	cs, ok := stats[ev.Command]
+	if !ok {
+		cmd := ev.Command
+		if len(stats) >= maxMetricsCommands {
+			cmd = MetricsOtherCommand
+		}
+
+		if cs, ok = stats[cmd]; !ok {
+			cs = &CommandStats{}
+			stats[cmd] = cs
+		}
+	}
+
+	cs.Messages++
+	cs.HeaderBytes += uint64(ev.HeaderBytes)
+	cs.PayloadBytes += uint64(ev.PayloadBytes)
+	cs.Duration += ev.Duration
+	cs.MaxDuration = max(cs.MaxDuration, ev.Duration)
+
+	if ev.Err == nil {
+		return
+	}
+
+	cs.Errors++
+
+	kind := "other"
+	if ev.Kind != 0 {
+		kind = ev.Kind.Error()
+	}
+
+	if cs.ErrorKinds == nil {
+		cs.ErrorKinds = make(map[string]uint64)
+	}
+
+	cs.ErrorKinds[kind]++
+}
+
+// Snapshot returns a copy of the current statistics.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return MetricsSnapshot{
+		Read:  copyCommandStats(m.read),
+		Write: copyCommandStats(m.write),
+	}
+}
+
+// Reset discards all statistics.
+func (m *Metrics) Reset() {
+	m.mtx.Lock()
+	clear(m.read)
+	clear(m.write)
+	m.mtx.Unlock()
+}
+
+// String returns the current statistics as JSON.  This is part of the
+// expvar.Var interface implementation.
+func (m *Metrics) String() string {
+	b, err := json.Marshal(m.Snapshot())
+	if err != nil {
+		return "{}"
+	}
+
+	return string(b)
+}
+
+// Publish publishes the metrics as an expvar variable with the provided name.
+// Like expvar.Publish, it panics when the name is already in use.
+func (m *Metrics) Publish(name string) {
+	expvar.Publish(name, m)
+}
+
+// copyCommandStats returns a deep copy of stats.
+func copyCommandStats(stats map[string]*CommandStats) map[string]CommandStats {
+	out := make(map[string]CommandStats, len(stats))
+	for cmd, cs := range stats {
+		c := *cs
+		c.ErrorKinds = maps.Clone(cs.ErrorKinds)
+		out[cmd] = c
+	}
+
+	return out
+}
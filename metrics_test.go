@@ -0,0 +1,105 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetrics ensures Metrics aggregates events by direction and command.
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+
+	m.ObserveMessage(MessageEvent{Command: CmdTx, Direction: DirectionRead,
+		HeaderBytes: 24, PayloadBytes: 100, Duration: time.Millisecond})
+	m.ObserveMessage(MessageEvent{Command: CmdTx, Direction: DirectionRead,
+		HeaderBytes: 24, PayloadBytes: 50, Duration: 3 * time.Millisecond,
+		Err: messageError("test", ErrChecksumMismatch, "bad"), Kind: ErrChecksumMismatch})
+	m.ObserveMessage(MessageEvent{Command: CmdTx, Direction: DirectionRead,
+		HeaderBytes: 24, Err: io.ErrUnexpectedEOF})
+	m.ObserveMessage(MessageEvent{Command: CmdInv, Direction: DirectionWrite,
+		HeaderBytes: 24, PayloadBytes: 37})
+
+	snap := m.Snapshot()
+
+	assert.Equal(t, map[string]CommandStats{
+		CmdTx: {
+			Messages:     3,
+			HeaderBytes:  72,
+			PayloadBytes: 150,
+			Duration:     4 * time.Millisecond,
+			MaxDuration:  3 * time.Millisecond,
+			Errors:       2,
+			ErrorKinds:   map[string]uint64{"ErrChecksumMismatch": 1, "other": 1},
+		},
+	}, snap.Read)
+	assert.Equal(t, map[string]CommandStats{
+		CmdInv: {Messages: 1, HeaderBytes: 24, PayloadBytes: 37},
+	}, snap.Write)
+
+	// Snapshots are not affected by later events.
+	m.ObserveMessage(MessageEvent{Command: CmdTx, Err: io.EOF})
+	assert.Equal(t, uint64(1), snap.Read[CmdTx].ErrorKinds["other"])
+
+	// The snapshot is published as JSON.
+	var decoded MetricsSnapshot
+	require.NoError(t, json.Unmarshal([]byte(m.String()), &decoded))
+	assert.Equal(t, m.Snapshot(), decoded)
+
+	m.Reset()
+	assert.Empty(t, m.Snapshot().Read)
+	assert.Empty(t, m.Snapshot().Write)
+}
+
+// TestMetricsCommandLimit ensures the number of distinct commands tracked is
+// bounded.
+func TestMetricsCommandLimit(t *testing.T) {
+	m := NewMetrics()
+
+	for i := 0; i < maxMetricsCommands+10; i++ {
+		m.ObserveMessage(MessageEvent{Command: fmt.Sprintf("cmd%d", i)})
+	}
+
+	// Known commands keep being tracked individually.
+	m.ObserveMessage(MessageEvent{Command: "cmd0"})
+
+	snap := m.Snapshot()
+	assert.Len(t, snap.Read, maxMetricsCommands+1)
+	assert.Equal(t, uint64(10), snap.Read[MetricsOtherCommand].Messages)
+	assert.Equal(t, uint64(2), snap.Read["cmd0"].Messages)
+}
+
+// TestMetricsCodec ensures Metrics aggregates the messages of a codec and can
+// be published via expvar.
+func TestMetricsCodec(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	m := NewMetrics()
+	codec.SetObserver(m)
+
+	var buf bytes.Buffer
+
+	n, err := codec.Write(&buf, &blockOne)
+	require.NoError(t, err)
+
+	_, _, _, err = codec.Read(&buf)
+	require.NoError(t, err)
+
+	snap := m.Snapshot()
+	assert.Equal(t, uint64(1), snap.Read[CmdBlock].Messages)
+	assert.Equal(t, uint64(n-MessageHeaderSize), snap.Read[CmdBlock].PayloadBytes)
+	assert.Equal(t, uint64(n-MessageHeaderSize), snap.Write[CmdBlock].PayloadBytes)
+
+	m.Publish("wire_test_metrics")
+	assert.JSONEq(t, m.String(), expvar.Get("wire_test_metrics").String())
+}
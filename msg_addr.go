@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 )
@@ -33,7 +34,7 @@ func (msg *MsgAddr) AddAddress(na *NetAddress) error {
 		str := fmt.Sprintf("too many addresses in message [max %v]",
 			MaxAddrPerMsg)
 
-		return messageError("MsgAddr.AddAddress", str)
+		return messageError("MsgAddr.AddAddress", ErrTooManyItems, str)
 	}
 
 	msg.AddrList = append(msg.AddrList, na)
@@ -63,25 +64,25 @@ func (msg *MsgAddr) ClearAddresses() {
 func (msg *MsgAddr) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
 	count, err := ReadVarInt(r, pver)
 	if err != nil {
-		return err
+		return traceField(r, err, "AddrList")
 	}
 
 	// Limit to max addresses per message.
 	if count > MaxAddrPerMsg {
 		str := fmt.Sprintf("too many addresses for message "+
 			"[count %v, max %v]", count, MaxAddrPerMsg)
-		return messageError("MsgAddr.Bsvdecode", str)
+		return traceField(r, messageError("MsgAddr.Bsvdecode", ErrTooManyItems, str), "AddrList")
 	}
 
-	addrList := make([]NetAddress, count)
-	msg.AddrList = make([]*NetAddress, 0, count)
+	addrList := recycleList(msg.AddrList, count, recyclingOf(r))
+	msg.AddrList = addrList[:0]
 
 	for i := uint64(0); i < count; i++ {
-		na := &addrList[i]
+		na := addrList[i]
 
 		err := readNetAddress(r, pver, na, true)
 		if err != nil {
-			return err
+			return traceItem(r, err, "AddrList", i)
 		}
 
 		_ = msg.AddAddress(na)
@@ -99,13 +100,13 @@ func (msg *MsgAddr) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error
 	if pver < MultipleAddressVersion && count > 1 {
 		str := fmt.Sprintf("too many addresses for message of "+
 			"protocol version %v [count %v, max 1]", pver, count)
-		return messageError("MsgAddr.BsvEncode", str)
+		return messageError("MsgAddr.BsvEncode", ErrTooManyItems, str)
 	}
 
 	if count > MaxAddrPerMsg {
 		str := fmt.Sprintf("too many addresses for message "+
 			"[count %v, max %v]", count, MaxAddrPerMsg)
-		return messageError("MsgAddr.BsvEncode", str)
+		return messageError("MsgAddr.BsvEncode", ErrTooManyItems, str)
 	}
 
 	err := WriteVarInt(w, pver, uint64(count))
@@ -123,6 +124,74 @@ func (msg *MsgAddr) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgAddr) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgAddr) PayloadSize(pver uint32) int {
+	n := VarIntSerializeSize(uint64(len(msg.AddrList)))
+
+	return n + len(msg.AddrList)*int(maxNetAddressPayload(pver))
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgAddr) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgAddr) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgAddr) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgAddr) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgAddrJSON is the JSON representation of an addr message.
+type msgAddrJSON struct {
+	AddrList []*NetAddress `json:"addresses"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgAddr) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgAddrJSON{AddrList: nonNil(msg.AddrList)})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgAddr) UnmarshalJSON(data []byte) error {
+	var j msgAddrJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgAddr{AddrList: j.AddrList}
+
+	return nil
+}
+
 // Command returns the protocol command string for the message.  This is part
 // of the Message interface implementation.
 func (msg *MsgAddr) Command() string {
@@ -141,6 +210,13 @@ func (msg *MsgAddr) MaxPayloadLength(pver uint32) uint64 {
 	return MaxVarIntPayload + (MaxAddrPerMsg * maxNetAddressPayload(pver))
 }
 
+// Reset resets the message to its zero state while keeping the capacity of
+// AddrList, along with the addresses it points to, for reuse by the next
+// decode.  This is part of the Resetter interface implementation.
+func (msg *MsgAddr) Reset() {
+	*msg = MsgAddr{AddrList: msg.AddrList[:0]}
+}
+
 // NewMsgAddr returns a new bitcoin addr message that conforms to the
 // Message interface.  See MsgAddr for details.
 func NewMsgAddr() *MsgAddr {
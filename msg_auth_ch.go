@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"io"
 )
 
@@ -18,26 +19,111 @@ type MsgAuthch struct {
 // Bsvdecode decodes r using the bitcoin protocol encoding into the receiver.
 // This is part of the Message interface implementation.
 func (msg *MsgAuthch) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
-	// Read stop hash
-	err := readElement(r, &msg.Version)
+	err := readUint32(r, &msg.Version)
 	if err != nil {
 		return err
 	}
 
-	msg.Challenge, err = ReadVarBytes(r, pver, msg.MaxPayloadLength(pver), "challenge")
+	// The challenge is prefixed by its length as a uint32, as written by
+	// BsvEncode, and takes up the rest of the payload.
+	msg.Challenge, err = readLengthBytes(r, &msg.Length, msg.MaxPayloadLength(pver)-8,
+		"MsgAuthch.Bsvdecode", "challenge")
 	if err != nil {
 		return err
 	}
 
-	msg.Length = uint32(len(msg.Challenge))
-
 	return nil
 }
 
 // BsvEncode encodes the receiver to w using the bitcoin protocol encoding.
 // This is part of the Message interface implementation.
 func (msg *MsgAuthch) BsvEncode(w io.Writer, _ uint32, _ MessageEncoding) error {
-	return writeElements(w, msg.Version, msg.Length, msg.Challenge)
+	if err := writeUint32(w, msg.Version); err != nil {
+		return err
+	}
+
+	if err := writeUint32(w, msg.Length); err != nil {
+		return err
+	}
+
+	return writeFixed(w, msg.Challenge)
+}
+
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgAuthch) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgAuthch) PayloadSize(_ uint32) int {
+	// Version 4 bytes + Length 4 bytes + Challenge.
+	return 8 + len(msg.Challenge)
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgAuthch) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgAuthch) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgAuthch) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgAuthch) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgAuthchJSON is the JSON representation of an authch message.
+type msgAuthchJSON struct {
+	Version   int32   `json:"version"`
+	Length    uint32  `json:"length"`
+	Challenge jsonHex `json:"challenge"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgAuthch) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgAuthchJSON{
+		Version:   msg.Version,
+		Length:    msg.Length,
+		Challenge: msg.Challenge,
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgAuthch) UnmarshalJSON(data []byte) error {
+	var j msgAuthchJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgAuthch{
+		Version:   j.Version,
+		Length:    j.Length,
+		Challenge: j.Challenge,
+	}
+
+	return nil
 }
 
 // Command returns the protocol command string for the message.  This is part
@@ -52,6 +138,12 @@ func (msg *MsgAuthch) MaxPayloadLength(_ uint32) uint64 {
 	return 40
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgAuthch) Reset() {
+	*msg = MsgAuthch{}
+}
+
 // NewMsgAuthch returns a new auth challenge message
 func NewMsgAuthch(message string) *MsgAuthch {
 	return &MsgAuthch{
@@ -31,9 +31,8 @@ func TestMsgAuthchWire(t *testing.T) {
 	var decoded MsgAuthch
 	require.NoError(t, decoded.Bsvdecode(&buf, ProtocolVersion, BaseEncoding))
 
-	assert.Equal(t, orig.Version, decoded.Version)
-	assert.Equal(t, uint32(len(decoded.Challenge)), decoded.Length)
-	assert.NotEmpty(t, decoded.Challenge)
+	assert.Equal(t, orig, &decoded)
+	assert.Zero(t, buf.Len())
 }
 
 // TestMsgAuthchWireErrors exercises error paths for encoding and decoding.
@@ -80,7 +79,7 @@ func TestMsgAuthchWireErrors(t *testing.T) {
 			buf:      encoded,
 			max:      8,
 			writeErr: io.ErrShortWrite,
-			readErr:  io.ErrUnexpectedEOF,
+			readErr:  io.EOF,
 		},
 		{
 			name:     "challenge too large",
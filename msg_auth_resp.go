@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"io"
 )
 
@@ -25,36 +26,135 @@ type MsgAuthresp struct {
 
 // Bsvdecode decodes r using the bitcoin protocol encoding into the receiver.
 // This is part of the Message interface implementation.
-func (msg *MsgAuthresp) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
+func (msg *MsgAuthresp) Bsvdecode(r io.Reader, _ uint32, _ MessageEncoding) error {
 	var err error
 
-	msg.PublicKey, err = ReadVarBytes(r, pver, msg.MaxPayloadLength(pver), "challenge")
+	// The public key and the signature are each prefixed by their length
+	// as a uint32, as written by BsvEncode.
+	msg.PublicKey, err = readLengthBytes(r, &msg.PublicKeyLength,
+		uint64(SECP256K1_COMP_PUB_KEY_SIZE_IN_BYTES), "MsgAuthresp.Bsvdecode", "public key")
 	if err != nil {
 		return err
 	}
 
-	msg.PublicKeyLength = uint32(len(msg.PublicKey))
-
-	// Read stop hash
-	err = readElement(r, &msg.ClientNonce)
+	err = readUint64(r, &msg.ClientNonce)
 	if err != nil {
 		return err
 	}
 
-	msg.Signature, err = ReadVarBytes(r, pver, msg.MaxPayloadLength(pver), "challenge")
+	msg.Signature, err = readLengthBytes(r, &msg.SignatureLength,
+		uint64(SECP256K1_DER_SIGN_MAX_SIZE_IN_BYTES), "MsgAuthresp.Bsvdecode", "signature")
 	if err != nil {
 		return err
 	}
 
-	msg.SignatureLength = uint32(len(msg.Signature))
-
 	return nil
 }
 
 // BsvEncode encodes the receiver to w using the bitcoin protocol encoding.
 // This is part of the Message interface implementation.
 func (msg *MsgAuthresp) BsvEncode(w io.Writer, _ uint32, _ MessageEncoding) error {
-	return writeElements(w, msg.PublicKeyLength, msg.PublicKey, msg.ClientNonce, msg.SignatureLength, msg.Signature)
+	if err := writeUint32(w, msg.PublicKeyLength); err != nil {
+		return err
+	}
+
+	if err := writeFixed(w, msg.PublicKey); err != nil {
+		return err
+	}
+
+	if err := writeUint64(w, msg.ClientNonce); err != nil {
+		return err
+	}
+
+	if err := writeUint32(w, msg.SignatureLength); err != nil {
+		return err
+	}
+
+	return writeFixed(w, msg.Signature)
+}
+
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgAuthresp) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgAuthresp) PayloadSize(_ uint32) int {
+	// PublicKeyLength 4 bytes + ClientNonce 8 bytes + SignatureLength 4
+	// bytes + PublicKey + Signature.
+	return 16 + len(msg.PublicKey) + len(msg.Signature)
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgAuthresp) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgAuthresp) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgAuthresp) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgAuthresp) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgAuthrespJSON is the JSON representation of an authresp message.
+type msgAuthrespJSON struct {
+	PublicKeyLength uint32  `json:"publickeylength"`
+	PublicKey       jsonHex `json:"publickey"`
+	ClientNonce     uint64  `json:"clientnonce"`
+	SignatureLength uint32  `json:"signaturelength"`
+	Signature       jsonHex `json:"signature"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgAuthresp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgAuthrespJSON{
+		PublicKeyLength: msg.PublicKeyLength,
+		PublicKey:       msg.PublicKey,
+		ClientNonce:     msg.ClientNonce,
+		SignatureLength: msg.SignatureLength,
+		Signature:       msg.Signature,
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgAuthresp) UnmarshalJSON(data []byte) error {
+	var j msgAuthrespJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgAuthresp{
+		PublicKeyLength: j.PublicKeyLength,
+		PublicKey:       j.PublicKey,
+		ClientNonce:     j.ClientNonce,
+		SignatureLength: j.SignatureLength,
+		Signature:       j.Signature,
+	}
+
+	return nil
 }
 
 // Command returns the protocol command string for the message.  This is part
@@ -69,6 +169,12 @@ func (msg *MsgAuthresp) MaxPayloadLength(_ uint32) uint64 {
 	return uint64(4 + SECP256K1_COMP_PUB_KEY_SIZE_IN_BYTES + 8 + 4 + SECP256K1_DER_SIGN_MAX_SIZE_IN_BYTES)
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgAuthresp) Reset() {
+	*msg = MsgAuthresp{}
+}
+
 // NewMsgAuthresp returns a new auth challenge message
 func NewMsgAuthresp(publickKey, signature []byte) *MsgAuthresp {
 	nonce, _ := RandomUint64()
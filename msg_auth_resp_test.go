@@ -59,15 +59,9 @@ func TestMsgAuthrespEncodeDecodeRoundTrip(t *testing.T) {
 	require.NoError(t, msg.BsvEncode(&buf, ProtocolVersion, BaseEncoding))
 	assert.Equal(t, want.Bytes(), buf.Bytes())
 
-	var decodeBuf bytes.Buffer
-	decodeBuf.WriteByte(byte(len(pubKey)))
-	decodeBuf.Write(pubKey)
-	require.NoError(t, writeElement(&decodeBuf, nonce))
-	decodeBuf.WriteByte(byte(len(sig)))
-	decodeBuf.Write(sig)
-
 	var decoded MsgAuthresp
-	require.NoError(t, decoded.Bsvdecode(&decodeBuf, ProtocolVersion, BaseEncoding))
+	require.NoError(t, decoded.Bsvdecode(&buf, ProtocolVersion, BaseEncoding))
+	assert.Zero(t, buf.Len())
 	assert.Equal(t, msg.PublicKey, decoded.PublicKey)
 	assert.Equal(t, msg.Signature, decoded.Signature)
 	assert.Equal(t, msg.ClientNonce, decoded.ClientNonce)
@@ -84,14 +78,10 @@ func TestMsgAuthrespEncodeDecodeErrors(t *testing.T) {
 	msg := NewMsgAuthresp(pubKey, sig)
 
 	var decBuf bytes.Buffer
-	decBuf.WriteByte(byte(len(pubKey)))
-	decBuf.Write(pubKey)
-	_ = writeElement(&decBuf, msg.ClientNonce)
-	decBuf.WriteByte(byte(len(sig)))
-	decBuf.Write(sig)
+	require.NoError(t, msg.BsvEncode(&decBuf, ProtocolVersion, BaseEncoding))
 	decodeBytes := decBuf.Bytes()
 
-	overflow := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	overflow := []byte{0xff, 0xff, 0xff, 0xff}
 	wireErr := &MessageError{}
 
 	cases := []struct {
@@ -6,6 +6,7 @@ package wire
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 
@@ -32,13 +33,19 @@ const MaxBlocksPerMsg = 500
 
 // MaxBlockPayload returns the maximum bytes a block message can be in bytes.
 func MaxBlockPayload() uint64 {
-	return ebs
+	return ebs.Load()
 }
 
 // maxTxPerBlock returns the maximum number of transactions that could
 // possibly fit into a block.
 func maxTxPerBlock() uint64 {
-	return (MaxBlockPayload() / minTxPayload) + 1
+	return decodeLimits{}.maxTxPerBlock()
+}
+
+// maxTxPerBlock returns the maximum number of transactions that could
+// possibly fit into a block within the limits.
+func (l decodeLimits) maxTxPerBlock() uint64 {
+	return (l.maxBlockPayload() / minTxPayload) + 1
 }
 
 // TxLoc holds locator data for the offset and length of where a transaction is
@@ -71,46 +78,56 @@ func (msg *MsgBlock) ClearTransactions() {
 // This is part of the Message interface implementation.
 // See Deserialize for decoding blocks stored to disk, such as in a database, as
 // opposed to decoding blocks from the wire.
-func (msg *MsgBlock) Bsvdecode(r io.Reader, pver uint32, enc MessageEncoding) error {
+func (msg *MsgBlock) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
+	return msg.decode(r, pver, nil)
+}
+
+// decode decodes a block from r into the receiver with the scripts of its
+// transactions read by src.  A nil src reads them into a block-scoped arena.
+func (msg *MsgBlock) decode(r io.Reader, pver uint32, src scriptSource) error {
 	err := readBlockHeader(r, pver, &msg.Header)
 	if err != nil {
-		return err
+		return traceField(r, err, "Header")
 	}
 
 	txCount, err := ReadVarInt(r, pver)
 	if err != nil {
-		return err
+		return traceField(r, err, "Transactions")
 	}
 
 	// Prevent more transactions than could possibly fit into a block.
 	// It would be possible to cause memory exhaustion and panic without
 	// a sane upper bound on this count.
-	if txCount > maxTxPerBlock() {
+	if txCount > limitsOf(r).maxTxPerBlock() {
 		str := fmt.Sprintf("too many transactions to fit into a block "+
-			"[count %d, max %d]", txCount, maxTxPerBlock())
-		return messageError("MsgBlock.Bsvdecode", str)
+			"[count %d, max %d]", txCount, limitsOf(r).maxTxPerBlock())
+		return traceField(r, messageError("MsgBlock.Bsvdecode", ErrTooManyItems, str), "Transactions")
+	}
+
+	if err = checkRemainingCount(r, txCount, minTxPayload, "MsgBlock.Bsvdecode", "transactions"); err != nil {
+		return traceField(r, err, "Transactions")
 	}
 
-	// Pre-allocate all MsgTx structs contiguously and use a block-scoped
-	// arena allocator for script bytes. The arena eliminates the per-tx
-	// contiguous copy that the old scratch-buffer approach required, and
-	// returns stable slices (cap==len) directly into fixed chunks.
-	txs := make([]MsgTx, txCount)
-	msg.Transactions = make([]*MsgTx, txCount)
+	// Pre-allocate all MsgTx structs contiguously, or reuse those kept by
+	// Reset, and use a block-scoped arena allocator for script bytes. The
+	// arena eliminates the per-tx contiguous copy that the old scratch-buffer
+	// approach required, and returns stable slices (cap==len) directly into
+	// fixed chunks.
+	msg.Transactions = recycleList(msg.Transactions, txCount, recyclingOf(r))
 
 	// Size the first arena chunk to fit the expected script bytes for this
 	// block. arenaScriptHintPerTx is a rough average of input+output script
 	// bytes per tx in mainnet history; the hint is clamped inside Alloc to
 	// [4 KiB, 4 MiB] so tiny blocks pay ~4 KiB and big blocks still amortize
 	// the full standard chunk size.
-	arena := newBlockArenaSized(int(txCount) * arenaScriptHintPerTx)
+	if src == nil {
+		src = newBlockArenaSized(int(txCount) * arenaScriptHintPerTx)
+	}
 
 	for i := uint64(0); i < txCount; i++ {
-		msg.Transactions[i] = &txs[i]
-
-		err := txs[i].bsvdecode(r, pver, enc, arena)
+		err := msg.Transactions[i].decode(r, pver, src)
 		if err != nil {
-			return err
+			return traceItem(r, err, "Transactions", i)
 		}
 	}
 
@@ -133,6 +150,34 @@ func (msg *MsgBlock) Deserialize(r io.Reader) error {
 	return msg.Bsvdecode(r, 0, BaseEncoding)
 }
 
+// FromBytes decodes the block serialized in b into the receiver using the
+// same format as Deserialize, without copying the transaction scripts out of b.
+// See MsgTx.FromBytes for the aliasing guarantees.  An error is returned when b
+// holds anything after the block.
+//
+// The receiver retains b: b must not be modified for as long as the block or
+// any of its transactions is in use.
+func (msg *MsgBlock) FromBytes(b []byte) error {
+	r := &sliceReader{buf: b}
+	if err := msg.decodeAlias(r, 0); err != nil {
+		return err
+	}
+
+	if r.Len() != 0 {
+		str := fmt.Sprintf("%d trailing bytes after block", r.Len())
+		return messageError("MsgBlock.FromBytes", ErrMalformedMessage, str)
+	}
+
+	return nil
+}
+
+// decodeAlias decodes a block from r with the scripts of its transactions
+// aliasing the buffer of r.  This is part of the aliasDecoder interface
+// implementation.
+func (msg *MsgBlock) decodeAlias(r *sliceReader, pver uint32) error {
+	return msg.decode(r, pver, r)
+}
+
 // DeserializeTxLoc decodes r in the same manner Deserialize does, but it takes
 // a byte buffer instead of a generic reader and returns a slice containing the
 // start and length of each transaction within the raw data that is being
@@ -159,7 +204,7 @@ func (msg *MsgBlock) DeserializeTxLoc(r *bytes.Buffer) ([]TxLoc, error) {
 	if txCount > maxTxPerBlock() {
 		str := fmt.Sprintf("too many transactions to fit into a block "+
 			"[count %d, max %d]", txCount, maxTxPerBlock())
-		return nil, messageError("MsgBlock.DeserializeTxLoc", str)
+		return nil, messageError("MsgBlock.DeserializeTxLoc", ErrTooManyItems, str)
 	}
 
 	// Deserialize each transaction while keeping track of its location
@@ -208,6 +253,85 @@ func (msg *MsgBlock) BsvEncode(w io.Writer, pver uint32, enc MessageEncoding) er
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgBlock) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgBlock) PayloadSize(pver uint32) int {
+	n := blockHeaderLen + VarIntSerializeSize(uint64(len(msg.Transactions)))
+
+	for _, tx := range msg.Transactions {
+		n += tx.PayloadSize(pver)
+	}
+
+	return n
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgBlock) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgBlock) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgBlock) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgBlock) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgBlockJSON is the JSON representation of a block message.
+type msgBlockJSON struct {
+	blockHeaderJSON
+
+	Transactions []*MsgTx `json:"tx"`
+}
+
+// MarshalJSON returns the JSON representation of the message in the format of
+// the getblock RPC of bitcoind at verbosity 2: the fields of the header
+// followed by the transactions in the format of MsgTx.MarshalJSON.  This is
+// part of the json.Marshaler interface implementation.
+func (msg *MsgBlock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgBlockJSON{
+		blockHeaderJSON: msg.Header.toJSON(),
+		Transactions:    nonNil(msg.Transactions),
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgBlock) UnmarshalJSON(data []byte) error {
+	var j msgBlockJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgBlock{Transactions: j.Transactions}
+
+	return msg.Header.fromJSON(&j.blockHeaderJSON)
+}
+
 // Serialize encodes the block to w using a format that suitable for long-term
 // storage such as a database while respecting the Version field in the block.
 // This function differs from BsvEncode in that BsvEncode encodes the block to
@@ -224,6 +348,14 @@ func (msg *MsgBlock) Serialize(w io.Writer) error {
 	return msg.BsvEncode(w, 0, BaseEncoding)
 }
 
+// AppendSerialize appends the encoding of the block, using the format of
+// Serialize, to dst and returns the extended buffer.  The buffer is grown to
+// the exact size of the block up front, so repeatedly serializing into one
+// reused buffer does not allocate.
+func (msg *MsgBlock) AppendSerialize(dst []byte) ([]byte, error) {
+	return msg.AppendBsvEncode(dst, 0, BaseEncoding)
+}
+
 // SerializeSize returns the number of bytes it would take to serialize the
 // block, factoring in any witness data within transaction.
 func (msg *MsgBlock) SerializeSize() int {
@@ -268,6 +400,13 @@ func (msg *MsgBlock) TxHashes() ([]chainhash.Hash, error) {
 	return hashList, nil
 }
 
+// Reset resets the message to its zero state while keeping the capacity of
+// Transactions, along with the transactions it points to, for reuse by the
+// next decode.  This is part of the Resetter interface implementation.
+func (msg *MsgBlock) Reset() {
+	*msg = MsgBlock{Transactions: msg.Transactions[:0]}
+}
+
 // NewMsgBlock returns a new bitcoin block message that conforms to the
 // Message interface.  See MsgBlock for details.
 func NewMsgBlock(blockHeader *BlockHeader) *MsgBlock {
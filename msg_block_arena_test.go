@@ -250,7 +250,7 @@ func TestMsgTxStandaloneUsesPool(t *testing.T) {
 	}
 
 	// cap == len is also expected on the single-tx path (three-index slice
-	// in bsvdecode's scripts[] consolidation step).
+	// in Bsvdecode's scripts[] consolidation step).
 	sig := decoded.TxIn[0].SignatureScript
 	pk := decoded.TxOut[0].PkScript
 	if cap(sig) != len(sig) {
@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 
@@ -38,7 +39,7 @@ func (msg *MsgCFHeaders) AddCFHash(hash *chainhash.Hash) error {
 	if len(msg.FilterHashes)+1 > MaxCFHeadersPerMsg {
 		str := fmt.Sprintf("too many block headers in message [max %v]",
 			MaxBlockHeadersPerMsg)
-		return messageError("MsgCFHeaders.AddCFHash", str)
+		return messageError("MsgCFHeaders.AddCFHash", ErrTooManyItems, str)
 	}
 
 	msg.FilterHashes = append(msg.FilterHashes, hash)
@@ -50,19 +51,19 @@ func (msg *MsgCFHeaders) AddCFHash(hash *chainhash.Hash) error {
 // This is part of the Message interface implementation.
 func (msg *MsgCFHeaders) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
 	// Read a filter type
-	err := readElement(r, &msg.FilterType)
+	err := readUint8(r, &msg.FilterType)
 	if err != nil {
 		return err
 	}
 
 	// Read stop hash
-	err = readElement(r, &msg.StopHash)
+	err = readHash(r, &msg.StopHash)
 	if err != nil {
 		return err
 	}
 
 	// Read prev filter header
-	err = readElement(r, &msg.PrevFilterHeader)
+	err = readHash(r, &msg.PrevFilterHeader)
 	if err != nil {
 		return err
 	}
@@ -79,22 +80,23 @@ func (msg *MsgCFHeaders) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 			"message [count %v, max %v]", count,
 			MaxBlockHeadersPerMsg)
 
-		return messageError("MsgCFHeaders.Bsvdecode", str)
+		return messageError("MsgCFHeaders.Bsvdecode", ErrTooManyItems, str)
 	}
 
 	// Create a contiguous slice of hashes to deserialize into to
-	// reduce the number of allocations.
-	msg.FilterHashes = make([]*chainhash.Hash, 0, count)
+	// reduce the number of allocations, reusing those kept by Reset.
+	hashes := recycleList(msg.FilterHashes, count, recyclingOf(r))
+	msg.FilterHashes = hashes[:0]
 
 	for i := uint64(0); i < count; i++ {
-		var cfh chainhash.Hash
+		cfh := hashes[i]
 
-		err := readElement(r, &cfh)
+		err := readHash(r, cfh)
 		if err != nil {
 			return err
 		}
 
-		_ = msg.AddCFHash(&cfh)
+		_ = msg.AddCFHash(cfh)
 	}
 
 	return nil
@@ -104,19 +106,19 @@ func (msg *MsgCFHeaders) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 // This is part of the Message interface implementation.
 func (msg *MsgCFHeaders) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error {
 	// Write a filter type
-	err := writeElement(w, msg.FilterType)
+	err := writeUint8(w, msg.FilterType)
 	if err != nil {
 		return err
 	}
 
 	// Write stop hash
-	err = writeElement(w, msg.StopHash)
+	err = writeHash(w, &msg.StopHash)
 	if err != nil {
 		return err
 	}
 
 	// Write prev filter header
-	err = writeElement(w, msg.PrevFilterHeader)
+	err = writeHash(w, &msg.PrevFilterHeader)
 	if err != nil {
 		return err
 	}
@@ -128,7 +130,7 @@ func (msg *MsgCFHeaders) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 			"message [count %v, max %v]", count,
 			MaxBlockHeadersPerMsg)
 
-		return messageError("MsgCFHeaders.BsvEncode", str)
+		return messageError("MsgCFHeaders.BsvEncode", ErrTooManyItems, str)
 	}
 
 	err = WriteVarInt(w, pver, uint64(count))
@@ -137,7 +139,7 @@ func (msg *MsgCFHeaders) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 	}
 
 	for _, cfh := range msg.FilterHashes {
-		err := writeElement(w, cfh)
+		err := writeHash(w, cfh)
 		if err != nil {
 			return err
 		}
@@ -146,6 +148,89 @@ func (msg *MsgCFHeaders) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgCFHeaders) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgCFHeaders) PayloadSize(_ uint32) int {
+	// FilterType 1 byte + StopHash + PrevFilterHeader + FilterHashes.
+	count := len(msg.FilterHashes)
+
+	return 1 + 2*chainhash.HashSize + VarIntSerializeSize(uint64(count)) +
+		count*chainhash.HashSize
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgCFHeaders) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgCFHeaders) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgCFHeaders) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgCFHeaders) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgCFHeadersJSON is the JSON representation of a cfheaders message.
+type msgCFHeadersJSON struct {
+	FilterType       FilterType `json:"filtertype"`
+	StopHash         jsonHash   `json:"stophash"`
+	PrevFilterHeader jsonHash   `json:"prevfilterheader"`
+	FilterHashes     []jsonHash `json:"filterhashes"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgCFHeaders) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgCFHeadersJSON{
+		FilterType:       msg.FilterType,
+		StopHash:         jsonHash(msg.StopHash),
+		PrevFilterHeader: jsonHash(msg.PrevFilterHeader),
+		FilterHashes:     toJSONHashes(msg.FilterHashes),
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgCFHeaders) UnmarshalJSON(data []byte) error {
+	var j msgCFHeadersJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgCFHeaders{
+		FilterType:       j.FilterType,
+		StopHash:         chainhash.Hash(j.StopHash),
+		PrevFilterHeader: chainhash.Hash(j.PrevFilterHeader),
+		FilterHashes:     fromJSONHashes(j.FilterHashes),
+	}
+
+	return nil
+}
+
 // Deserialize decodes a filter header from r into the receiver using a format
 // that is suitable for long-term storage such as a database. This function
 // differs from Bsvdecode in that Bsvdecode decodes from the bitcoin wire
@@ -177,6 +262,13 @@ func (msg *MsgCFHeaders) MaxPayloadLength(_ uint32) uint64 {
 		(MaxCFHeaderPayload * MaxCFHeadersPerMsg)
 }
 
+// Reset resets the message to its zero state while keeping the capacity of
+// FilterHashes, along with the filter hashes it points to, for reuse by the
+// next decode.  This is part of the Resetter interface implementation.
+func (msg *MsgCFHeaders) Reset() {
+	*msg = MsgCFHeaders{FilterHashes: msg.FilterHashes[:0]}
+}
+
 // NewMsgCFHeaders returns a new bitcoin cfheaders message that conforms to
 // the Message interface. See MsgCFHeaders for details.
 func NewMsgCFHeaders() *MsgCFHeaders {
@@ -5,7 +5,7 @@
 package wire
 
 import (
-	"errors"
+	"encoding/json"
 	"fmt"
 	"io"
 
@@ -23,10 +23,11 @@ const (
 )
 
 // ErrInsaneCFHeaderCount signals that we were asked to decode an
-// unreasonable number of cfilter headers.
-var ErrInsaneCFHeaderCount = errors.New(
-	"refusing to decode unreasonable number of filter headers",
-)
+// unreasonable number of cfilter headers.  It is of kind ErrTooManyItems.
+var ErrInsaneCFHeaderCount error = &MessageError{
+	Kind:        ErrTooManyItems,
+	Description: "refusing to decode unreasonable number of filter headers",
+}
 
 // MsgCFCheckpt implements the Message interface and represents a bitcoin
 // cfcheckpt message.  It is used to deliver committed filter header information
@@ -43,7 +44,7 @@ func (msg *MsgCFCheckpt) AddCFHeader(header *chainhash.Hash) error {
 	if len(msg.FilterHeaders) == cap(msg.FilterHeaders) {
 		str := fmt.Sprintf("FilterHeaders has insufficient capacity for "+
 			"additional header: len = %d", len(msg.FilterHeaders))
-		return messageError("MsgCFCheckpt.AddCFHeader", str)
+		return messageError("MsgCFCheckpt.AddCFHeader", ErrTooManyItems, str)
 	}
 
 	msg.FilterHeaders = append(msg.FilterHeaders, header)
@@ -55,13 +56,13 @@ func (msg *MsgCFCheckpt) AddCFHeader(header *chainhash.Hash) error {
 // This is part of the Message interface implementation.
 func (msg *MsgCFCheckpt) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
 	// Read a filter type
-	err := readElement(r, &msg.FilterType)
+	err := readUint8(r, &msg.FilterType)
 	if err != nil {
 		return err
 	}
 
 	// Read stop hash
-	err = readElement(r, &msg.StopHash)
+	err = readHash(r, &msg.StopHash)
 	if err != nil {
 		return err
 	}
@@ -78,18 +79,14 @@ func (msg *MsgCFCheckpt) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 	}
 
 	// Create a contiguous slice of hashes to deserialize into to
-	// reduce the number of allocations.
-	msg.FilterHeaders = make([]*chainhash.Hash, count)
+	// reduce the number of allocations, reusing those kept by Reset.
+	msg.FilterHeaders = recycleList(msg.FilterHeaders, count, recyclingOf(r))
 
 	for i := uint64(0); i < count; i++ {
-		var cfh chainhash.Hash
-
-		err := readElement(r, &cfh)
+		err := readHash(r, msg.FilterHeaders[i])
 		if err != nil {
 			return err
 		}
-
-		msg.FilterHeaders[i] = &cfh
 	}
 
 	return nil
@@ -99,13 +96,13 @@ func (msg *MsgCFCheckpt) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 // This is part of the Message interface implementation.
 func (msg *MsgCFCheckpt) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error {
 	// Write a filter type
-	err := writeElement(w, msg.FilterType)
+	err := writeUint8(w, msg.FilterType)
 	if err != nil {
 		return err
 	}
 
 	// Write stop hash
-	err = writeElement(w, msg.StopHash)
+	err = writeHash(w, &msg.StopHash)
 	if err != nil {
 		return err
 	}
@@ -119,7 +116,7 @@ func (msg *MsgCFCheckpt) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 	}
 
 	for _, cfh := range msg.FilterHeaders {
-		err := writeElement(w, cfh)
+		err := writeHash(w, cfh)
 		if err != nil {
 			return err
 		}
@@ -128,6 +125,86 @@ func (msg *MsgCFCheckpt) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgCFCheckpt) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgCFCheckpt) PayloadSize(_ uint32) int {
+	// FilterType 1 byte + StopHash + FilterHeaders.
+	count := len(msg.FilterHeaders)
+
+	return 1 + chainhash.HashSize + VarIntSerializeSize(uint64(count)) +
+		count*chainhash.HashSize
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgCFCheckpt) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgCFCheckpt) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgCFCheckpt) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgCFCheckpt) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgCFCheckptJSON is the JSON representation of a cfcheckpt message.
+type msgCFCheckptJSON struct {
+	FilterType    FilterType `json:"filtertype"`
+	StopHash      jsonHash   `json:"stophash"`
+	FilterHeaders []jsonHash `json:"filterheaders"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgCFCheckpt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgCFCheckptJSON{
+		FilterType:    msg.FilterType,
+		StopHash:      jsonHash(msg.StopHash),
+		FilterHeaders: toJSONHashes(msg.FilterHeaders),
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgCFCheckpt) UnmarshalJSON(data []byte) error {
+	var j msgCFCheckptJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgCFCheckpt{
+		FilterType:    j.FilterType,
+		StopHash:      chainhash.Hash(j.StopHash),
+		FilterHeaders: fromJSONHashes(j.FilterHeaders),
+	}
+
+	return nil
+}
+
 // Deserialize decodes a filter header from r into the receiver using a format
 // that is suitable for long-term storage such as a database. This function
 // differs from Bsvdecode in that Bsvdecode decodes from the bitcoin wire
@@ -158,6 +235,13 @@ func (msg *MsgCFCheckpt) MaxPayloadLength(_ uint32) uint64 {
 	return maxMessagePayload()
 }
 
+// Reset resets the message to its zero state while keeping the capacity of
+// FilterHeaders, along with the filter headers it points to, for reuse by the
+// next decode.  This is part of the Resetter interface implementation.
+func (msg *MsgCFCheckpt) Reset() {
+	*msg = MsgCFCheckpt{FilterHeaders: msg.FilterHeaders[:0]}
+}
+
 // NewMsgCFCheckpt returns a new bitcoin cfheaders message that conforms to
 // the Message interface. See MsgCFCheckpt for details.
 func NewMsgCFCheckpt(filterType FilterType, stopHash *chainhash.Hash,
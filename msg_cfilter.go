@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 
@@ -38,13 +39,13 @@ type MsgCFilter struct {
 // This is part of the Message interface implementation.
 func (msg *MsgCFilter) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
 	// Read filter type
-	err := readElement(r, &msg.FilterType)
+	err := readUint8(r, &msg.FilterType)
 	if err != nil {
 		return err
 	}
 
 	// Read the hash of the filter's block
-	err = readElement(r, &msg.BlockHash)
+	err = readHash(r, &msg.BlockHash)
 	if err != nil {
 		return err
 	}
@@ -63,15 +64,15 @@ func (msg *MsgCFilter) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) er
 	if size > MaxCFilterDataSize {
 		str := fmt.Sprintf("cfilter size too large for message "+
 			"[size %v, max %v]", size, MaxCFilterDataSize)
-		return messageError("MsgCFilter.BsvEncode", str)
+		return messageError("MsgCFilter.BsvEncode", ErrElementTooLarge, str)
 	}
 
-	err := writeElement(w, msg.FilterType)
+	err := writeUint8(w, msg.FilterType)
 	if err != nil {
 		return err
 	}
 
-	err = writeElement(w, msg.BlockHash)
+	err = writeHash(w, &msg.BlockHash)
 	if err != nil {
 		return err
 	}
@@ -79,6 +80,84 @@ func (msg *MsgCFilter) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) er
 	return WriteVarBytes(w, pver, msg.Data)
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgCFilter) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgCFilter) PayloadSize(_ uint32) int {
+	// FilterType 1 byte + BlockHash + Data.
+	return 1 + chainhash.HashSize + VarIntSerializeSize(uint64(len(msg.Data))) +
+		len(msg.Data)
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgCFilter) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgCFilter) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgCFilter) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgCFilter) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgCFilterJSON is the JSON representation of a cfilter message.
+type msgCFilterJSON struct {
+	FilterType FilterType `json:"filtertype"`
+	BlockHash  jsonHash   `json:"blockhash"`
+	Data       jsonHex    `json:"data"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgCFilter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgCFilterJSON{
+		FilterType: msg.FilterType,
+		BlockHash:  jsonHash(msg.BlockHash),
+		Data:       msg.Data,
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgCFilter) UnmarshalJSON(data []byte) error {
+	var j msgCFilterJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgCFilter{
+		FilterType: j.FilterType,
+		BlockHash:  chainhash.Hash(j.BlockHash),
+		Data:       j.Data,
+	}
+
+	return nil
+}
+
 // Deserialize decodes a filter from r into the receiver using a format that is
 // suitable for long-term storage such as a database. This function differs
 // from Bsvdecode in that Bsvdecode decodes from the bitcoin wire protocol as
@@ -108,6 +187,12 @@ func (msg *MsgCFilter) MaxPayloadLength(_ uint32) uint64 {
 		MaxCFilterDataSize + chainhash.HashSize + 1
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgCFilter) Reset() {
+	*msg = MsgCFilter{}
+}
+
 // NewMsgCFilter returns a new bitcoin cfilter message that conforms to the
 // Message interface. See MsgCFilter for details.
 func NewMsgCFilter(filterType FilterType, blockHash *chainhash.Hash,
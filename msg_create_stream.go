@@ -1,6 +1,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 )
@@ -25,11 +26,11 @@ func (msg *MsgCreateStream) Bsvdecode(r io.Reader, pver uint32, _ MessageEncodin
 	}
 
 	if len(msg.AssociationID) == 0 {
-		return messageError("MsgCreateStream.Bsvdecode", "association ID must not be empty")
+		return messageError("MsgCreateStream.Bsvdecode", ErrMalformedMessage, "association ID must not be empty")
 	}
 
 	var streamType uint8
-	if err = readElement(r, &streamType); err != nil {
+	if err = readUint8(r, &streamType); err != nil {
 		return err
 	}
 
@@ -47,20 +48,20 @@ func (msg *MsgCreateStream) Bsvdecode(r io.Reader, pver uint32, _ MessageEncodin
 // This is part of the Message interface implementation.
 func (msg *MsgCreateStream) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error {
 	if len(msg.AssociationID) == 0 {
-		return messageError("MsgCreateStream.BsvEncode", "association ID must not be empty")
+		return messageError("MsgCreateStream.BsvEncode", ErrMalformedMessage, "association ID must not be empty")
 	}
 
 	if len(msg.AssociationID) > MaxAssociationIDLen {
 		str := fmt.Sprintf("association ID too long [len %v, max %v]",
 			len(msg.AssociationID), MaxAssociationIDLen)
-		return messageError("MsgCreateStream.BsvEncode", str)
+		return messageError("MsgCreateStream.BsvEncode", ErrElementTooLarge, str)
 	}
 
 	if err := WriteVarBytes(w, pver, msg.AssociationID); err != nil {
 		return err
 	}
 
-	if err := writeElement(w, uint8(msg.StreamType)); err != nil {
+	if err := writeUint8(w, uint8(msg.StreamType)); err != nil {
 		return err
 	}
 
@@ -71,6 +72,84 @@ func (msg *MsgCreateStream) BsvEncode(w io.Writer, pver uint32, _ MessageEncodin
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgCreateStream) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgCreateStream) PayloadSize(_ uint32) int {
+	// AssociationID + StreamType 1 byte + StreamPolicyName.
+	return VarIntSerializeSize(uint64(len(msg.AssociationID))) + len(msg.AssociationID) + 1 +
+		VarIntSerializeSize(uint64(len(msg.StreamPolicyName))) + len(msg.StreamPolicyName)
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgCreateStream) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgCreateStream) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgCreateStream) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgCreateStream) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgCreateStreamJSON is the JSON representation of a createstrm message.
+type msgCreateStreamJSON struct {
+	AssociationID    jsonHex    `json:"associationid"`
+	StreamType       StreamType `json:"streamtype"`
+	StreamPolicyName string     `json:"streampolicyname"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgCreateStream) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgCreateStreamJSON{
+		AssociationID:    msg.AssociationID,
+		StreamType:       msg.StreamType,
+		StreamPolicyName: msg.StreamPolicyName,
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgCreateStream) UnmarshalJSON(data []byte) error {
+	var j msgCreateStreamJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgCreateStream{
+		AssociationID:    j.AssociationID,
+		StreamType:       j.StreamType,
+		StreamPolicyName: j.StreamPolicyName,
+	}
+
+	return nil
+}
+
 // Command returns the protocol command string for the message.
 func (msg *MsgCreateStream) Command() string {
 	return CmdCreateStream
@@ -82,6 +161,12 @@ func (msg *MsgCreateStream) MaxPayloadLength(_ uint32) uint64 {
 	return MaxVarIntPayload + MaxAssociationIDLen + 1 + MaxVarIntPayload + MaxUserAgentLen
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgCreateStream) Reset() {
+	*msg = MsgCreateStream{}
+}
+
 // NewMsgCreateStream returns a new createstream message.
 func NewMsgCreateStream(associationID []byte, streamType StreamType, policyName string) *MsgCreateStream {
 	return &MsgCreateStream{
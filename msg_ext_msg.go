@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 )
@@ -22,14 +23,18 @@ const MaxExtMsgPayload = 0xFFFFFFFFFFFFFFFF
 
 // Bsvdecode decodes r using the bitcoin protocol encoding into the receiver.
 // This is part of the Message interface implementation.
-func (msg *MsgExtMsg) Bsvdecode(_ io.Reader, pver uint32, _ MessageEncoding) error {
+func (msg *MsgExtMsg) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
 	if pver < ProtoconfVersion {
 		str := fmt.Sprintf("protoconf message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgExtMsg.Bsvdecode", str)
+		return messageError("MsgExtMsg.Bsvdecode", ErrProtocolVersion, str)
 	}
-	// do nothing...
-	return nil
+
+	if err := readUint64(r, &msg.NumberOfFields); err != nil {
+		return err
+	}
+
+	return readUint64(r, &msg.MaxRecvPayloadLength)
 }
 
 // BsvEncode encodes the receiver to w using the bitcoin protocol encoding.
@@ -38,16 +43,88 @@ func (msg *MsgExtMsg) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) err
 	if pver < ProtoconfVersion {
 		str := fmt.Sprintf("protoconf message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgExtMsg.BsvEncode", str)
+		return messageError("MsgExtMsg.BsvEncode", ErrProtocolVersion, str)
+	}
+
+	if err := writeUint64(w, msg.NumberOfFields); err != nil {
+		return err
+	}
+
+	return writeUint64(w, msg.MaxRecvPayloadLength)
+}
+
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgExtMsg) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgExtMsg) PayloadSize(_ uint32) int {
+	// NumberOfFields 8 bytes + MaxRecvPayloadLength 8 bytes.
+	return 16
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgExtMsg) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgExtMsg) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgExtMsg) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgExtMsg) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgExtMsgJSON is the JSON representation of an extmsg message.
+type msgExtMsgJSON struct {
+	NumberOfFields       uint64 `json:"numberoffields"`
+	MaxRecvPayloadLength uint64 `json:"maxrecvpayloadlength"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgExtMsg) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgExtMsgJSON(*msg))
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgExtMsg) UnmarshalJSON(data []byte) error {
+	var j msgExtMsgJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
 	}
 
-	return writeElements(w, msg.NumberOfFields, msg.MaxRecvPayloadLength)
+	*msg = MsgExtMsg(j)
+
+	return nil
 }
 
 // Command returns the protocol command string for the message.  This is part
 // of the Message interface implementation.
 func (msg *MsgExtMsg) Command() string {
-	return CmdProtoconf
+	return CmdExtMsg
 }
 
 // MaxPayloadLength returns the maximum length the payload can be for the
@@ -56,6 +133,12 @@ func (msg *MsgExtMsg) MaxPayloadLength(_ uint32) uint64 {
 	return MaxProtoconfPayload
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgExtMsg) Reset() {
+	*msg = MsgExtMsg{}
+}
+
 // NewMsgExtMsg returns a new bitcoin feefilter message that conforms to
 // the Message interface.  See MsgFeeFilter for details.
 func NewMsgExtMsg(maxRecvPayloadLength uint64) *MsgExtMsg {
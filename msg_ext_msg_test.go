@@ -14,7 +14,7 @@ func TestMsgExtMsgLatest(t *testing.T) {
 	const recvLen uint64 = 12345
 	msg := NewMsgExtMsg(recvLen)
 
-	require.Equal(t, CmdProtoconf, msg.Command())
+	require.Equal(t, CmdExtMsg, msg.Command())
 	require.Equal(t, uint64(1), msg.NumberOfFields)
 	require.Equal(t, recvLen, msg.MaxRecvPayloadLength)
 	require.Equal(t, uint64(MaxProtoconfPayload), msg.MaxPayloadLength(ProtocolVersion))
@@ -23,7 +23,10 @@ func TestMsgExtMsgLatest(t *testing.T) {
 	require.NoError(t, msg.BsvEncode(&b, ProtocolVersion, BaseEncoding))
 	require.Equal(t, "01000000000000003930000000000000", hex.EncodeToString(b.Bytes()))
 
-	require.NoError(t, msg.Bsvdecode(bytes.NewReader(nil), ProtocolVersion, BaseEncoding))
+	var decoded MsgExtMsg
+	require.NoError(t, decoded.Bsvdecode(&b, ProtocolVersion, BaseEncoding))
+	require.Equal(t, msg, &decoded)
+	require.Zero(t, b.Len())
 }
 
 // TestMsgExtMsgCrossProtocol tests the MsgExtMsg message type
@@ -41,4 +44,18 @@ func TestMsgExtMsgWireErrors(t *testing.T) {
 
 	err := msg.BsvEncode(newFixedWriter(0), ProtocolVersion, BaseEncoding)
 	require.ErrorIs(t, err, io.ErrShortWrite)
+
+	err = msg.BsvEncode(newFixedWriter(8), ProtocolVersion, BaseEncoding)
+	require.ErrorIs(t, err, io.ErrShortWrite)
+
+	var b bytes.Buffer
+	require.NoError(t, msg.BsvEncode(&b, ProtocolVersion, BaseEncoding))
+
+	var decoded MsgExtMsg
+
+	err = decoded.Bsvdecode(newFixedReader(0, b.Bytes()), ProtocolVersion, BaseEncoding)
+	require.ErrorIs(t, err, io.EOF)
+
+	err = decoded.Bsvdecode(newFixedReader(12, b.Bytes()), ProtocolVersion, BaseEncoding)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
 }
@@ -7,12 +7,18 @@ package wire
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"io"
 
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
 )
 
+// extendedFormatMarker is written directly after the version of a transaction
+// in the extended format.  It is a zero input count followed by 0x0000000000EF,
+// which legacy parsers reject as invalid.
+var extendedFormatMarker = [6]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0xEF}
+
 // ExtendedTxIn defines an extended bitcoin transaction input.
 type ExtendedTxIn struct {
 	PreviousOutPoint   OutPoint
@@ -46,6 +52,65 @@ func NewExtendedTxIn(prevOut *OutPoint, signatureScript []byte, previousTxSatosh
 	}
 }
 
+// extendedTxInJSON is the JSON representation of an extended transaction
+// input.  It extends the representation of a TxIn with the output it spends,
+// like the prevout of the getrawtransaction RPC of bitcoind.
+type extendedTxInJSON struct {
+	txInJSON
+
+	PrevOut struct {
+		Value        jsonAmount `json:"value"`
+		ScriptPubKey jsonScript `json:"scriptPubKey"`
+	} `json:"prevout"`
+}
+
+// toJSON returns the JSON representation of the input.
+func (t *ExtendedTxIn) toJSON() extendedTxInJSON {
+	j := extendedTxInJSON{
+		txInJSON: newTxInJSON(&t.PreviousOutPoint, t.SignatureScript, t.Sequence),
+	}
+	j.PrevOut.Value = jsonAmount(t.PreviousTxSatoshis)
+	j.PrevOut.ScriptPubKey.Hex = t.PreviousTxScript
+
+	return j
+}
+
+// fromJSON sets the input from its JSON representation.
+func (t *ExtendedTxIn) fromJSON(j *extendedTxInJSON) error {
+	prevOut, signatureScript, err := j.outPoint()
+	if err != nil {
+		return err
+	}
+
+	*t = ExtendedTxIn{
+		PreviousOutPoint:   prevOut,
+		PreviousTxSatoshis: uint64(j.PrevOut.Value),
+		PreviousTxScript:   j.PrevOut.ScriptPubKey.Hex,
+		SignatureScript:    signatureScript,
+		Sequence:           j.Sequence,
+	}
+
+	return nil
+}
+
+// MarshalJSON returns the JSON representation of the input, which is that of
+// a TxIn along with the value and script of the output it spends as prevout.
+// This is part of the json.Marshaler interface implementation.
+func (t ExtendedTxIn) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.toJSON())
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (t *ExtendedTxIn) UnmarshalJSON(data []byte) error {
+	var j extendedTxInJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	return t.fromJSON(&j)
+}
+
 // MsgExtendedTx implements the Message interface and represents a bitcoin tx message.
 // It is used to deliver transaction information in response to a getdata
 // message (MsgGetData) for a given transaction.
@@ -152,16 +217,14 @@ func (msg *MsgExtendedTx) Copy() *MsgTx {
 // See Deserialize for decoding transactions stored to disk, such as in a
 // database, as opposed to decoding transactions from the wire.
 func (msg *MsgExtendedTx) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
-	version, err := binarySerializer.Uint32(r, littleEndian)
+	err := readUint32(r, &msg.Version)
 	if err != nil {
-		return err
+		return traceField(r, err, "Version")
 	}
 
-	msg.Version = int32(version)
-
 	count, err := ReadVarInt(r, pver)
 	if err != nil {
-		return err
+		return traceField(r, err, "EFMarker")
 	}
 
 	// read in the EF header
@@ -169,25 +232,26 @@ func (msg *MsgExtendedTx) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 
 	_, err = io.ReadFull(r, efHeader)
 	if err != nil {
-		return err
+		return traceField(r, err, "EFMarker")
 	}
 
 	if count != 0 || !bytes.Equal(efHeader, []byte{0x00, 0x00, 0x00, 0x00, 0xEF}) {
-		return fmt.Errorf("invalid extended tx EF header") //nolint:err113 // needs refactoring
+		err = messageError("MsgExtendedTx.Bsvdecode", ErrMalformedMessage, "invalid extended tx EF header")
+		return traceField(r, err, "EFMarker")
 	}
 
 	count, err = ReadVarInt(r, pver)
 	if err != nil {
-		return err
+		return traceField(r, err, "TxIn")
 	}
 
 	// Prevent more input transactions than could possibly fit into a
 	// message.  It would be possible to cause memory exhaustion and panic
 	// without a sane upper bound on this count.
-	if count > maxTxInPerMessage() {
+	if count > limitsOf(r).maxTxInPerMessage() {
 		str := fmt.Sprintf("too many input transactions to fit into "+
-			"max message size [count %d, max %d]", count, maxTxInPerMessage())
-		return messageError("MsgTx.Bsvdecode", str)
+			"max message size [count %d, max %d]", count, limitsOf(r).maxTxInPerMessage())
+		return traceField(r, messageError("MsgTx.Bsvdecode", ErrTooManyItems, str), "TxIn")
 	}
 
 	// returnScriptBuffers is a closure that returns any script buffers that
@@ -218,19 +282,17 @@ func (msg *MsgExtendedTx) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 	// Deserialize the inputs.
 	var totalScriptSize uint64
 
-	txIns := make([]ExtendedTxIn, count)
-	msg.TxIn = make([]*ExtendedTxIn, count)
+	msg.TxIn = recycleList(msg.TxIn, count, recyclingOf(r))
 
 	for i := uint64(0); i < count; i++ {
 		// The pointer is set now in case a script buffer is borrowed
 		// and needs to be returned to the pool on error.
-		ti := &txIns[i]
-		msg.TxIn[i] = ti
+		ti := msg.TxIn[i]
 
 		err = readExtendedTxIn(r, pver, msg.Version, ti)
 		if err != nil {
 			returnScriptBuffers()
-			return err
+			return traceItem(r, err, "TxIn", i)
 		}
 
 		totalScriptSize += uint64(len(ti.SignatureScript))
@@ -239,44 +301,42 @@ func (msg *MsgExtendedTx) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 	count, err = ReadVarInt(r, pver)
 	if err != nil {
 		returnScriptBuffers()
-		return err
+		return traceField(r, err, "TxOut")
 	}
 
 	// Prevent more output transactions than could possibly fit into a
 	// message.  It would be possible to cause memory exhaustion and panic
 	// without a sane upper bound on this count.
-	if count > maxTxOutPerMessage() {
+	if count > limitsOf(r).maxTxOutPerMessage() {
 		returnScriptBuffers()
 
 		str := fmt.Sprintf("too many output transactions to fit into "+
-			"max message size [count %d, max %d]", count, maxTxOutPerMessage())
+			"max message size [count %d, max %d]", count, limitsOf(r).maxTxOutPerMessage())
 
-		return messageError("MsgTx.Bsvdecode", str)
+		return traceField(r, messageError("MsgTx.Bsvdecode", ErrTooManyItems, str), "TxOut")
 	}
 
 	// Deserialize the outputs.
-	txOuts := make([]TxOut, count)
-	msg.TxOut = make([]*TxOut, count)
+	msg.TxOut = recycleList(msg.TxOut, count, recyclingOf(r))
 
 	for i := uint64(0); i < count; i++ {
 		// The pointer is set now in case a script buffer is borrowed
 		// and needs to be returned to the pool on error.
-		to := &txOuts[i]
-		msg.TxOut[i] = to
+		to := msg.TxOut[i]
 
 		err = readTxOut(r, pver, msg.Version, to)
 		if err != nil {
 			returnScriptBuffers()
-			return err
+			return traceItem(r, err, "TxOut", i)
 		}
 
 		totalScriptSize += uint64(len(to.PkScript))
 	}
 
-	msg.LockTime, err = binarySerializer.Uint32(r, littleEndian)
+	err = readUint32(r, &msg.LockTime)
 	if err != nil {
 		returnScriptBuffers()
-		return err
+		return traceField(r, err, "LockTime")
 	}
 
 	// Create a single allocation to house all the scripts and set each
@@ -356,13 +416,13 @@ func (msg *MsgExtendedTx) Deserialize(r io.Reader) error {
 // See Serialize for encoding transactions to be stored to disk, such as in a
 // database, as opposed to encoding transactions for the wire.
 func (msg *MsgExtendedTx) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error {
-	err := binarySerializer.PutUint32(w, littleEndian, uint32(msg.Version))
+	err := writeUint32(w, msg.Version)
 	if err != nil {
 		return err
 	}
 
 	// write EF header
-	_, err = w.Write([]byte{0x00, 0x00, 0x00, 0x00, 0x00, 0xEF})
+	_, err = w.Write(extendedFormatMarker[:])
 	if err != nil {
 		return err
 	}
@@ -395,7 +455,112 @@ func (msg *MsgExtendedTx) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 		}
 	}
 
-	return binarySerializer.PutUint32(w, littleEndian, msg.LockTime)
+	return writeUint32(w, msg.LockTime)
+}
+
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgExtendedTx) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgExtendedTx) PayloadSize(_ uint32) int {
+	return msg.SerializeSize()
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgExtendedTx) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgExtendedTx) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgExtendedTx) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgExtendedTx) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgExtendedTxJSON is the JSON representation of an extended transaction.
+type msgExtendedTxJSON struct {
+	TxID     jsonHash           `json:"txid"`
+	Hash     jsonHash           `json:"hash"`
+	Version  int32              `json:"version"`
+	Size     int                `json:"size"`
+	LockTime uint32             `json:"locktime"`
+	Vin      []extendedTxInJSON `json:"vin"`
+	Vout     []txOutJSON        `json:"vout"`
+}
+
+// MarshalJSON returns the JSON representation of the transaction, which is
+// that of a MsgTx with the inputs extended with the outputs they spend.  The
+// txid, hash and size are computed from the transaction and ignored by
+// UnmarshalJSON.  This is part of the json.Marshaler interface implementation.
+func (msg *MsgExtendedTx) MarshalJSON() ([]byte, error) {
+	txHash := jsonHash(msg.TxHash())
+
+	vin := make([]extendedTxInJSON, len(msg.TxIn))
+	for i, ti := range msg.TxIn {
+		vin[i] = ti.toJSON()
+	}
+
+	return json.Marshal(msgExtendedTxJSON{
+		TxID:     txHash,
+		Hash:     txHash,
+		Version:  msg.Version,
+		Size:     msg.SerializeSize(),
+		LockTime: msg.LockTime,
+		Vin:      vin,
+		Vout:     toTxOutsJSON(msg.TxOut),
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgExtendedTx) UnmarshalJSON(data []byte) error {
+	var j msgExtendedTxJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	backing := make([]ExtendedTxIn, len(j.Vin))
+	txIns := make([]*ExtendedTxIn, len(j.Vin))
+
+	for i := range j.Vin {
+		if err := backing[i].fromJSON(&j.Vin[i]); err != nil {
+			return err
+		}
+
+		txIns[i] = &backing[i]
+	}
+
+	*msg = MsgExtendedTx{
+		Version:  j.Version,
+		TxIn:     txIns,
+		TxOut:    fromTxOutsJSON(j.Vout),
+		LockTime: j.LockTime,
+	}
+
+	return nil
 }
 
 // Serialize encodes the transaction to w using a format that suitable for
@@ -435,10 +600,11 @@ func (msg *MsgExtendedTx) baseSize() int {
 }
 
 // SerializeSize returns the number of bytes it would take to serialize the
-//
-//	transaction.
+// transaction.  This includes the 6-byte extended format marker, so it is the
+// number of bytes BsvEncode writes and always exceeds the size of the
+// transaction in the legacy format by at least 6 bytes.
 func (msg *MsgExtendedTx) SerializeSize() int {
-	return msg.baseSize()
+	return msg.baseSize() + len(extendedFormatMarker)
 }
 
 // Command returns the protocol command string for the message.  This is part
@@ -492,6 +658,13 @@ func (msg *MsgExtendedTx) PkScriptLocs() []int {
 	return pkScriptLocs
 }
 
+// Reset resets the message to its zero state while keeping the capacity of
+// TxIn and TxOut, along with the inputs and outputs they point to, for reuse
+// by the next decode.  This is part of the Resetter interface implementation.
+func (msg *MsgExtendedTx) Reset() {
+	*msg = MsgExtendedTx{TxIn: msg.TxIn[:0], TxOut: msg.TxOut[:0]}
+}
+
 // NewMsgExtendedTx returns a new extended bitcoin tx message that conforms to the Message
 // interface.  The return instance has a default version of TxVersion, and there
 // are no transaction inputs or outputs.  Also, the lock time is set to zero
@@ -510,30 +683,30 @@ func NewMsgExtendedTx(version int32) *MsgExtendedTx {
 func readExtendedTxIn(r io.Reader, pver uint32, version int32, ti *ExtendedTxIn) error {
 	err := readOutPoint(r, pver, version, &ti.PreviousOutPoint)
 	if err != nil {
-		return err
+		return traceField(r, err, "PreviousOutPoint")
 	}
 
-	ti.SignatureScript, err = readScript(r, pver, maxTxInPerMessage(),
+	ti.SignatureScript, err = readScript(r, pver, limitsOf(r).maxTxInPerMessage(),
 		"transaction input signature script")
 	if err != nil {
-		return err
+		return traceField(r, err, "SignatureScript")
 	}
 
-	err = readElement(r, &ti.Sequence)
+	err = readUint32(r, &ti.Sequence)
 	if err != nil {
-		return err
+		return traceField(r, err, "Sequence")
 	}
 
-	err = readElement(r, &ti.PreviousTxSatoshis)
+	err = readUint64(r, &ti.PreviousTxSatoshis)
 	if err != nil {
-		return err
+		return traceField(r, err, "PreviousTxSatoshis")
 	}
 
 	// read the previous tx script
-	ti.PreviousTxScript, err = readScript(r, pver, maxTxInPerMessage(),
+	ti.PreviousTxScript, err = readScript(r, pver, limitsOf(r).maxTxInPerMessage(),
 		"transaction previous tx script")
 	if err != nil {
-		return err
+		return traceField(r, err, "PreviousTxScript")
 	}
 
 	return nil
@@ -552,12 +725,12 @@ func writeExtendedTxIn(w io.Writer, pver uint32, version int32, ti *ExtendedTxIn
 		return err
 	}
 
-	err = binarySerializer.PutUint32(w, littleEndian, ti.Sequence)
+	err = writeUint32(w, ti.Sequence)
 	if err != nil {
 		return err
 	}
 
-	err = binarySerializer.PutUint64(w, littleEndian, ti.PreviousTxSatoshis)
+	err = writeUint64(w, ti.PreviousTxSatoshis)
 	if err != nil {
 		return err
 	}
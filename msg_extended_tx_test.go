@@ -459,6 +459,51 @@ func TestExtendedTxSerializeSizeStripped(t *testing.T) {
 	}
 }
 
+// TestExtendedTxSerializeSize ensures the serialize and payload sizes of
+// extended transactions count the extended format marker along with
+// everything else BsvEncode writes.
+func TestExtendedTxSerializeSize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   *MsgExtendedTx
+		size int
+	}{
+		// Version 4 bytes + marker 6 bytes + varint input and output
+		// counts 1 byte each + lock time 4 bytes.
+		{"no inputs or outputs", &MsgExtendedTx{Version: 1}, 16},
+		{"input and output", multiExtendedTx, multiExtendedTx.baseSize() + 6},
+	}
+
+	t.Logf("Running %d tests", len(tests))
+
+	for i, test := range tests {
+		serializedSize := test.in.SerializeSize()
+		if serializedSize != test.size {
+			t.Errorf("MsgExtendedTx.SerializeSize: #%d (%s) got: %d, want: %d",
+				i, test.name, serializedSize, test.size)
+			continue
+		}
+
+		payloadSize := test.in.PayloadSize(ProtocolVersion)
+		if payloadSize != test.size {
+			t.Errorf("MsgExtendedTx.PayloadSize: #%d (%s) got: %d, want: %d",
+				i, test.name, payloadSize, test.size)
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := test.in.BsvEncode(&buf, ProtocolVersion, BaseEncoding); err != nil {
+			t.Errorf("BsvEncode #%d (%s) error %v", i, test.name, err)
+			continue
+		}
+
+		if buf.Len() != test.size {
+			t.Errorf("BsvEncode #%d (%s) wrote %d bytes, want: %d",
+				i, test.name, buf.Len(), test.size)
+		}
+	}
+}
+
 var script, _ = hex.DecodeString("3ba27aa200b1cecaad478d2b00432346c3f1f3986da1afd33e5006")
 
 // multiExtendedTx is a MsgTx with an input and output and used in various tests.
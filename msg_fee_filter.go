@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 )
@@ -25,10 +26,10 @@ func (msg *MsgFeeFilter) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 	if pver < FeeFilterVersion {
 		str := fmt.Sprintf("feefilter message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgFeeFilter.Bsvdecode", str)
+		return messageError("MsgFeeFilter.Bsvdecode", ErrProtocolVersion, str)
 	}
 
-	return readElement(r, &msg.MinFee)
+	return readUint64(r, &msg.MinFee)
 }
 
 // BsvEncode encodes the receiver to w using the bitcoin protocol encoding.
@@ -37,10 +38,77 @@ func (msg *MsgFeeFilter) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 	if pver < FeeFilterVersion {
 		str := fmt.Sprintf("feefilter message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgFeeFilter.BsvEncode", str)
+		return messageError("MsgFeeFilter.BsvEncode", ErrProtocolVersion, str)
 	}
 
-	return writeElement(w, msg.MinFee)
+	return writeUint64(w, msg.MinFee)
+}
+
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgFeeFilter) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgFeeFilter) PayloadSize(_ uint32) int {
+	// MinFee 8 bytes.
+	return 8
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgFeeFilter) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgFeeFilter) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgFeeFilter) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgFeeFilter) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgFeeFilterJSON is the JSON representation of a feefilter message.
+type msgFeeFilterJSON struct {
+	MinFee int64 `json:"minfee"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgFeeFilter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgFeeFilterJSON(*msg))
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgFeeFilter) UnmarshalJSON(data []byte) error {
+	var j msgFeeFilterJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgFeeFilter(j)
+
+	return nil
 }
 
 // Command returns the protocol command string for the message.  This is part
@@ -55,6 +123,12 @@ func (msg *MsgFeeFilter) MaxPayloadLength(_ uint32) uint64 {
 	return 8
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgFeeFilter) Reset() {
+	*msg = MsgFeeFilter{}
+}
+
 // NewMsgFeeFilter returns a new bitcoin feefilter message that conforms to
 // the Message interface.  See MsgFeeFilter for details.
 func NewMsgFeeFilter(minFee int64) *MsgFeeFilter {
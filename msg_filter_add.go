@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 )
@@ -31,7 +32,7 @@ func (msg *MsgFilterAdd) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 	if pver < BIP0037Version {
 		str := fmt.Sprintf("filteradd message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgFilterAdd.Bsvdecode", str)
+		return messageError("MsgFilterAdd.Bsvdecode", ErrProtocolVersion, str)
 	}
 
 	var err error
@@ -47,19 +48,85 @@ func (msg *MsgFilterAdd) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 	if pver < BIP0037Version {
 		str := fmt.Sprintf("filteradd message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgFilterAdd.BsvEncode", str)
+		return messageError("MsgFilterAdd.BsvEncode", ErrProtocolVersion, str)
 	}
 
 	size := len(msg.Data)
 	if size > MaxFilterAddDataSize {
 		str := fmt.Sprintf("filteradd size too large for message "+
 			"[size %v, max %v]", size, MaxFilterAddDataSize)
-		return messageError("MsgFilterAdd.BsvEncode", str)
+		return messageError("MsgFilterAdd.BsvEncode", ErrElementTooLarge, str)
 	}
 
 	return WriteVarBytes(w, pver, msg.Data)
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgFilterAdd) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgFilterAdd) PayloadSize(_ uint32) int {
+	return VarIntSerializeSize(uint64(len(msg.Data))) + len(msg.Data)
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgFilterAdd) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgFilterAdd) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgFilterAdd) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgFilterAdd) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgFilterAddJSON is the JSON representation of a filteradd message.
+type msgFilterAddJSON struct {
+	Data jsonHex `json:"data"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgFilterAdd) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgFilterAddJSON{Data: msg.Data})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgFilterAdd) UnmarshalJSON(data []byte) error {
+	var j msgFilterAddJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgFilterAdd{Data: j.Data}
+
+	return nil
+}
+
 // Command returns the protocol command string for the message.  This is part
 // of the Message interface implementation.
 func (msg *MsgFilterAdd) Command() string {
@@ -73,6 +140,12 @@ func (msg *MsgFilterAdd) MaxPayloadLength(_ uint32) uint64 {
 		MaxFilterAddDataSize
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgFilterAdd) Reset() {
+	*msg = MsgFilterAdd{}
+}
+
 // NewMsgFilterAdd returns a new bitcoin filteradd message that conforms to the
 // Message interface.  See MsgFilterAdd for details.
 func NewMsgFilterAdd(data []byte) *MsgFilterAdd {
@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 )
@@ -22,7 +23,7 @@ func (msg *MsgFilterClear) Bsvdecode(_ io.Reader, pver uint32, _ MessageEncoding
 	if pver < BIP0037Version {
 		str := fmt.Sprintf("filterclear message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgFilterClear.Bsvdecode", str)
+		return messageError("MsgFilterClear.Bsvdecode", ErrProtocolVersion, str)
 	}
 
 	return nil
@@ -34,12 +35,68 @@ func (msg *MsgFilterClear) BsvEncode(_ io.Writer, pver uint32, _ MessageEncoding
 	if pver < BIP0037Version {
 		str := fmt.Sprintf("filterclear message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgFilterClear.BsvEncode", str)
+		return messageError("MsgFilterClear.BsvEncode", ErrProtocolVersion, str)
 	}
 
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgFilterClear) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgFilterClear) PayloadSize(_ uint32) int {
+	return 0
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgFilterClear) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgFilterClear) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgFilterClear) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgFilterClear) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// MarshalJSON returns the JSON representation of the message, which is an
+// empty object since the message has no payload.  This is part of the
+// json.Marshaler interface implementation.
+func (msg *MsgFilterClear) MarshalJSON() ([]byte, error) {
+	return []byte("{}"), nil
+}
+
+// UnmarshalJSON checks that data is the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgFilterClear) UnmarshalJSON(data []byte) error {
+	var j struct{}
+	return json.Unmarshal(data, &j)
+}
+
 // Command returns the protocol command string for the message.  This is part
 // of the Message interface implementation.
 func (msg *MsgFilterClear) Command() string {
@@ -52,6 +109,12 @@ func (msg *MsgFilterClear) MaxPayloadLength(_ uint32) uint64 {
 	return 0
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgFilterClear) Reset() {
+	*msg = MsgFilterClear{}
+}
+
 // NewMsgFilterClear returns a new bitcoin filterclear message that conforms to the Message
 // interface.  See MsgFilterClear for details.
 func NewMsgFilterClear() *MsgFilterClear {
@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 )
@@ -55,7 +56,7 @@ func (msg *MsgFilterLoad) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 	if pver < BIP0037Version {
 		str := fmt.Sprintf("filterload message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgFilterLoad.Bsvdecode", str)
+		return messageError("MsgFilterLoad.Bsvdecode", ErrProtocolVersion, str)
 	}
 
 	var err error
@@ -66,7 +67,17 @@ func (msg *MsgFilterLoad) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 		return err
 	}
 
-	err = readElements(r, &msg.HashFuncs, &msg.Tweak, &msg.Flags)
+	err = readUint32(r, &msg.HashFuncs)
+	if err != nil {
+		return err
+	}
+
+	err = readUint32(r, &msg.Tweak)
+	if err != nil {
+		return err
+	}
+
+	err = readUint8(r, &msg.Flags)
 	if err != nil {
 		return err
 	}
@@ -74,7 +85,7 @@ func (msg *MsgFilterLoad) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 	if msg.HashFuncs > MaxFilterLoadHashFuncs {
 		str := fmt.Sprintf("too many filter hash functions for message "+
 			"[count %v, max %v]", msg.HashFuncs, MaxFilterLoadHashFuncs)
-		return messageError("MsgFilterLoad.Bsvdecode", str)
+		return messageError("MsgFilterLoad.Bsvdecode", ErrTooManyItems, str)
 	}
 
 	return nil
@@ -86,20 +97,20 @@ func (msg *MsgFilterLoad) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 	if pver < BIP0037Version {
 		str := fmt.Sprintf("filterload message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgFilterLoad.BsvEncode", str)
+		return messageError("MsgFilterLoad.BsvEncode", ErrProtocolVersion, str)
 	}
 
 	size := len(msg.Filter)
 	if size > MaxFilterLoadFilterSize {
 		str := fmt.Sprintf("filterload filter size too large for message "+
 			"[size %v, max %v]", size, MaxFilterLoadFilterSize)
-		return messageError("MsgFilterLoad.BsvEncode", str)
+		return messageError("MsgFilterLoad.BsvEncode", ErrElementTooLarge, str)
 	}
 
 	if msg.HashFuncs > MaxFilterLoadHashFuncs {
 		str := fmt.Sprintf("too many filter hash functions for message "+
 			"[count %v, max %v]", msg.HashFuncs, MaxFilterLoadHashFuncs)
-		return messageError("MsgFilterLoad.BsvEncode", str)
+		return messageError("MsgFilterLoad.BsvEncode", ErrTooManyItems, str)
 	}
 
 	err := WriteVarBytes(w, pver, msg.Filter)
@@ -107,7 +118,97 @@ func (msg *MsgFilterLoad) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 		return err
 	}
 
-	return writeElements(w, msg.HashFuncs, msg.Tweak, msg.Flags)
+	err = writeUint32(w, msg.HashFuncs)
+	if err != nil {
+		return err
+	}
+
+	err = writeUint32(w, msg.Tweak)
+	if err != nil {
+		return err
+	}
+
+	return writeUint8(w, msg.Flags)
+}
+
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgFilterLoad) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgFilterLoad) PayloadSize(_ uint32) int {
+	// Filter + HashFuncs 4 bytes + Tweak 4 bytes + Flags 1 byte.
+	return VarIntSerializeSize(uint64(len(msg.Filter))) + len(msg.Filter) + 9
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgFilterLoad) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgFilterLoad) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgFilterLoad) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgFilterLoad) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgFilterLoadJSON is the JSON representation of a filterload message.
+type msgFilterLoadJSON struct {
+	Filter    jsonHex         `json:"filter"`
+	HashFuncs uint32          `json:"hashfuncs"`
+	Tweak     uint32          `json:"tweak"`
+	Flags     BloomUpdateType `json:"flags"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgFilterLoad) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgFilterLoadJSON{
+		Filter:    msg.Filter,
+		HashFuncs: msg.HashFuncs,
+		Tweak:     msg.Tweak,
+		Flags:     msg.Flags,
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgFilterLoad) UnmarshalJSON(data []byte) error {
+	var j msgFilterLoadJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgFilterLoad{
+		Filter:    j.Filter,
+		HashFuncs: j.HashFuncs,
+		Tweak:     j.Tweak,
+		Flags:     j.Flags,
+	}
+
+	return nil
 }
 
 // Command returns the protocol command string for the message.  This is part
@@ -126,6 +227,12 @@ func (msg *MsgFilterLoad) MaxPayloadLength(_ uint32) uint64 {
 		MaxFilterLoadFilterSize + 9
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgFilterLoad) Reset() {
+	*msg = MsgFilterLoad{}
+}
+
 // NewMsgFilterLoad returns a new bitcoin filterload message that conforms to
 // the Message interface.  See MsgFilterLoad for details.
 func NewMsgFilterLoad(filter []byte, hashFuncs, tweak uint32, flags BloomUpdateType) *MsgFilterLoad {
@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"io"
 
 	"github.com/bsv-blockchain/go-bt/v2/chainhash"
@@ -22,33 +23,110 @@ type MsgGetCFHeaders struct {
 // Bsvdecode decodes r using the bitcoin protocol encoding into the receiver.
 // This is part of the Message interface implementation.
 func (msg *MsgGetCFHeaders) Bsvdecode(r io.Reader, _ uint32, _ MessageEncoding) error {
-	err := readElement(r, &msg.FilterType)
+	err := readUint8(r, &msg.FilterType)
 	if err != nil {
 		return err
 	}
 
-	err = readElement(r, &msg.StartHeight)
+	err = readUint32(r, &msg.StartHeight)
 	if err != nil {
 		return err
 	}
 
-	return readElement(r, &msg.StopHash)
+	return readHash(r, &msg.StopHash)
 }
 
 // BsvEncode encodes the receiver to w using the bitcoin protocol encoding.
 // This is part of the Message interface implementation.
 func (msg *MsgGetCFHeaders) BsvEncode(w io.Writer, _ uint32, _ MessageEncoding) error {
-	err := writeElement(w, msg.FilterType)
+	err := writeUint8(w, msg.FilterType)
 	if err != nil {
 		return err
 	}
 
-	err = writeElement(w, &msg.StartHeight)
+	err = writeUint32(w, msg.StartHeight)
 	if err != nil {
 		return err
 	}
 
-	return writeElement(w, &msg.StopHash)
+	return writeHash(w, &msg.StopHash)
+}
+
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgGetCFHeaders) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgGetCFHeaders) PayloadSize(_ uint32) int {
+	// FilterType 1 byte + StartHeight 4 bytes + StopHash.
+	return 5 + chainhash.HashSize
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgGetCFHeaders) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgGetCFHeaders) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgGetCFHeaders) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgGetCFHeaders) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgGetCFHeadersJSON is the JSON representation of a getcfheaders message.
+type msgGetCFHeadersJSON struct {
+	FilterType  FilterType `json:"filtertype"`
+	StartHeight uint32     `json:"startheight"`
+	StopHash    jsonHash   `json:"stophash"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgGetCFHeaders) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgGetCFHeadersJSON{
+		FilterType:  msg.FilterType,
+		StartHeight: msg.StartHeight,
+		StopHash:    jsonHash(msg.StopHash),
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgGetCFHeaders) UnmarshalJSON(data []byte) error {
+	var j msgGetCFHeadersJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgGetCFHeaders{
+		FilterType:  j.FilterType,
+		StartHeight: j.StartHeight,
+		StopHash:    chainhash.Hash(j.StopHash),
+	}
+
+	return nil
 }
 
 // Command returns the protocol command string for the message.  This is part
@@ -64,6 +142,12 @@ func (msg *MsgGetCFHeaders) MaxPayloadLength(_ uint32) uint64 {
 	return 1 + 4 + chainhash.HashSize
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgGetCFHeaders) Reset() {
+	*msg = MsgGetCFHeaders{}
+}
+
 // NewMsgGetCFHeaders returns a new bitcoin getcfheader message that conforms to
 // the Message interface using the passed parameters and defaults for the
 // remaining fields.
@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 
@@ -38,7 +39,7 @@ func (msg *MsgGetHeaders) AddBlockLocatorHash(hash *chainhash.Hash) error {
 	if len(msg.BlockLocatorHashes)+1 > MaxBlockLocatorsPerMsg {
 		str := fmt.Sprintf("too many block locator hashes for message [max %v]",
 			MaxBlockLocatorsPerMsg)
-		return messageError("MsgGetHeaders.AddBlockLocatorHash", str)
+		return messageError("MsgGetHeaders.AddBlockLocatorHash", ErrTooManyItems, str)
 	}
 
 	msg.BlockLocatorHashes = append(msg.BlockLocatorHashes, hash)
@@ -49,43 +50,47 @@ func (msg *MsgGetHeaders) AddBlockLocatorHash(hash *chainhash.Hash) error {
 // Bsvdecode decodes r using the bitcoin protocol encoding into the receiver.
 // This is part of the Message interface implementation.
 func (msg *MsgGetHeaders) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
-	err := readElement(r, &msg.ProtocolVersion)
+	err := readUint32(r, &msg.ProtocolVersion)
 	if err != nil {
-		return err
+		return traceField(r, err, "ProtocolVersion")
 	}
 
 	// Read num block locator hashes and limit to max.
 	count, err := ReadVarInt(r, pver)
 	if err != nil {
-		return err
+		return traceField(r, err, "BlockLocatorHashes")
 	}
 
 	if count > MaxBlockLocatorsPerMsg {
 		str := fmt.Sprintf("too many block locator hashes for message "+
 			"[count %v, max %v]", count, MaxBlockLocatorsPerMsg)
-		return messageError("MsgGetHeaders.Bsvdecode", str)
+		return traceField(r, messageError("MsgGetHeaders.Bsvdecode", ErrTooManyItems, str), "BlockLocatorHashes")
 	}
 
 	// Create a contiguous slice of hashes to deserialize into to
-	// reduce the number of allocations.
-	locatorHashes := make([]chainhash.Hash, count)
-	msg.BlockLocatorHashes = make([]*chainhash.Hash, 0, count)
+	// reduce the number of allocations, reusing those kept by Reset.
+	locatorHashes := recycleList(msg.BlockLocatorHashes, count, recyclingOf(r))
+	msg.BlockLocatorHashes = locatorHashes[:0]
 
 	for i := uint64(0); i < count; i++ {
-		hash := &locatorHashes[i]
+		hash := locatorHashes[i]
 
-		err = readElement(r, hash)
+		err = readHash(r, hash)
 		if err != nil {
-			return err
+			return traceItem(r, err, "BlockLocatorHashes", i)
 		}
 
 		err = msg.AddBlockLocatorHash(hash)
 		if err != nil {
-			return err
+			return traceItem(r, err, "BlockLocatorHashes", i)
 		}
 	}
 
-	return readElement(r, &msg.HashStop)
+	if err = readHash(r, &msg.HashStop); err != nil {
+		return traceField(r, err, "HashStop")
+	}
+
+	return nil
 }
 
 // BsvEncode encodes the receiver to w using the bitcoin protocol encoding.
@@ -96,10 +101,10 @@ func (msg *MsgGetHeaders) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 	if count > MaxBlockLocatorsPerMsg {
 		str := fmt.Sprintf("too many block locator hashes for message "+
 			"[count %v, max %v]", count, MaxBlockLocatorsPerMsg)
-		return messageError("MsgGetHeaders.BsvEncode", str)
+		return messageError("MsgGetHeaders.BsvEncode", ErrTooManyItems, str)
 	}
 
-	err := writeElement(w, msg.ProtocolVersion)
+	err := writeUint32(w, msg.ProtocolVersion)
 	if err != nil {
 		return err
 	}
@@ -110,13 +115,92 @@ func (msg *MsgGetHeaders) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 	}
 
 	for _, hash := range msg.BlockLocatorHashes {
-		err := writeElement(w, hash)
+		err := writeHash(w, hash)
 		if err != nil {
 			return err
 		}
 	}
 
-	return writeElement(w, &msg.HashStop)
+	return writeHash(w, &msg.HashStop)
+}
+
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgGetHeaders) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgGetHeaders) PayloadSize(_ uint32) int {
+	// ProtocolVersion 4 bytes + BlockLocatorHashes + HashStop.
+	count := len(msg.BlockLocatorHashes)
+
+	return 4 + VarIntSerializeSize(uint64(count)) + (count+1)*chainhash.HashSize
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgGetHeaders) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgGetHeaders) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgGetHeaders) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgGetHeaders) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgGetHeadersJSON is the JSON representation of a getheaders message.
+type msgGetHeadersJSON struct {
+	ProtocolVersion    uint32     `json:"protocolversion"`
+	BlockLocatorHashes []jsonHash `json:"blocklocatorhashes"`
+	HashStop           jsonHash   `json:"hashstop"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgGetHeaders) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgGetHeadersJSON{
+		ProtocolVersion:    msg.ProtocolVersion,
+		BlockLocatorHashes: toJSONHashes(msg.BlockLocatorHashes),
+		HashStop:           jsonHash(msg.HashStop),
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgGetHeaders) UnmarshalJSON(data []byte) error {
+	var j msgGetHeadersJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgGetHeaders{
+		ProtocolVersion:    j.ProtocolVersion,
+		BlockLocatorHashes: fromJSONHashes(j.BlockLocatorHashes),
+		HashStop:           chainhash.Hash(j.HashStop),
+	}
+
+	return nil
 }
 
 // Command returns the protocol command string for the message.  This is part
@@ -134,6 +218,14 @@ func (msg *MsgGetHeaders) MaxPayloadLength(_ uint32) uint64 {
 		chainhash.HashSize) + chainhash.HashSize
 }
 
+// Reset resets the message to its zero state while keeping the capacity of
+// BlockLocatorHashes, along with the block locator hashes it points to, for
+// reuse by the next decode.  This is part of the Resetter interface
+// implementation.
+func (msg *MsgGetHeaders) Reset() {
+	*msg = MsgGetHeaders{BlockLocatorHashes: msg.BlockLocatorHashes[:0]}
+}
+
 // NewMsgGetHeaders returns a new bitcoin getheaders message that conforms to
 // the Message interface.  See MsgGetHeaders for details.
 func NewMsgGetHeaders() *MsgGetHeaders {
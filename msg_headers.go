@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 )
@@ -27,7 +28,7 @@ func (msg *MsgHeaders) AddBlockHeader(bh *BlockHeader) error {
 	if len(msg.Headers)+1 > MaxBlockHeadersPerMsg {
 		str := fmt.Sprintf("too many block headers in message [max %v]",
 			MaxBlockHeadersPerMsg)
-		return messageError("MsgHeaders.AddBlockHeader", str)
+		return messageError("MsgHeaders.AddBlockHeader", ErrTooManyItems, str)
 	}
 
 	msg.Headers = append(msg.Headers, bh)
@@ -40,45 +41,45 @@ func (msg *MsgHeaders) AddBlockHeader(bh *BlockHeader) error {
 func (msg *MsgHeaders) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
 	count, err := ReadVarInt(r, pver)
 	if err != nil {
-		return err
+		return traceField(r, err, "Headers")
 	}
 
 	// Limit to max block headers per message.
 	if count > MaxBlockHeadersPerMsg {
 		str := fmt.Sprintf("too many block headers for message "+
 			"[count %v, max %v]", count, MaxBlockHeadersPerMsg)
-		return messageError("MsgHeaders.Bsvdecode", str)
+		return traceField(r, messageError("MsgHeaders.Bsvdecode", ErrTooManyItems, str), "Headers")
 	}
 
 	// Create a contiguous slice of headers to deserialize into to
-	// reduce the number of allocations.
-	headers := make([]BlockHeader, count)
-	msg.Headers = make([]*BlockHeader, 0, count)
+	// reduce the number of allocations, reusing those kept by Reset.
+	headers := recycleList(msg.Headers, count, recyclingOf(r))
+	msg.Headers = headers[:0]
 
 	for i := uint64(0); i < count; i++ {
-		bh := &headers[i]
+		bh := headers[i]
 
 		err = readBlockHeader(r, pver, bh)
 		if err != nil {
-			return err
+			return traceItem(r, err, "Headers", i)
 		}
 
 		var txCount uint64
 		txCount, err = ReadVarInt(r, pver)
 		if err != nil {
-			return err
+			return traceItem(r, err, "Headers", i)
 		}
 
 		// Ensure the transaction count is zero for headers.
 		if txCount > 0 {
 			str := fmt.Sprintf("block headers may not contain "+
 				"transactions [count %v]", txCount)
-			return messageError("MsgHeaders.Bsvdecode", str)
+			return traceItem(r, messageError("MsgHeaders.Bsvdecode", ErrMalformedMessage, str), "Headers", i)
 		}
 
 		err = msg.AddBlockHeader(bh)
 		if err != nil {
-			return err
+			return traceItem(r, err, "Headers", i)
 		}
 	}
 
@@ -93,7 +94,7 @@ func (msg *MsgHeaders) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) er
 	if count > MaxBlockHeadersPerMsg {
 		str := fmt.Sprintf("too many block headers for message "+
 			"[count %v, max %v]", count, MaxBlockHeadersPerMsg)
-		return messageError("MsgHeaders.BsvEncode", str)
+		return messageError("MsgHeaders.BsvEncode", ErrTooManyItems, str)
 	}
 
 	err := WriteVarInt(w, pver, uint64(count))
@@ -120,6 +121,76 @@ func (msg *MsgHeaders) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) er
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgHeaders) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgHeaders) PayloadSize(_ uint32) int {
+	// Each header is followed by a transaction count of 0, which takes a
+	// single byte.
+	count := len(msg.Headers)
+
+	return VarIntSerializeSize(uint64(count)) + count*(blockHeaderLen+1)
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgHeaders) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgHeaders) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgHeaders) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgHeaders) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgHeadersJSON is the JSON representation of a headers message.
+type msgHeadersJSON struct {
+	Headers []*BlockHeader `json:"headers"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgHeaders) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgHeadersJSON{Headers: nonNil(msg.Headers)})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgHeaders) UnmarshalJSON(data []byte) error {
+	var j msgHeadersJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgHeaders{Headers: j.Headers}
+
+	return nil
+}
+
 // Command returns the protocol command string for the message.  This is part
 // of the Message interface implementation.
 func (msg *MsgHeaders) Command() string {
@@ -135,6 +206,13 @@ func (msg *MsgHeaders) MaxPayloadLength(_ uint32) uint64 {
 		MaxBlockHeadersPerMsg)
 }
 
+// Reset resets the message to its zero state while keeping the capacity of
+// Headers, along with the block headers it points to, for reuse by the next
+// decode.  This is part of the Resetter interface implementation.
+func (msg *MsgHeaders) Reset() {
+	*msg = MsgHeaders{Headers: msg.Headers[:0]}
+}
+
 // NewMsgHeaders returns a new bitcoin headers message that conforms to the
 // Message interface.  See MsgHeaders for details.
 func NewMsgHeaders() *MsgHeaders {
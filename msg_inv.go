@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 )
@@ -36,7 +37,7 @@ func (msg *MsgInv) AddInvVect(iv *InvVect) error {
 	if len(msg.InvList)+1 > MaxInvPerMsg {
 		str := fmt.Sprintf("too many invvect in message [max %v]",
 			MaxInvPerMsg)
-		return messageError("MsgInv.AddInvVect", str)
+		return messageError("MsgInv.AddInvVect", ErrTooManyItems, str)
 	}
 
 	msg.InvList = append(msg.InvList, iv)
@@ -49,31 +50,31 @@ func (msg *MsgInv) AddInvVect(iv *InvVect) error {
 func (msg *MsgInv) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
 	count, err := ReadVarInt(r, pver)
 	if err != nil {
-		return err
+		return traceField(r, err, "InvList")
 	}
 
 	// Limit to max inventory vectors per message.
 	if count > MaxInvPerMsg {
 		str := fmt.Sprintf("too many invvect in message [%v]", count)
-		return messageError("MsgInv.Bsvdecode", str)
+		return traceField(r, messageError("MsgInv.Bsvdecode", ErrTooManyItems, str), "InvList")
 	}
 
 	// Create a contiguous slice of inventory vectors to deserialize into
-	// to reduce the number of allocations.
-	invList := make([]InvVect, count)
-	msg.InvList = make([]*InvVect, 0, count)
+	// to reduce the number of allocations, reusing those kept by Reset.
+	invList := recycleList(msg.InvList, count, recyclingOf(r))
+	msg.InvList = invList[:0]
 
 	for i := uint64(0); i < count; i++ {
-		iv := &invList[i]
+		iv := invList[i]
 
 		err = readInvVect(r, pver, iv)
 		if err != nil {
-			return err
+			return traceItem(r, err, "InvList", i)
 		}
 
 		err = msg.AddInvVect(iv)
 		if err != nil {
-			return err
+			return traceItem(r, err, "InvList", i)
 		}
 	}
 
@@ -87,7 +88,7 @@ func (msg *MsgInv) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error
 	count := len(msg.InvList)
 	if count > MaxInvPerMsg {
 		str := fmt.Sprintf("too many invvect in message [%v]", count)
-		return messageError("MsgInv.BsvEncode", str)
+		return messageError("MsgInv.BsvEncode", ErrTooManyItems, str)
 	}
 
 	err := WriteVarInt(w, pver, uint64(count))
@@ -105,6 +106,72 @@ func (msg *MsgInv) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgInv) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgInv) PayloadSize(_ uint32) int {
+	return VarIntSerializeSize(uint64(len(msg.InvList))) + len(msg.InvList)*maxInvVectPayload
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgInv) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgInv) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgInv) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgInv) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgInvJSON is the JSON representation of an inv message.
+type msgInvJSON struct {
+	InvList []*InvVect `json:"inventory"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgInv) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgInvJSON{InvList: nonNil(msg.InvList)})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgInv) UnmarshalJSON(data []byte) error {
+	var j msgInvJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgInv{InvList: j.InvList}
+
+	return nil
+}
+
 // Command returns the protocol command string for the message.  This is part
 // of the Message interface implementation.
 func (msg *MsgInv) Command() string {
@@ -118,6 +185,13 @@ func (msg *MsgInv) MaxPayloadLength(_ uint32) uint64 {
 	return MaxVarIntPayload + (MaxInvPerMsg * maxInvVectPayload)
 }
 
+// Reset resets the message to its zero state while keeping the capacity of
+// InvList, along with the inventory vectors it points to, for reuse by the
+// next decode.  This is part of the Resetter interface implementation.
+func (msg *MsgInv) Reset() {
+	*msg = MsgInv{InvList: msg.InvList[:0]}
+}
+
 // NewMsgInv returns a new bitcoin inv message that conforms to the Message
 // interface.  See MsgInv for details.
 func NewMsgInv() *MsgInv {
@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 
@@ -16,7 +17,13 @@ import (
 // , this is the max number of transactions per block divided by
 // 8 bits per byte.  Then an extra one to cover partials.
 func maxFlagsPerMerkleBlock() uint64 {
-	return maxTxPerBlock() / 8
+	return decodeLimits{}.maxFlagsPerMerkleBlock()
+}
+
+// maxFlagsPerMerkleBlock returns the maximum number of flag bytes that could
+// possibly fit into a merkle block within the limits.
+func (l decodeLimits) maxFlagsPerMerkleBlock() uint64 {
+	return l.maxTxPerBlock() / 8
 }
 
 // MsgMerkleBlock implements the Message interface and represents a bitcoin
@@ -35,7 +42,7 @@ func (msg *MsgMerkleBlock) AddTxHash(hash *chainhash.Hash) error {
 	if uint64(len(msg.Hashes))+1 > maxTxPerBlock() {
 		str := fmt.Sprintf("too many tx hashes for message [max %v]",
 			maxTxPerBlock())
-		return messageError("MsgMerkleBlock.AddTxHash", str)
+		return messageError("MsgMerkleBlock.AddTxHash", ErrTooManyItems, str)
 	}
 
 	msg.Hashes = append(msg.Hashes, hash)
@@ -49,54 +56,57 @@ func (msg *MsgMerkleBlock) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding
 	if pver < BIP0037Version {
 		str := fmt.Sprintf("merkleblock message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgMerkleBlock.Bsvdecode", str)
+		return messageError("MsgMerkleBlock.Bsvdecode", ErrProtocolVersion, str)
 	}
 
 	err := readBlockHeader(r, pver, &msg.Header)
 	if err != nil {
-		return err
+		return traceField(r, err, "Header")
 	}
 
-	err = readElement(r, &msg.Transactions)
+	err = readUint32(r, &msg.Transactions)
 	if err != nil {
-		return err
+		return traceField(r, err, "Transactions")
 	}
 
 	// Read num block locator hashes and limit to max.
 	count, err := ReadVarInt(r, pver)
 	if err != nil {
-		return err
+		return traceField(r, err, "Hashes")
 	}
 
-	if count > maxTxPerBlock() {
+	if count > limitsOf(r).maxTxPerBlock() {
 		str := fmt.Sprintf("too many transaction hashes for message "+
-			"[count %v, max %v]", count, maxTxPerBlock())
-		return messageError("MsgMerkleBlock.Bsvdecode", str)
+			"[count %v, max %v]", count, limitsOf(r).maxTxPerBlock())
+		return traceField(r, messageError("MsgMerkleBlock.Bsvdecode", ErrTooManyItems, str), "Hashes")
 	}
 
 	// Create a contiguous slice of hashes to deserialize into to
-	// reduce the number of allocations.
-	hashes := make([]chainhash.Hash, count)
-	msg.Hashes = make([]*chainhash.Hash, 0, count)
+	// reduce the number of allocations, reusing those kept by Reset.
+	hashes := recycleList(msg.Hashes, count, recyclingOf(r))
+	msg.Hashes = hashes[:0]
 
 	for i := uint64(0); i < count; i++ {
-		hash := &hashes[i]
+		hash := hashes[i]
 
-		err = readElement(r, hash)
+		err = readHash(r, hash)
 		if err != nil {
-			return err
+			return traceItem(r, err, "Hashes", i)
 		}
 
 		err = msg.AddTxHash(hash)
 		if err != nil {
-			return err
+			return traceItem(r, err, "Hashes", i)
 		}
 	}
 
-	msg.Flags, err = ReadVarBytes(r, pver, maxFlagsPerMerkleBlock(),
+	msg.Flags, err = ReadVarBytes(r, pver, limitsOf(r).maxFlagsPerMerkleBlock(),
 		"merkle block flags size")
+	if err != nil {
+		return traceField(r, err, "Flags")
+	}
 
-	return err
+	return nil
 }
 
 // BsvEncode encodes the receiver to w using the bitcoin protocol encoding.
@@ -105,7 +115,7 @@ func (msg *MsgMerkleBlock) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding
 	if pver < BIP0037Version {
 		str := fmt.Sprintf("merkleblock message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgMerkleBlock.BsvEncode", str)
+		return messageError("MsgMerkleBlock.BsvEncode", ErrProtocolVersion, str)
 	}
 
 	// Read num transaction hashes and limit to max.
@@ -113,7 +123,7 @@ func (msg *MsgMerkleBlock) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding
 	if numHashes > int(maxTxPerBlock()) {
 		str := fmt.Sprintf("too many transaction hashes for message "+
 			"[count %v, max %v]", numHashes, maxTxPerBlock())
-		return messageError("MsgMerkleBlock.Bsvdecode", str)
+		return messageError("MsgMerkleBlock.Bsvdecode", ErrTooManyItems, str)
 	}
 
 	numFlagBytes := len(msg.Flags)
@@ -121,7 +131,7 @@ func (msg *MsgMerkleBlock) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding
 	if numFlagBytes > int(maxFlagsPerMerkleBlock()) {
 		str := fmt.Sprintf("too many flag bytes for message [count %v, "+
 			"max %v]", numFlagBytes, maxFlagsPerMerkleBlock())
-		return messageError("MsgMerkleBlock.Bsvdecode", str)
+		return messageError("MsgMerkleBlock.Bsvdecode", ErrTooManyItems, str)
 	}
 
 	err := writeBlockHeader(w, pver, &msg.Header)
@@ -129,7 +139,7 @@ func (msg *MsgMerkleBlock) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding
 		return err
 	}
 
-	err = writeElement(w, msg.Transactions)
+	err = writeUint32(w, msg.Transactions)
 	if err != nil {
 		return err
 	}
@@ -140,7 +150,7 @@ func (msg *MsgMerkleBlock) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding
 	}
 
 	for _, hash := range msg.Hashes {
-		err = writeElement(w, hash)
+		err = writeHash(w, hash)
 		if err != nil {
 			return err
 		}
@@ -149,6 +159,90 @@ func (msg *MsgMerkleBlock) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding
 	return WriteVarBytes(w, pver, msg.Flags)
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgMerkleBlock) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgMerkleBlock) PayloadSize(_ uint32) int {
+	// Header + Transactions 4 bytes + Hashes + Flags.
+	return blockHeaderLen + 4 + VarIntSerializeSize(uint64(len(msg.Hashes))) +
+		len(msg.Hashes)*chainhash.HashSize +
+		VarIntSerializeSize(uint64(len(msg.Flags))) + len(msg.Flags)
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgMerkleBlock) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgMerkleBlock) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgMerkleBlock) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgMerkleBlock) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgMerkleBlockJSON is the JSON representation of a merkleblock message.
+type msgMerkleBlockJSON struct {
+	blockHeaderJSON
+
+	Transactions uint32     `json:"totaltransactions"`
+	Hashes       []jsonHash `json:"hashes"`
+	Flags        jsonHex    `json:"flags"`
+}
+
+// MarshalJSON returns the JSON representation of the message, which holds the
+// fields of the header in the format of BlockHeader.MarshalJSON followed by
+// the partial merkle tree.  This is part of the json.Marshaler interface
+// implementation.
+func (msg *MsgMerkleBlock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgMerkleBlockJSON{
+		blockHeaderJSON: msg.Header.toJSON(),
+		Transactions:    msg.Transactions,
+		Hashes:          toJSONHashes(msg.Hashes),
+		Flags:           msg.Flags,
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgMerkleBlock) UnmarshalJSON(data []byte) error {
+	var j msgMerkleBlockJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgMerkleBlock{
+		Transactions: j.Transactions,
+		Hashes:       fromJSONHashes(j.Hashes),
+		Flags:        j.Flags,
+	}
+
+	return msg.Header.fromJSON(&j.blockHeaderJSON)
+}
+
 // Command returns the protocol command string for the message.  This is part
 // of the Message interface implementation.
 func (msg *MsgMerkleBlock) Command() string {
@@ -161,6 +255,13 @@ func (msg *MsgMerkleBlock) MaxPayloadLength(_ uint32) uint64 {
 	return MaxBlockPayload()
 }
 
+// Reset resets the message to its zero state while keeping the capacity of
+// Hashes, along with the transaction hashes it points to, for reuse by the
+// next decode.  This is part of the Resetter interface implementation.
+func (msg *MsgMerkleBlock) Reset() {
+	*msg = MsgMerkleBlock{Hashes: msg.Hashes[:0]}
+}
+
 // NewMsgMerkleBlock returns a new bitcoin merkleblock message that conforms to
 // the Message interface.  See MsgMerkleBlock for details.
 func NewMsgMerkleBlock(bh *BlockHeader) *MsgMerkleBlock {
@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"io"
 )
 
@@ -32,7 +33,7 @@ func (msg *MsgPing) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error
 	// NOTE: > is not a mistake here.  The BIP0031 was defined as AFTER
 	// the version unlike most others.
 	if pver > BIP0031Version {
-		err := readElement(r, &msg.Nonce)
+		err := readUint64(r, &msg.Nonce)
 		if err != nil {
 			return err
 		}
@@ -48,7 +49,7 @@ func (msg *MsgPing) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error
 	// NOTE: > is not a mistake here.  The BIP0031 was defined as AFTER
 	// the version unlike most others.
 	if pver > BIP0031Version {
-		err := writeElement(w, msg.Nonce)
+		err := writeUint64(w, msg.Nonce)
 		if err != nil {
 			return err
 		}
@@ -57,6 +58,78 @@ func (msg *MsgPing) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgPing) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgPing) PayloadSize(pver uint32) int {
+	// There was no nonce for BIP0031Version and earlier.
+	if pver > BIP0031Version {
+		// Nonce 8 bytes.
+		return 8
+	}
+
+	return 0
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgPing) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgPing) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgPing) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgPing) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgPingJSON is the JSON representation of a ping message.
+type msgPingJSON struct {
+	Nonce uint64 `json:"nonce"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgPing) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgPingJSON(*msg))
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgPing) UnmarshalJSON(data []byte) error {
+	var j msgPingJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgPing(j)
+
+	return nil
+}
+
 // Command returns the protocol command string for the message.  This is part
 // of the Message interface implementation.
 func (msg *MsgPing) Command() string {
@@ -78,6 +151,12 @@ func (msg *MsgPing) MaxPayloadLength(pver uint32) uint64 {
 	return plen
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgPing) Reset() {
+	*msg = MsgPing{}
+}
+
 // NewMsgPing returns a new bitcoin ping message that conforms to the Message
 // interface.  See MsgPing for details.
 func NewMsgPing(nonce uint64) *MsgPing {
@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 )
@@ -28,10 +29,10 @@ func (msg *MsgPong) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error
 	if pver <= BIP0031Version {
 		str := fmt.Sprintf("pong message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgPong.Bsvdecode", str)
+		return messageError("MsgPong.Bsvdecode", ErrProtocolVersion, str)
 	}
 
-	return readElement(r, &msg.Nonce)
+	return readUint64(r, &msg.Nonce)
 }
 
 // BsvEncode encodes the receiver to w using the bitcoin protocol encoding.
@@ -42,10 +43,77 @@ func (msg *MsgPong) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error
 	if pver <= BIP0031Version {
 		str := fmt.Sprintf("pong message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgPong.BsvEncode", str)
+		return messageError("MsgPong.BsvEncode", ErrProtocolVersion, str)
 	}
 
-	return writeElement(w, msg.Nonce)
+	return writeUint64(w, msg.Nonce)
+}
+
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgPong) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgPong) PayloadSize(_ uint32) int {
+	// Nonce 8 bytes.
+	return 8
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgPong) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgPong) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgPong) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgPong) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgPongJSON is the JSON representation of a pong message.
+type msgPongJSON struct {
+	Nonce uint64 `json:"nonce"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgPong) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgPongJSON(*msg))
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgPong) UnmarshalJSON(data []byte) error {
+	var j msgPongJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgPong(j)
+
+	return nil
 }
 
 // Command returns the protocol command string for the message.  This is part
@@ -69,6 +137,12 @@ func (msg *MsgPong) MaxPayloadLength(pver uint32) uint64 {
 	return plen
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgPong) Reset() {
+	*msg = MsgPong{}
+}
+
 // NewMsgPong returns a new bitcoin pong message that conforms to the Message
 // interface.  See MsgPong for details.
 func NewMsgPong(nonce uint64) *MsgPong {
@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
@@ -38,6 +39,12 @@ type MsgProtoconf struct {
 	StreamPolicies       []string
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgProtoconf) Reset() {
+	*msg = MsgProtoconf{}
+}
+
 // NewMsgProtoconf returns a new bitcoin protoconf message that conforms to
 // the Message interface.  See MsgFeeFilter for details.
 func NewMsgProtoconf(maxRecvPayloadLength uint32, allowBlockPriority bool) *MsgProtoconf {
@@ -66,7 +73,7 @@ func NewMsgProtoconf(maxRecvPayloadLength uint32, allowBlockPriority bool) *MsgP
 func (msg *MsgProtoconf) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
 	if pver < ProtoconfVersion {
 		str := fmt.Sprintf("protoconf message invalid for protocol version %d", pver)
-		return messageError("MsgProtoconf.Bsvdecode", str)
+		return messageError("MsgProtoconf.Bsvdecode", ErrProtocolVersion, str)
 	}
 
 	var vi bt.VarInt
@@ -80,7 +87,7 @@ func (msg *MsgProtoconf) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 
 	if msg.NumberOfFields > 0 {
 		// Read the MaxRecvPayloadLength
-		msg.MaxRecvPayloadLength, err = binarySerializer.Uint32(r, littleEndian)
+		err = readUint32(r, &msg.MaxRecvPayloadLength)
 		if err != nil {
 			return err
 		}
@@ -96,10 +103,10 @@ func (msg *MsgProtoconf) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 		// allocating: an unbounded make([]byte, vi) panics ("makeslice: len
 		// out of range") or exhausts memory on a crafted length. Mirrors the
 		// cap ReadVarString applies.
-		if uint64(vi) > maxMessagePayload() {
+		if uint64(vi) > limitsOf(r).maxMessagePayload() {
 			str := fmt.Sprintf("stream policies length too long "+
-				"[count %d, max %d]", uint64(vi), maxMessagePayload())
-			return messageError("MsgProtoconf.Bsvdecode", str)
+				"[count %d, max %d]", uint64(vi), limitsOf(r).maxMessagePayload())
+			return messageError("MsgProtoconf.Bsvdecode", ErrElementTooLarge, str)
 		}
 
 		b := make([]byte, vi)
@@ -120,7 +127,7 @@ func (msg *MsgProtoconf) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 func (msg *MsgProtoconf) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error {
 	if pver < ProtoconfVersion {
 		str := fmt.Sprintf("protoconf message invalid for protocol version %d", pver)
-		return messageError("MsgProtoconf.BsvEncode", str)
+		return messageError("MsgProtoconf.BsvEncode", ErrProtocolVersion, str)
 	}
 
 	// First, write the number of fields as a varint. At the moment, this is always 2 and will write 1 byte (0x02)
@@ -128,7 +135,7 @@ func (msg *MsgProtoconf) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 		return err
 	}
 
-	if err := binarySerializer.PutUint32(w, littleEndian, msg.MaxRecvPayloadLength); err != nil {
+	if err := writeUint32(w, msg.MaxRecvPayloadLength); err != nil {
 		return err
 	}
 
@@ -145,6 +152,86 @@ func (msg *MsgProtoconf) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgProtoconf) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgProtoconf) PayloadSize(_ uint32) int {
+	// NumberOfFields + MaxRecvPayloadLength 4 bytes + StreamPolicies.
+	n := len(msg.StreamPolicies) - 1
+	for _, policy := range msg.StreamPolicies {
+		n += len(policy)
+	}
+
+	n = max(n, 0)
+
+	return VarIntSerializeSize(msg.NumberOfFields) + 4 + VarIntSerializeSize(uint64(n)) + n
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgProtoconf) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgProtoconf) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgProtoconf) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgProtoconf) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgProtoconfJSON is the JSON representation of a protoconf message.
+type msgProtoconfJSON struct {
+	NumberOfFields       uint64   `json:"numberoffields"`
+	MaxRecvPayloadLength uint32   `json:"maxrecvpayloadlength"`
+	StreamPolicies       []string `json:"streampolicies"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgProtoconf) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgProtoconfJSON{
+		NumberOfFields:       msg.NumberOfFields,
+		MaxRecvPayloadLength: msg.MaxRecvPayloadLength,
+		StreamPolicies:       nonNil(msg.StreamPolicies),
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgProtoconf) UnmarshalJSON(data []byte) error {
+	var j msgProtoconfJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgProtoconf(j)
+
+	return nil
+}
+
 // Command returns the protocol command string for the message.  This is part
 // of the Message interface implementation.
 func (msg *MsgProtoconf) Command() string {
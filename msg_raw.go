@@ -0,0 +1,155 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// MsgRaw implements the Message interface and represents a message with a
+// command this package does not recognize.  It is only returned by codecs
+// with unknown command passthrough enabled, see Codec.SetUnknownPassthrough,
+// and allows transparent proxies, capture tools and relays to carry messages
+// they do not understand.
+//
+// Writing a MsgRaw reproduces the message it was read from byte-for-byte when
+// the header form matches, that is when both were written with or without the
+// extended header, and the checksum verified.  A payload which failed its
+// checksum is written with the correct checksum.
+type MsgRaw struct {
+	// Cmd is the command of the message.
+	Cmd string
+
+	// Payload is the undecoded payload of the message.
+	Payload []byte
+
+	// ChecksumVerified reports whether the checksum of the payload
+	// matched the header when the message was read.  It is always false for
+	// messages read with the extended header since they carry no checksum.
+	ChecksumVerified bool
+}
+
+// Bsvdecode decodes r using the bitcoin protocol encoding into the receiver.
+// The whole of r is read as the payload.  This is part of the Message interface
+// implementation.
+func (msg *MsgRaw) Bsvdecode(r io.Reader, _ uint32, _ MessageEncoding) error {
+	payload, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	msg.Payload = payload
+
+	return nil
+}
+
+// BsvEncode encodes the receiver to w using the bitcoin protocol encoding.
+// This is part of the Message interface implementation.
+func (msg *MsgRaw) BsvEncode(w io.Writer, _ uint32, _ MessageEncoding) error {
+	_, err := w.Write(msg.Payload)
+	return err
+}
+
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgRaw) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgRaw) PayloadSize(_ uint32) int {
+	return len(msg.Payload)
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgRaw) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgRaw) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgRaw) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgRaw) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgRawJSON is the JSON representation of a raw message.
+type msgRawJSON struct {
+	Cmd     string  `json:"command"`
+	Payload jsonHex `json:"payload"`
+}
+
+// MarshalJSON returns the JSON representation of the message, which holds its
+// command and its payload as hex.  Whether the checksum was verified is not
+// represented.  This is part of the json.Marshaler interface implementation.
+func (msg *MsgRaw) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgRawJSON{Cmd: msg.Cmd, Payload: msg.Payload})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgRaw) UnmarshalJSON(data []byte) error {
+	var j msgRawJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgRaw{Cmd: j.Cmd, Payload: j.Payload}
+
+	return nil
+}
+
+// Command returns the protocol command string for the message.  This is part
+// of the Message interface implementation.
+func (msg *MsgRaw) Command() string {
+	return msg.Cmd
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the
+// receiver.  This is part of the Message interface implementation.
+func (msg *MsgRaw) MaxPayloadLength(_ uint32) uint64 {
+	return maxMessagePayload()
+}
+
+// SerializeSize returns the number of bytes it would take to serialize the
+// message.
+func (msg *MsgRaw) SerializeSize() int {
+	return len(msg.Payload)
+}
+
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgRaw) Reset() {
+	*msg = MsgRaw{}
+}
+
+// NewMsgRaw returns a new raw message with the provided command and payload
+// that conforms to the Message interface.  See MsgRaw for details.
+func NewMsgRaw(command string, payload []byte) *MsgRaw {
+	return &MsgRaw{
+		Cmd:     command,
+		Payload: payload,
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMsgRaw tests the MsgRaw API.
+func TestMsgRaw(t *testing.T) {
+	msg := NewMsgRaw("xfuture", []byte{0x01, 0x02, 0x03})
+	assert.Equal(t, "xfuture", msg.Command())
+	assert.Equal(t, 3, msg.SerializeSize())
+	assert.Equal(t, maxMessagePayload(), msg.MaxPayloadLength(ProtocolVersion))
+
+	var buf bytes.Buffer
+	require.NoError(t, msg.BsvEncode(&buf, ProtocolVersion, BaseEncoding))
+	assert.Equal(t, msg.Payload, buf.Bytes())
+
+	var decoded MsgRaw
+	require.NoError(t, decoded.Bsvdecode(&buf, ProtocolVersion, BaseEncoding))
+	assert.Equal(t, msg.Payload, decoded.Payload)
+}
+
+// TestMsgRawPassthrough ensures messages with unknown commands are returned as
+// a MsgRaw by every read path when passthrough is enabled, and re-encode
+// byte-for-byte.
+func TestMsgRawPassthrough(t *testing.T) {
+	writer := NewCodec(MainNet, ProtocolVersion)
+	frame := encodeFrame(t, writer, NewMsgRaw("xfuture", []byte("some future payload")))
+
+	badChecksum := bytes.Clone(frame)
+	badChecksum[MessageHeaderSize] ^= 0xff
+
+	extWriter := NewCodec(MainNet, ProtocolVersion)
+	extWriter.SetExtendedMessages(true)
+	extFrame := encodeFrame(t, extWriter, NewMsgRaw("xfuture", []byte("some future payload")))
+
+	codec := NewCodec(MainNet, ProtocolVersion)
+	assert.False(t, codec.UnknownPassthrough())
+
+	// Unknown commands are rejected by default.
+	var msgErr *MessageError
+
+	_, _, _, err := codec.Read(bytes.NewReader(frame))
+	require.ErrorAs(t, err, &msgErr)
+
+	codec.SetUnknownPassthrough(true)
+	assert.True(t, codec.UnknownPassthrough())
+
+	reads := map[string]func(b []byte) (Message, error){
+		"Read": func(b []byte) (Message, error) {
+			_, msg, _, err := codec.Read(bytes.NewReader(b))
+			return msg, err
+		},
+		"ReadStreaming": func(b []byte) (Message, error) {
+			_, msg, err := codec.ReadStreaming(bytes.NewReader(b))
+			return msg, err
+		},
+		"DecodeBytes": func(b []byte) (Message, error) {
+			_, msg, _, err := codec.DecodeBytes(b)
+			return msg, err
+		},
+	}
+
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			msg, err := read(frame)
+			require.NoError(t, err)
+			require.IsType(t, &MsgRaw{}, msg)
+
+			raw := msg.(*MsgRaw)
+			assert.Equal(t, "xfuture", raw.Cmd)
+			assert.Equal(t, []byte("some future payload"), raw.Payload)
+			assert.True(t, raw.ChecksumVerified)
+			assert.Equal(t, frame, encodeFrame(t, writer, raw))
+
+			// A checksum mismatch is reported rather than rejected.
+			msg, err = read(badChecksum)
+			require.NoError(t, err)
+			assert.False(t, msg.(*MsgRaw).ChecksumVerified)
+
+			// Extended messages carry no checksum.
+			msg, err = read(extFrame)
+			require.NoError(t, err)
+			assert.False(t, msg.(*MsgRaw).ChecksumVerified)
+			assert.Equal(t, extFrame, encodeFrame(t, extWriter, msg))
+
+			// Recognized commands are unaffected.
+			msg, err = read(encodeFrame(t, writer, NewMsgPing(9)))
+			require.NoError(t, err)
+			assert.Equal(t, NewMsgPing(9), msg)
+		})
+	}
+
+	// A checksum mismatch on a recognized command is still an error.
+	pingFrame := encodeFrame(t, writer, NewMsgPing(9))
+	pingFrame[MessageHeaderSize] ^= 0xff
+
+	_, _, _, err = codec.Read(bytes.NewReader(pingFrame))
+	require.ErrorAs(t, err, &msgErr)
+}
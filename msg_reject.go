@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 
@@ -77,7 +78,7 @@ func (msg *MsgReject) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) err
 	if pver < RejectVersion {
 		str := fmt.Sprintf("reject message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgReject.Bsvdecode", str)
+		return messageError("MsgReject.Bsvdecode", ErrProtocolVersion, str)
 	}
 
 	// Command that was rejected.
@@ -89,7 +90,7 @@ func (msg *MsgReject) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) err
 	msg.Cmd = cmd
 
 	// Code indicating why the command was rejected.
-	err = readElement(r, &msg.Code)
+	err = readUint8(r, &msg.Code)
 	if err != nil {
 		return err
 	}
@@ -106,7 +107,7 @@ func (msg *MsgReject) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) err
 	// CmdBlock and CmdTx messages have an additional hash field that
 	// identifies the specific block or transaction.
 	if msg.Cmd == CmdBlock || msg.Cmd == CmdTx || msg.Cmd == CmdExtendedTx {
-		err := readElement(r, &msg.Hash)
+		err := readHash(r, &msg.Hash)
 		if err != nil {
 			return err
 		}
@@ -121,7 +122,7 @@ func (msg *MsgReject) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) err
 	if pver < RejectVersion {
 		str := fmt.Sprintf("reject message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgReject.BsvEncode", str)
+		return messageError("MsgReject.BsvEncode", ErrProtocolVersion, str)
 	}
 
 	// Command that was rejected.
@@ -131,7 +132,7 @@ func (msg *MsgReject) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) err
 	}
 
 	// Code indicating why the command was rejected.
-	err = writeElement(w, msg.Code)
+	err = writeUint8(w, msg.Code)
 	if err != nil {
 		return err
 	}
@@ -146,7 +147,7 @@ func (msg *MsgReject) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) err
 	// CmdBlock and CmdTx messages have an additional hash field that
 	// identifies the specific block or transaction.
 	if msg.Cmd == CmdBlock || msg.Cmd == CmdTx || msg.Cmd == CmdExtendedTx {
-		err := writeElement(w, &msg.Hash)
+		err := writeHash(w, &msg.Hash)
 		if err != nil {
 			return err
 		}
@@ -155,6 +156,105 @@ func (msg *MsgReject) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) err
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgReject) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgReject) PayloadSize(_ uint32) int {
+	// Cmd + Code 1 byte + Reason.
+	n := VarIntSerializeSize(uint64(len(msg.Cmd))) + len(msg.Cmd) + 1 +
+		VarIntSerializeSize(uint64(len(msg.Reason))) + len(msg.Reason)
+
+	// CmdBlock and CmdTx messages have an additional hash field.
+	if msg.Cmd == CmdBlock || msg.Cmd == CmdTx || msg.Cmd == CmdExtendedTx {
+		n += chainhash.HashSize
+	}
+
+	return n
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgReject) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgReject) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgReject) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgReject) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgRejectJSON is the JSON representation of a reject message.
+type msgRejectJSON struct {
+	Cmd    string         `json:"command"`
+	Code   jsonRejectCode `json:"code"`
+	Reason string         `json:"reason"`
+	Hash   *jsonHash      `json:"hash,omitempty"`
+}
+
+// MarshalJSON returns the JSON representation of the message, which holds the
+// reject code by name, such as "REJECT_DUPLICATE", and the hash only for the
+// commands it is encoded for.  This is part of the json.Marshaler interface
+// implementation.
+func (msg *MsgReject) MarshalJSON() ([]byte, error) {
+	j := msgRejectJSON{
+		Cmd:    msg.Cmd,
+		Code:   jsonRejectCode(msg.Code),
+		Reason: msg.Reason,
+	}
+
+	if msg.Cmd == CmdBlock || msg.Cmd == CmdTx || msg.Cmd == CmdExtendedTx {
+		hash := jsonHash(msg.Hash)
+		j.Hash = &hash
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgReject) UnmarshalJSON(data []byte) error {
+	var j msgRejectJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgReject{
+		Cmd:    j.Cmd,
+		Code:   RejectCode(j.Code),
+		Reason: j.Reason,
+	}
+
+	if j.Hash != nil {
+		msg.Hash = chainhash.Hash(*j.Hash)
+	}
+
+	return nil
+}
+
 // Command returns the protocol command string for the message.  This is part
 // of the Message interface implementation.
 func (msg *MsgReject) Command() string {
@@ -177,6 +277,12 @@ func (msg *MsgReject) MaxPayloadLength(pver uint32) uint64 {
 	return plen
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgReject) Reset() {
+	*msg = MsgReject{}
+}
+
 // NewMsgReject returns a new bitcoin reject a message that conforms to the
 // Message interface.  See MsgReject for details.
 func NewMsgReject(command string, code RejectCode, reason string) *MsgReject {
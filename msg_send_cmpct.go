@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"io"
 )
 
@@ -19,12 +20,12 @@ type MsgSendcmpct struct {
 // This is part of the Message interface implementation.
 func (msg *MsgSendcmpct) Bsvdecode(r io.Reader, _ uint32, _ MessageEncoding) error {
 	// Read stop hash
-	err := readElement(r, &msg.SendCmpct)
+	err := readBool(r, &msg.SendCmpct)
 	if err != nil {
 		return err
 	}
 
-	err = readElement(r, &msg.Version)
+	err = readUint64(r, &msg.Version)
 	if err != nil {
 		return err
 	}
@@ -35,7 +36,79 @@ func (msg *MsgSendcmpct) Bsvdecode(r io.Reader, _ uint32, _ MessageEncoding) err
 // BsvEncode encodes the receiver to w using the bitcoin protocol encoding.
 // This is part of the Message interface implementation.
 func (msg *MsgSendcmpct) BsvEncode(w io.Writer, _ uint32, _ MessageEncoding) error {
-	return writeElements(w, msg.SendCmpct, msg.Version)
+	if err := writeBool(w, msg.SendCmpct); err != nil {
+		return err
+	}
+
+	return writeUint64(w, msg.Version)
+}
+
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgSendcmpct) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgSendcmpct) PayloadSize(_ uint32) int {
+	// SendCmpct 1 byte + Version 8 bytes.
+	return 9
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgSendcmpct) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgSendcmpct) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgSendcmpct) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgSendcmpct) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgSendcmpctJSON is the JSON representation of a sendcmpct message.
+type msgSendcmpctJSON struct {
+	SendCmpct bool   `json:"sendcmpct"`
+	Version   uint64 `json:"version"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgSendcmpct) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgSendcmpctJSON(*msg))
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgSendcmpct) UnmarshalJSON(data []byte) error {
+	var j msgSendcmpctJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgSendcmpct(j)
+
+	return nil
 }
 
 // Command returns the protocol command string for the message.  This is part
@@ -50,6 +123,12 @@ func (msg *MsgSendcmpct) MaxPayloadLength(_ uint32) uint64 {
 	return 1 + 8
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgSendcmpct) Reset() {
+	*msg = MsgSendcmpct{}
+}
+
 // NewMsgSendcmpct returns a new compact blocks negotiation message that conforms to
 // the Message interface.  See MsgSendcmpct for details.
 func NewMsgSendcmpct(sendcmpct bool) *MsgSendcmpct {
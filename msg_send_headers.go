@@ -5,6 +5,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 )
@@ -23,7 +24,7 @@ func (msg *MsgSendHeaders) Bsvdecode(_ io.Reader, pver uint32, _ MessageEncoding
 	if pver < SendHeadersVersion {
 		str := fmt.Sprintf("sendheaders message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgSendHeaders.Bsvdecode", str)
+		return messageError("MsgSendHeaders.Bsvdecode", ErrProtocolVersion, str)
 	}
 
 	return nil
@@ -35,12 +36,68 @@ func (msg *MsgSendHeaders) BsvEncode(_ io.Writer, pver uint32, _ MessageEncoding
 	if pver < SendHeadersVersion {
 		str := fmt.Sprintf("sendheaders message invalid for protocol "+
 			"version %d", pver)
-		return messageError("MsgSendHeaders.BsvEncode", str)
+		return messageError("MsgSendHeaders.BsvEncode", ErrProtocolVersion, str)
 	}
 
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgSendHeaders) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgSendHeaders) PayloadSize(_ uint32) int {
+	return 0
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgSendHeaders) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgSendHeaders) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgSendHeaders) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgSendHeaders) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// MarshalJSON returns the JSON representation of the message, which is an
+// empty object since the message has no payload.  This is part of the
+// json.Marshaler interface implementation.
+func (msg *MsgSendHeaders) MarshalJSON() ([]byte, error) {
+	return []byte("{}"), nil
+}
+
+// UnmarshalJSON checks that data is the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgSendHeaders) UnmarshalJSON(data []byte) error {
+	var j struct{}
+	return json.Unmarshal(data, &j)
+}
+
 // Command returns the protocol command string for the message.  This is part
 // of the Message interface implementation.
 func (msg *MsgSendHeaders) Command() string {
@@ -53,6 +110,12 @@ func (msg *MsgSendHeaders) MaxPayloadLength(_ uint32) uint64 {
 	return 0
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgSendHeaders) Reset() {
+	*msg = MsgSendHeaders{}
+}
+
 // NewMsgSendHeaders returns a new bitcoin sendheaders message that conforms to
 // the Message interface.  See MsgSendHeaders for details.
 func NewMsgSendHeaders() *MsgSendHeaders {
@@ -1,6 +1,7 @@
 package wire
 
 import (
+	"encoding/json"
 	"io"
 )
 
@@ -23,7 +24,7 @@ func (msg *MsgStreamAck) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding)
 	}
 
 	var streamType uint8
-	if err = readElement(r, &streamType); err != nil {
+	if err = readUint8(r, &streamType); err != nil {
 		return err
 	}
 
@@ -39,13 +40,81 @@ func (msg *MsgStreamAck) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding)
 		return err
 	}
 
-	if err := writeElement(w, uint8(msg.StreamType)); err != nil {
+	if err := writeUint8(w, uint8(msg.StreamType)); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgStreamAck) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgStreamAck) PayloadSize(_ uint32) int {
+	// AssociationID + StreamType 1 byte.
+	return VarIntSerializeSize(uint64(len(msg.AssociationID))) + len(msg.AssociationID) + 1
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgStreamAck) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgStreamAck) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgStreamAck) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgStreamAck) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgStreamAckJSON is the JSON representation of a streamack message.
+type msgStreamAckJSON struct {
+	AssociationID jsonHex    `json:"associationid"`
+	StreamType    StreamType `json:"streamtype"`
+}
+
+// MarshalJSON returns the JSON representation of the message.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgStreamAck) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgStreamAckJSON{AssociationID: msg.AssociationID, StreamType: msg.StreamType})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgStreamAck) UnmarshalJSON(data []byte) error {
+	var j msgStreamAckJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgStreamAck{AssociationID: j.AssociationID, StreamType: j.StreamType}
+
+	return nil
+}
+
 // Command returns the protocol command string for the message.
 func (msg *MsgStreamAck) Command() string {
 	return CmdStreamAck
@@ -57,6 +126,12 @@ func (msg *MsgStreamAck) MaxPayloadLength(_ uint32) uint64 {
 	return MaxVarIntPayload + MaxAssociationIDLen + 1
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgStreamAck) Reset() {
+	*msg = MsgStreamAck{}
+}
+
 // NewMsgStreamAck returns a new streamack message.
 func NewMsgStreamAck(associationID []byte, streamType StreamType) *MsgStreamAck {
 	return &MsgStreamAck{
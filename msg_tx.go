@@ -6,6 +6,7 @@ package wire
 
 import (
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
@@ -53,6 +54,10 @@ const (
 	// backing array multiple times.
 	defaultTxInOutAlloc = 15
 
+	// outPointLen is the length of an encoded outpoint.
+	// Hash + Index 4 bytes.
+	outPointLen = chainhash.HashSize + 4
+
 	// minTxInPayload is the minimum payload size for a transaction input.
 	// PreviousOutPoint.Hash + PreviousOutPoint.Index 4 bytes + Varint for
 	// SignatureScript length 1 byte + Sequence 4 bytes.
@@ -90,13 +95,27 @@ const (
 // maxTxInPerMessage returns the maximum number of transaction inputs that
 // a transaction which fits into a message could possibly have.
 func maxTxInPerMessage() uint64 {
-	return (maxMessagePayload() / minTxInPayload) + 1
+	return decodeLimits{}.maxTxInPerMessage()
 }
 
 // maxTxOutPerMessage returns the maximum number of transaction outputs that
 // a transaction which fits into a message could possibly have.
 func maxTxOutPerMessage() uint64 {
-	return (maxMessagePayload() / MinTxOutPayload) + 1
+	return decodeLimits{}.maxTxOutPerMessage()
+}
+
+// maxTxInPerMessage returns the maximum number of transaction inputs that
+// a transaction which fits into a message within the limits could possibly
+// have.
+func (l decodeLimits) maxTxInPerMessage() uint64 {
+	return (l.maxMessagePayload() / minTxInPayload) + 1
+}
+
+// maxTxOutPerMessage returns the maximum number of transaction outputs that
+// a transaction which fits into a message within the limits could possibly
+// have.
+func (l decodeLimits) maxTxOutPerMessage() uint64 {
+	return (l.maxMessagePayload() / MinTxOutPayload) + 1
 }
 
 // scriptFreeList defines a free list of byte slices (up to the maximum number
@@ -189,6 +208,66 @@ func (o OutPoint) String() string {
 	return string(buf)
 }
 
+// MarshalBinary returns the bitcoin protocol encoding of the outpoint.  This
+// is part of the encoding.BinaryMarshaler interface implementation.
+func (o *OutPoint) MarshalBinary() ([]byte, error) {
+	aw := &appendWriter{b: make([]byte, 0, outPointLen)}
+	if err := writeOutPoint(aw, ProtocolVersion, 0, o); err != nil {
+		return nil, err
+	}
+
+	return aw.b, nil
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of an outpoint, into the receiver.  This is part of the
+// encoding.BinaryUnmarshaler interface implementation.
+func (o *OutPoint) UnmarshalBinary(data []byte) error {
+	return decodeExact(data, "outpoint", func(r io.Reader) error {
+		return readOutPoint(r, ProtocolVersion, 0, o)
+	})
+}
+
+// WriteTo writes the bitcoin protocol encoding of the outpoint to w and
+// returns the number of bytes written.  This is part of the io.WriterTo
+// interface implementation.
+func (o *OutPoint) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(o, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding of an
+// outpoint read from r until EOF and returns the number of bytes read.  This is
+// part of the io.ReaderFrom interface implementation.
+func (o *OutPoint) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(o, r, outPointLen)
+}
+
+// outPointJSON is the JSON representation of an outpoint.
+type outPointJSON struct {
+	TxID jsonHash `json:"txid"`
+	Vout uint32   `json:"vout"`
+}
+
+// MarshalJSON returns the JSON representation of the outpoint, which holds
+// its transaction hash as txid and its index as vout.  This is part of the
+// json.Marshaler interface implementation.
+func (o OutPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(outPointJSON{TxID: jsonHash(o.Hash), Vout: o.Index})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (o *OutPoint) UnmarshalJSON(data []byte) error {
+	var j outPointJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*o = OutPoint{Hash: chainhash.Hash(j.TxID), Index: j.Vout}
+
+	return nil
+}
+
 // TxIn defines a bitcoin transaction input.
 type TxIn struct {
 	PreviousOutPoint OutPoint
@@ -218,6 +297,97 @@ func NewTxIn(prevOut *OutPoint, signatureScript []byte) *TxIn {
 	}
 }
 
+// txInJSON is the JSON representation of a transaction input.  It follows the
+// vin entries of the decoderawtransaction RPC of bitcoind, so an input spending
+// the null outpoint, as the input of a coinbase transaction does, is
+// represented by its coinbase script instead of its outpoint and scriptSig.
+type txInJSON struct {
+	Coinbase  *jsonHex    `json:"coinbase,omitempty"`
+	TxID      *jsonHash   `json:"txid,omitempty"`
+	Vout      *uint32     `json:"vout,omitempty"`
+	ScriptSig *jsonScript `json:"scriptSig,omitempty"`
+	Sequence  uint32      `json:"sequence"`
+}
+
+// newTxInJSON returns the JSON representation of an input spending prevOut.
+func newTxInJSON(prevOut *OutPoint, signatureScript []byte, sequence uint32) txInJSON {
+	j := txInJSON{Sequence: sequence}
+
+	if prevOut.Index == MaxPrevOutIndex && prevOut.Hash == (chainhash.Hash{}) {
+		coinbase := jsonHex(signatureScript)
+		j.Coinbase = &coinbase
+
+		return j
+	}
+
+	txID := jsonHash(prevOut.Hash)
+	vout := prevOut.Index
+
+	j.TxID = &txID
+	j.Vout = &vout
+	j.ScriptSig = &jsonScript{Hex: signatureScript}
+
+	return j
+}
+
+// outPoint returns the outpoint and signature script of the input.
+func (j *txInJSON) outPoint() (OutPoint, []byte, error) {
+	if j.Coinbase != nil {
+		return OutPoint{Index: MaxPrevOutIndex}, *j.Coinbase, nil
+	}
+
+	if j.TxID == nil || j.Vout == nil {
+		return OutPoint{}, nil, jsonError("input has neither a coinbase " +
+			"nor a txid and vout")
+	}
+
+	var signatureScript []byte
+	if j.ScriptSig != nil {
+		signatureScript = j.ScriptSig.Hex
+	}
+
+	return OutPoint{Hash: chainhash.Hash(*j.TxID), Index: *j.Vout}, signatureScript, nil
+}
+
+// toJSON returns the JSON representation of the input.
+func (t *TxIn) toJSON() txInJSON {
+	return newTxInJSON(&t.PreviousOutPoint, t.SignatureScript, t.Sequence)
+}
+
+// fromJSON sets the input from its JSON representation.
+func (t *TxIn) fromJSON(j *txInJSON) error {
+	prevOut, signatureScript, err := j.outPoint()
+	if err != nil {
+		return err
+	}
+
+	*t = TxIn{
+		PreviousOutPoint: prevOut,
+		SignatureScript:  signatureScript,
+		Sequence:         j.Sequence,
+	}
+
+	return nil
+}
+
+// MarshalJSON returns the JSON representation of the input in the format of
+// the vin entries of the decoderawtransaction RPC of bitcoind.  This is part of
+// the json.Marshaler interface implementation.
+func (t TxIn) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.toJSON())
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (t *TxIn) UnmarshalJSON(data []byte) error {
+	var j txInJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	return t.fromJSON(&j)
+}
+
 // TxOut defines a bitcoin transaction output.
 type TxOut struct {
 	Value    int64
@@ -242,6 +412,68 @@ func NewTxOut(value int64, pkScript []byte) *TxOut {
 	}
 }
 
+// txOutJSON is the JSON representation of a transaction output.  It follows
+// the vout entries of the decoderawtransaction RPC of bitcoind; the index n is
+// only present in the representation of a transaction.
+type txOutJSON struct {
+	Value        jsonAmount `json:"value"`
+	N            *int       `json:"n,omitempty"`
+	ScriptPubKey jsonScript `json:"scriptPubKey"`
+}
+
+// MarshalJSON returns the JSON representation of the output in the format of
+// the vout entries of the decoderawtransaction RPC of bitcoind.  This is part
+// of the json.Marshaler interface implementation.
+func (t TxOut) MarshalJSON() ([]byte, error) {
+	return json.Marshal(txOutJSON{
+		Value:        jsonAmount(t.Value),
+		ScriptPubKey: jsonScript{Hex: t.PkScript},
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (t *TxOut) UnmarshalJSON(data []byte) error {
+	var j txOutJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*t = TxOut{Value: int64(j.Value), PkScript: j.ScriptPubKey.Hex}
+
+	return nil
+}
+
+// toTxOutsJSON returns the JSON representation of the outputs of a
+// transaction, numbered by their index.
+func toTxOutsJSON(outs []*TxOut) []txOutJSON {
+	list := make([]txOutJSON, len(outs))
+	for i, to := range outs {
+		n := i
+		list[i] = txOutJSON{
+			Value:        jsonAmount(to.Value),
+			N:            &n,
+			ScriptPubKey: jsonScript{Hex: to.PkScript},
+		}
+	}
+
+	return list
+}
+
+// fromTxOutsJSON returns the outputs of a transaction from their JSON
+// representation.  The index of each output is implied by its position.
+func fromTxOutsJSON(list []txOutJSON) []*TxOut {
+	backing := make([]TxOut, len(list))
+	outs := make([]*TxOut, len(list))
+
+	for i := range list {
+		backing[i] = TxOut{Value: int64(list[i].Value), PkScript: list[i].ScriptPubKey.Hex}
+		outs[i] = &backing[i]
+	}
+
+	return outs
+}
+
 // MsgTx implements the Message interface and represents a bitcoin tx message.
 // It is used to deliver transaction information in response to a getdata
 // message (MsgGetData) for a given transaction.
@@ -348,124 +580,24 @@ func (msg *MsgTx) Copy() *MsgTx {
 // This is part of the Message interface implementation.
 // See Deserialize for decoding transactions stored to disk, such as in a
 // database, as opposed to decoding transactions from the wire.
-func (msg *MsgTx) Bsvdecode(r io.Reader, pver uint32, enc MessageEncoding) error {
-	return msg.bsvdecode(r, pver, enc, nil)
-}
-
-// bsvdecode is the internal deserialization implementation. When arena is
-// non-nil (block-level decoding), each script is read directly into an arena
-// slice, eliminating the per-tx heap allocation and memcpy of the old scratch
-// approach. When arena is nil (single-tx decoding), the traditional scriptPool
-// path is used unchanged.
-func (msg *MsgTx) bsvdecode(r io.Reader, pver uint32, _ MessageEncoding, arena *blockArena) error {
-	version, err := binarySerializer.Uint32(r, littleEndian)
-	if err != nil {
+func (msg *MsgTx) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) error {
+	// The scripts are read into buffers borrowed from the script pool
+	// and, once the whole transaction has been read, copied into a single
+	// contiguous buffer so the transaction makes one allocation for all of
+	// them rather than one per script.
+	if err := msg.decode(r, pver, pooledScripts{}); err != nil {
+		msg.returnScriptBuffers()
 		return err
 	}
 
-	msg.Version = int32(version)
-
-	count, err := ReadVarInt(r, pver)
-	if err != nil {
-		return err
-	}
-
-	// Prevent more input transactions than could possibly fit into a
-	// message.  It would be possible to cause memory exhaustion and panic
-	// without a sane upper bound on this count.
-	if count > maxTxInPerMessage() {
-		str := fmt.Sprintf("too many input transactions to fit into "+
-			"max message size [count %d, max %d]", count, maxTxInPerMessage())
-		return messageError("MsgTx.Bsvdecode", str)
-	}
-
-	// returnScriptBuffers is a closure that returns any script buffers that
-	// were borrowed from the pool when there are any deserialization
-	// errors.  This is only valid to call before the final step which
-	// replaces the scripts with the location in a contiguous buffer and
-	// returns them.
-	returnScriptBuffers := func() {
-		for _, txIn := range msg.TxIn {
-			if txIn == nil {
-				continue
-			}
-
-			if txIn.SignatureScript != nil {
-				scriptPool.Return(txIn.SignatureScript)
-			}
-		}
-
-		for _, txOut := range msg.TxOut {
-			if txOut == nil || txOut.PkScript == nil {
-				continue
-			}
-
-			scriptPool.Return(txOut.PkScript)
-		}
-	}
-
-	// When arena is provided (block-level decoding), each script is read
-	// directly into an arena slice. No intermediate scratch buffer, no
-	// per-tx copy. When arena is nil (single-tx decoding), the traditional
-	// scriptPool path is used unchanged.
-	if arena != nil {
-		return msg.bsvdecodeWithArena(r, pver, count, arena)
-	}
-
-	// Deserialize the inputs using script pool (single-tx path).
 	var totalScriptSize uint64
 
-	txIns := make([]TxIn, count)
-	msg.TxIn = make([]*TxIn, count)
-
-	for i := uint64(0); i < count; i++ {
-		ti := &txIns[i]
-		msg.TxIn[i] = ti
-
-		err = readTxIn(r, pver, msg.Version, ti)
-		if err != nil {
-			returnScriptBuffers()
-			return err
-		}
-
-		totalScriptSize += uint64(len(ti.SignatureScript))
-	}
-
-	count, err = ReadVarInt(r, pver)
-	if err != nil {
-		returnScriptBuffers()
-		return err
-	}
-
-	if count > maxTxOutPerMessage() {
-		returnScriptBuffers()
-
-		str := fmt.Sprintf("too many output transactions to fit into "+
-			"max message size [count %d, max %d]", count, maxTxOutPerMessage())
-
-		return messageError("MsgTx.Bsvdecode", str)
-	}
-
-	txOuts := make([]TxOut, count)
-	msg.TxOut = make([]*TxOut, count)
-
-	for i := uint64(0); i < count; i++ {
-		to := &txOuts[i]
-		msg.TxOut[i] = to
-
-		err = readTxOut(r, pver, msg.Version, to)
-		if err != nil {
-			returnScriptBuffers()
-			return err
-		}
-
-		totalScriptSize += uint64(len(to.PkScript))
+	for _, txIn := range msg.TxIn {
+		totalScriptSize += uint64(len(txIn.SignatureScript))
 	}
 
-	msg.LockTime, err = binarySerializer.Uint32(r, littleEndian)
-	if err != nil {
-		returnScriptBuffers()
-		return err
+	for _, txOut := range msg.TxOut {
+		totalScriptSize += uint64(len(txOut.PkScript))
 	}
 
 	var offset uint64
@@ -499,96 +631,96 @@ func (msg *MsgTx) bsvdecode(r io.Reader, pver uint32, _ MessageEncoding, arena *
 	return nil
 }
 
-// bsvdecodeWithArena deserializes a transaction using the block-scoped arena
-// allocator. Each script is read directly from r into an arena.Alloc slice,
-// which becomes the final SignatureScript / PkScript with no intermediate
-// copy. The arena ensures:
-//   - Stable slices: previously returned slices are never invalidated.
-//   - cap == len on every script slice.
-//   - Oversize scripts (> blockArenaChunkSize) get a private chunk.
-func (msg *MsgTx) bsvdecodeWithArena(r io.Reader, pver uint32, inCount uint64, arena *blockArena) error {
-	txIns := make([]TxIn, inCount)
-	msg.TxIn = make([]*TxIn, inCount)
-
-	for i := uint64(0); i < inCount; i++ {
-		ti := &txIns[i]
-		msg.TxIn[i] = ti
-
-		err := readOutPoint(r, pver, msg.Version, &ti.PreviousOutPoint)
-		if err != nil {
-			return err
+// returnScriptBuffers returns any script buffers that were borrowed from the
+// pool when there are any deserialization errors.  This is only valid to call
+// before the scripts are replaced with their location in a contiguous buffer.
+func (msg *MsgTx) returnScriptBuffers() {
+	for _, txIn := range msg.TxIn {
+		if txIn == nil {
+			continue
 		}
 
-		scriptLen, err := ReadVarInt(r, pver)
-		if err != nil {
-			return err
+		if txIn.SignatureScript != nil {
+			scriptPool.Return(txIn.SignatureScript)
 		}
+	}
 
-		if scriptLen > maxTxInPerMessage() {
-			str := fmt.Sprintf("transaction input signature script is larger than the max allowed size "+
-				"[count %d, max %d]", scriptLen, maxTxInPerMessage())
-			return messageError("MsgTx.bsvdecodeWithArena", str)
+	for _, txOut := range msg.TxOut {
+		if txOut == nil || txOut.PkScript == nil {
+			continue
 		}
 
-		// Alloc returns nil for scriptLen==0, which is valid (empty script).
-		s := arena.Alloc(int(scriptLen))
-		if scriptLen > 0 {
-			if _, err = io.ReadFull(r, s); err != nil {
-				return err
-			}
-		}
-		ti.SignatureScript = s
+		scriptPool.Return(txOut.PkScript)
+	}
+}
 
-		if err = readElement(r, &ti.Sequence); err != nil {
-			return err
-		}
+// decode decodes a transaction from r into the receiver with its scripts read
+// by src, which determines where their bytes live.  Every decode path shares
+// it, so the same limits apply whether the scripts are borrowed from the
+// script pool, allocated from a block arena or alias the buffer of r.
+func (msg *MsgTx) decode(r io.Reader, pver uint32, src scriptSource) error {
+	lim := limitsOf(r)
+
+	err := readUint32(r, &msg.Version)
+	if err != nil {
+		return traceField(r, err, "Version")
 	}
 
-	outCount, err := ReadVarInt(r, pver)
+	count, err := ReadVarInt(r, pver)
 	if err != nil {
-		return err
+		return traceField(r, err, "TxIn")
 	}
 
-	if outCount > maxTxOutPerMessage() {
-		str := fmt.Sprintf("too many output transactions to fit into "+
-			"max message size [count %d, max %d]", outCount, maxTxOutPerMessage())
-		return messageError("MsgTx.bsvdecodeWithArena", str)
+	// Prevent more input transactions than could possibly fit into a
+	// message.  It would be possible to cause memory exhaustion and panic
+	// without a sane upper bound on this count.
+	if count > lim.maxTxInPerMessage() {
+		str := fmt.Sprintf("too many input transactions to fit into "+
+			"max message size [count %d, max %d]", count, lim.maxTxInPerMessage())
+		return traceField(r, messageError("MsgTx.Bsvdecode", ErrTooManyItems, str), "TxIn")
 	}
 
-	txOuts := make([]TxOut, outCount)
-	msg.TxOut = make([]*TxOut, outCount)
+	if err = checkRemainingCount(r, count, minTxInPayload, "MsgTx.Bsvdecode", "input transactions"); err != nil {
+		return traceField(r, err, "TxIn")
+	}
 
-	for i := uint64(0); i < outCount; i++ {
-		to := &txOuts[i]
-		msg.TxOut[i] = to
+	msg.TxIn = recycleList(msg.TxIn, count, recyclingOf(r))
 
-		if err = readElement(r, &to.Value); err != nil {
-			return err
+	for i := uint64(0); i < count; i++ {
+		if err = decodeTxIn(r, pver, msg.Version, msg.TxIn[i], src); err != nil {
+			return traceItem(r, err, "TxIn", i)
 		}
+	}
 
-		var scriptLen uint64
-		scriptLen, err = ReadVarInt(r, pver)
-		if err != nil {
-			return err
-		}
+	count, err = ReadVarInt(r, pver)
+	if err != nil {
+		return traceField(r, err, "TxOut")
+	}
 
-		if scriptLen > maxMessagePayload() {
-			str := fmt.Sprintf("transaction output public key script is larger than the max allowed size "+
-				"[count %d, max %d]", scriptLen, maxMessagePayload())
-			return messageError("MsgTx.bsvdecodeWithArena", str)
-		}
+	if count > lim.maxTxOutPerMessage() {
+		str := fmt.Sprintf("too many output transactions to fit into "+
+			"max message size [count %d, max %d]", count, lim.maxTxOutPerMessage())
+		return traceField(r, messageError("MsgTx.Bsvdecode", ErrTooManyItems, str), "TxOut")
+	}
+
+	if err = checkRemainingCount(r, count, MinTxOutPayload, "MsgTx.Bsvdecode", "output transactions"); err != nil {
+		return traceField(r, err, "TxOut")
+	}
+
+	msg.TxOut = recycleList(msg.TxOut, count, recyclingOf(r))
 
-		s := arena.Alloc(int(scriptLen))
-		if scriptLen > 0 {
-			if _, err = io.ReadFull(r, s); err != nil {
-				return err
-			}
+	for i := uint64(0); i < count; i++ {
+		if err = decodeTxOut(r, pver, msg.Version, msg.TxOut[i], src); err != nil {
+			return traceItem(r, err, "TxOut", i)
 		}
-		to.PkScript = s
 	}
 
-	msg.LockTime, err = binarySerializer.Uint32(r, littleEndian)
-	return err
+	err = readUint32(r, &msg.LockTime)
+	if err != nil {
+		return traceField(r, err, "LockTime")
+	}
+
+	return nil
 }
 
 // Deserialize decodes a transaction from r into the receiver using a format
@@ -608,12 +740,41 @@ func (msg *MsgTx) Deserialize(r io.Reader) error {
 	return msg.Bsvdecode(r, 0, BaseEncoding)
 }
 
+// FromBytes decodes the transaction serialized in b into the receiver using
+// the same format as Deserialize, without copying the scripts out of b.  Every
+// SignatureScript and PkScript of the decoded transaction is a sub-slice of b
+// with cap == len, so appending to a script never overwrites b.  An error is
+// returned when b holds anything after the transaction.
+//
+// The receiver retains b: b must not be modified for as long as the
+// transaction is in use, and any retained script keeps all of b from being
+// garbage collected.  Use Copy to obtain a transaction independent of b.
+func (msg *MsgTx) FromBytes(b []byte) error {
+	r := &sliceReader{buf: b}
+	if err := msg.decodeAlias(r, 0); err != nil {
+		return err
+	}
+
+	if r.Len() != 0 {
+		str := fmt.Sprintf("%d trailing bytes after transaction", r.Len())
+		return messageError("MsgTx.FromBytes", ErrMalformedMessage, str)
+	}
+
+	return nil
+}
+
+// decodeAlias decodes a transaction from r with its scripts aliasing the
+// buffer of r.  This is part of the aliasDecoder interface implementation.
+func (msg *MsgTx) decodeAlias(r *sliceReader, pver uint32) error {
+	return msg.decode(r, pver, r)
+}
+
 // BsvEncode encodes the receiver to w using the bitcoin protocol encoding.
 // This is part of the Message interface implementation.
 // See Serialize for encoding transactions to be stored to disk, such as in a
 // database, as opposed to encoding transactions for the wire.
 func (msg *MsgTx) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error {
-	err := binarySerializer.PutUint32(w, littleEndian, uint32(msg.Version))
+	err := writeUint32(w, msg.Version)
 	if err != nil {
 		return err
 	}
@@ -646,7 +807,113 @@ func (msg *MsgTx) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) error {
 		}
 	}
 
-	return binarySerializer.PutUint32(w, littleEndian, msg.LockTime)
+	return writeUint32(w, msg.LockTime)
+}
+
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgTx) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgTx) PayloadSize(_ uint32) int {
+	return msg.SerializeSize()
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgTx) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgTx) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgTx) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgTx) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgTxJSON is the JSON representation of a transaction.  It follows the
+// decoderawtransaction RPC of bitcoind.
+type msgTxJSON struct {
+	TxID     jsonHash    `json:"txid"`
+	Hash     jsonHash    `json:"hash"`
+	Version  int32       `json:"version"`
+	Size     int         `json:"size"`
+	LockTime uint32      `json:"locktime"`
+	Vin      []txInJSON  `json:"vin"`
+	Vout     []txOutJSON `json:"vout"`
+}
+
+// MarshalJSON returns the JSON representation of the transaction in the
+// format of the decoderawtransaction RPC of bitcoind, with scripts as hex.  The
+// txid, hash and size are computed from the transaction and ignored by
+// UnmarshalJSON.  This is part of the json.Marshaler interface implementation.
+func (msg *MsgTx) MarshalJSON() ([]byte, error) {
+	txHash := jsonHash(msg.TxHash())
+
+	vin := make([]txInJSON, len(msg.TxIn))
+	for i, ti := range msg.TxIn {
+		vin[i] = ti.toJSON()
+	}
+
+	return json.Marshal(msgTxJSON{
+		TxID:     txHash,
+		Hash:     txHash,
+		Version:  msg.Version,
+		Size:     msg.SerializeSize(),
+		LockTime: msg.LockTime,
+		Vin:      vin,
+		Vout:     toTxOutsJSON(msg.TxOut),
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgTx) UnmarshalJSON(data []byte) error {
+	var j msgTxJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	backing := make([]TxIn, len(j.Vin))
+	txIns := make([]*TxIn, len(j.Vin))
+
+	for i := range j.Vin {
+		if err := backing[i].fromJSON(&j.Vin[i]); err != nil {
+			return err
+		}
+
+		txIns[i] = &backing[i]
+	}
+
+	*msg = MsgTx{
+		Version:  j.Version,
+		TxIn:     txIns,
+		TxOut:    fromTxOutsJSON(j.Vout),
+		LockTime: j.LockTime,
+	}
+
+	return nil
 }
 
 // Serialize encodes the transaction to w using a format that suitable for
@@ -666,6 +933,14 @@ func (msg *MsgTx) Serialize(w io.Writer) error {
 	return msg.BsvEncode(w, 0, BaseEncoding)
 }
 
+// AppendSerialize appends the encoding of the transaction, using the format of
+// Serialize, to dst and returns the extended buffer.  The buffer is grown to
+// the exact size of the transaction up front, so repeatedly serializing into
+// one reused buffer does not allocate.
+func (msg *MsgTx) AppendSerialize(dst []byte) ([]byte, error) {
+	return msg.AppendBsvEncode(dst, 0, BaseEncoding)
+}
+
 // baseSize returns the serialized size of the transaction without accounting
 // for any witness data.
 func (msg *MsgTx) baseSize() int {
@@ -743,6 +1018,13 @@ func (msg *MsgTx) PkScriptLocs() []int {
 	return pkScriptLocs
 }
 
+// Reset resets the message to its zero state while keeping the capacity of
+// TxIn and TxOut, along with the inputs and outputs they point to, for reuse
+// by the next decode.  This is part of the Resetter interface implementation.
+func (msg *MsgTx) Reset() {
+	*msg = MsgTx{TxIn: msg.TxIn[:0], TxOut: msg.TxOut[:0]}
+}
+
 // NewMsgTx returns a new bitcoin tx message that conforms to the Message
 // interface.  The return instance has a default version of TxVersion, and there
 // are no transaction inputs or outputs.  Also, the lock time is set to zero
@@ -758,14 +1040,12 @@ func NewMsgTx(version int32) *MsgTx {
 
 // readOutPoint reads the next sequence of bytes from r as an OutPoint.
 func readOutPoint(r io.Reader, _ uint32, _ int32, op *OutPoint) error {
-	_, err := io.ReadFull(r, op.Hash[:])
+	err := readHash(r, &op.Hash)
 	if err != nil {
 		return err
 	}
 
-	op.Index, err = binarySerializer.Uint32(r, littleEndian)
-
-	return err
+	return readUint32(r, &op.Index)
 }
 
 // writeOutPoint encodes op to the bitcoin protocol encoding for an OutPoint
@@ -776,7 +1056,7 @@ func writeOutPoint(w io.Writer, _ uint32, _ int32, op *OutPoint) error {
 		return err
 	}
 
-	return binarySerializer.PutUint32(w, littleEndian, op.Index)
+	return writeUint32(w, op.Index)
 }
 
 // readScript reads a variable length byte array that represents a transaction
@@ -787,20 +1067,11 @@ func writeOutPoint(w io.Writer, _ uint32, _ int32, op *OutPoint) error {
 // fieldName parameter is only used for the error message so it provides more
 // context in the error.
 func readScript(r io.Reader, pver uint32, maxAllowed uint64, fieldName string) ([]byte, error) {
-	count, err := ReadVarInt(r, pver)
+	count, err := readScriptLength(r, pver, maxAllowed, fieldName)
 	if err != nil {
 		return nil, err
 	}
 
-	// Prevent a byte array larger than the max message size.  It would
-	// be possible to cause memory exhaustion and panic without a sane
-	// upper bound on this count.
-	if count > maxAllowed {
-		str := fmt.Sprintf("%s is larger than the max allowed size "+
-			"[count %d, max %d]", fieldName, count, maxAllowed)
-		return nil, messageError("readScript", str)
-	}
-
 	b := scriptPool.Borrow(count)
 	_, err = io.ReadFull(r, b)
 	if err != nil {
@@ -811,21 +1082,68 @@ func readScript(r io.Reader, pver uint32, maxAllowed uint64, fieldName string) (
 	return b, nil
 }
 
+// scriptSource reads the scripts of the transactions being decoded, which
+// determines where their bytes live: in buffers borrowed from scriptPool, in
+// a block-scoped arena or in the buffer the transactions are decoded from.
+type scriptSource interface {
+	readScript(r io.Reader, pver uint32, maxAllowed uint64, fieldName string) ([]byte, error)
+}
+
+// pooledScripts is the scriptSource which reads scripts into buffers borrowed
+// from scriptPool.
+type pooledScripts struct{}
+
+// readScript reads a script like the readScript function.  This is part of
+// the scriptSource interface implementation.
+func (pooledScripts) readScript(r io.Reader, pver uint32, maxAllowed uint64, fieldName string) ([]byte, error) {
+	return readScript(r, pver, maxAllowed, fieldName)
+}
+
+// readScriptLength reads the variable length integer which prefixes a
+// transaction script and ensures it does not exceed maxAllowed.
+func readScriptLength(r io.Reader, pver uint32, maxAllowed uint64, fieldName string) (uint64, error) {
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return 0, err
+	}
+
+	// Prevent a byte array larger than the max message size.  It would
+	// be possible to cause memory exhaustion and panic without a sane
+	// upper bound on this count.
+	if count > maxAllowed {
+		str := fmt.Sprintf("%s is larger than the max allowed size "+
+			"[count %d, max %d]", fieldName, count, maxAllowed)
+		return 0, messageError("readScript", ErrElementTooLarge, str)
+	}
+
+	return count, nil
+}
+
 // readTxIn reads the next sequence of bytes from r as a transaction input
 // (TxIn).
 func readTxIn(r io.Reader, pver uint32, version int32, ti *TxIn) error {
+	return decodeTxIn(r, pver, version, ti, pooledScripts{})
+}
+
+// decodeTxIn reads a transaction input from r like readTxIn with its signature
+// script read by src.
+func decodeTxIn(r io.Reader, pver uint32, version int32, ti *TxIn, src scriptSource) error {
 	err := readOutPoint(r, pver, version, &ti.PreviousOutPoint)
 	if err != nil {
-		return err
+		return traceField(r, err, "PreviousOutPoint")
 	}
 
-	ti.SignatureScript, err = readScript(r, pver, maxTxInPerMessage(),
+	ti.SignatureScript, err = src.readScript(r, pver, limitsOf(r).maxTxInPerMessage(),
 		"transaction input signature script")
 	if err != nil {
-		return err
+		return traceField(r, err, "SignatureScript")
 	}
 
-	return readElement(r, &ti.Sequence)
+	if err = readUint32(r, &ti.Sequence); err != nil {
+		return traceField(r, err, "Sequence")
+	}
+
+	return nil
 }
 
 // writeTxIn encodes ti to the bitcoin protocol encoding for a transaction
@@ -841,21 +1159,30 @@ func writeTxIn(w io.Writer, pver uint32, version int32, ti *TxIn) error {
 		return err
 	}
 
-	return binarySerializer.PutUint32(w, littleEndian, ti.Sequence)
+	return writeUint32(w, ti.Sequence)
 }
 
 // readTxOut reads the next sequence of bytes from r as a transaction output
 // (TxOut).
-func readTxOut(r io.Reader, pver uint32, _ int32, to *TxOut) error {
-	err := readElement(r, &to.Value)
+func readTxOut(r io.Reader, pver uint32, version int32, to *TxOut) error {
+	return decodeTxOut(r, pver, version, to, pooledScripts{})
+}
+
+// decodeTxOut reads a transaction output from r like readTxOut with its public
+// key script read by src.
+func decodeTxOut(r io.Reader, pver uint32, _ int32, to *TxOut, src scriptSource) error {
+	err := readUint64(r, &to.Value)
 	if err != nil {
-		return err
+		return traceField(r, err, "Value")
 	}
 
-	to.PkScript, err = readScript(r, pver, maxMessagePayload(),
+	to.PkScript, err = src.readScript(r, pver, limitsOf(r).maxMessagePayload(),
 		"transaction output public key script")
+	if err != nil {
+		return traceField(r, err, "PkScript")
+	}
 
-	return err
+	return nil
 }
 
 // WriteTxOut encodes to into the bitcoin protocol encoding for a transaction
@@ -864,7 +1191,7 @@ func readTxOut(r io.Reader, pver uint32, _ int32, to *TxOut) error {
 // NOTE: This function is exported to allow txscript to compute the
 // new sighashes for witness transactions (BIP0143).
 func WriteTxOut(w io.Writer, pver uint32, _ int32, to *TxOut) error {
-	err := binarySerializer.PutUint64(w, littleEndian, uint64(to.Value))
+	err := writeUint64(w, to.Value)
 	if err != nil {
 		return err
 	}
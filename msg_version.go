@@ -6,6 +6,7 @@ package wire
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strings"
@@ -89,12 +90,25 @@ func (msg *MsgVersion) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) er
 			"*bytes.Buffer")
 	}
 
-	err := readElements(buf, &msg.ProtocolVersion, &msg.Services,
-		(*int64Time)(&msg.Timestamp))
+	err := readUint32(buf, &msg.ProtocolVersion)
 	if err != nil {
 		return err
 	}
 
+	err = readUint64(buf, &msg.Services)
+	if err != nil {
+		return err
+	}
+
+	var sec int64
+
+	err = readUint64(buf, &sec)
+	if err != nil {
+		return err
+	}
+
+	msg.Timestamp = time.Unix(sec, 0)
+
 	err = readNetAddress(buf, pver, &msg.AddrYou, false)
 	if err != nil {
 		return err
@@ -111,7 +125,7 @@ func (msg *MsgVersion) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) er
 	}
 
 	if buf.Len() > 0 {
-		err = readElement(buf, &msg.Nonce)
+		err = readUint64(buf, &msg.Nonce)
 		if err != nil {
 			return err
 		}
@@ -135,7 +149,7 @@ func (msg *MsgVersion) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) er
 	// Protocol versions >= 209 added a last known block field.  It is only
 	// considered present if there are bytes remaining in the message.
 	if buf.Len() > 0 {
-		err = readElement(buf, &msg.LastBlock)
+		err = readUint32(buf, &msg.LastBlock)
 		if err != nil {
 			return err
 		}
@@ -152,7 +166,7 @@ func (msg *MsgVersion) Bsvdecode(r io.Reader, pver uint32, _ MessageEncoding) er
 		// it for the DisableRelayTx field.
 		var relayTx bool
 
-		_ = readElement(r, &relayTx)
+		_ = readBool(r, &relayTx)
 		msg.DisableRelayTx = !relayTx
 	}
 
@@ -176,8 +190,17 @@ func (msg *MsgVersion) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) er
 		return err
 	}
 
-	err = writeElements(w, msg.ProtocolVersion, msg.Services,
-		msg.Timestamp.Unix())
+	err = writeUint32(w, msg.ProtocolVersion)
+	if err != nil {
+		return err
+	}
+
+	err = writeUint64(w, msg.Services)
+	if err != nil {
+		return err
+	}
+
+	err = writeUint64(w, msg.Timestamp.Unix())
 	if err != nil {
 		return err
 	}
@@ -192,7 +215,7 @@ func (msg *MsgVersion) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) er
 		return err
 	}
 
-	err = writeElement(w, msg.Nonce)
+	err = writeUint64(w, msg.Nonce)
 	if err != nil {
 		return err
 	}
@@ -202,7 +225,7 @@ func (msg *MsgVersion) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) er
 		return err
 	}
 
-	err = writeElement(w, msg.LastBlock)
+	err = writeUint32(w, msg.LastBlock)
 	if err != nil {
 		return err
 	}
@@ -211,7 +234,7 @@ func (msg *MsgVersion) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) er
 	// the wire encoding for the field is true when transactions should be
 	// relayed, so reverse it from the DisableRelayTx field.
 	if pver >= BIP0037Version {
-		err = writeElement(w, !msg.DisableRelayTx)
+		err = writeBool(w, !msg.DisableRelayTx)
 		if err != nil {
 			return err
 		}
@@ -228,6 +251,119 @@ func (msg *MsgVersion) BsvEncode(w io.Writer, pver uint32, _ MessageEncoding) er
 	return nil
 }
 
+// AppendBsvEncode appends the bitcoin protocol encoding of the receiver to dst
+// and returns the extended buffer.  This is part of the AppendEncoder
+// interface implementation.
+func (msg *MsgVersion) AppendBsvEncode(dst []byte, pver uint32, enc MessageEncoding) ([]byte, error) {
+	return appendBsvEncode(dst, msg, pver, enc)
+}
+
+// PayloadSize returns the number of bytes BsvEncode writes for the receiver
+// at the provided protocol version.  This is part of the PayloadSizer
+// interface implementation.
+func (msg *MsgVersion) PayloadSize(pver uint32) int {
+	// ProtocolVersion 4 bytes + Services 8 bytes + Timestamp 8 bytes +
+	// AddrYou and AddrMe without timestamps 26 bytes each + Nonce 8 bytes +
+	// UserAgent + LastBlock 4 bytes.
+	n := 84 + VarIntSerializeSize(uint64(len(msg.UserAgent))) + len(msg.UserAgent)
+
+	// RelayTx 1 byte.
+	if pver >= BIP0037Version {
+		n++
+	}
+
+	if len(msg.AssociationID) > 0 {
+		n += VarIntSerializeSize(uint64(len(msg.AssociationID))) + len(msg.AssociationID)
+	}
+
+	return n
+}
+
+// MarshalBinary returns the bitcoin protocol encoding of the receiver at the
+// latest protocol version.  This is part of the encoding.BinaryMarshaler
+// interface implementation.
+func (msg *MsgVersion) MarshalBinary() ([]byte, error) {
+	return marshalMessage(msg)
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of a message at the latest protocol version, into the receiver.
+// This is part of the encoding.BinaryUnmarshaler interface implementation.
+func (msg *MsgVersion) UnmarshalBinary(data []byte) error {
+	return unmarshalMessage(msg, data)
+}
+
+// WriteTo writes the bitcoin protocol encoding of the receiver at the latest
+// protocol version to w and returns the number of bytes written.  This is part
+// of the io.WriterTo interface implementation.
+func (msg *MsgVersion) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(msg, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding at the
+// latest protocol version read from r until EOF and returns the number of
+// bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (msg *MsgVersion) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(msg, r, msg.MaxPayloadLength(ProtocolVersion))
+}
+
+// msgVersionJSON is the JSON representation of a version message.
+type msgVersionJSON struct {
+	ProtocolVersion int32        `json:"version"`
+	Services        jsonServices `json:"services"`
+	Timestamp       int64        `json:"timestamp"`
+	AddrYou         NetAddress   `json:"addryou"`
+	AddrMe          NetAddress   `json:"addrme"`
+	Nonce           uint64       `json:"nonce"`
+	UserAgent       string       `json:"useragent"`
+	LastBlock       int32        `json:"lastblock"`
+	DisableRelayTx  bool         `json:"disablerelaytx"`
+	AssociationID   jsonHex      `json:"associationid,omitempty"`
+}
+
+// MarshalJSON returns the JSON representation of the message, which holds the
+// services by name and the timestamp as a unix time.  The timestamps of the
+// addresses are represented although they are not encoded.  This is part of
+// the json.Marshaler interface implementation.
+func (msg *MsgVersion) MarshalJSON() ([]byte, error) {
+	return json.Marshal(msgVersionJSON{
+		ProtocolVersion: msg.ProtocolVersion,
+		Services:        jsonServices(msg.Services),
+		Timestamp:       msg.Timestamp.Unix(),
+		AddrYou:         msg.AddrYou,
+		AddrMe:          msg.AddrMe,
+		Nonce:           msg.Nonce,
+		UserAgent:       msg.UserAgent,
+		LastBlock:       msg.LastBlock,
+		DisableRelayTx:  msg.DisableRelayTx,
+		AssociationID:   msg.AssociationID,
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (msg *MsgVersion) UnmarshalJSON(data []byte) error {
+	var j msgVersionJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	*msg = MsgVersion{
+		ProtocolVersion: j.ProtocolVersion,
+		Services:        ServiceFlag(j.Services),
+		Timestamp:       time.Unix(j.Timestamp, 0),
+		AddrYou:         j.AddrYou,
+		AddrMe:          j.AddrMe,
+		Nonce:           j.Nonce,
+		UserAgent:       j.UserAgent,
+		LastBlock:       j.LastBlock,
+		DisableRelayTx:  j.DisableRelayTx,
+		AssociationID:   j.AssociationID,
+	}
+
+	return nil
+}
+
 // Command returns the protocol command string for the message.  This is part
 // of the Message interface implementation.
 func (msg *MsgVersion) Command() string {
@@ -245,6 +381,12 @@ func (msg *MsgVersion) MaxPayloadLength(pver uint32) uint64 {
 		MaxUserAgentLen + MaxVarIntPayload + MaxAssociationIDLen
 }
 
+// Reset resets the message to its zero state.  This is part of the Resetter
+// interface implementation.
+func (msg *MsgVersion) Reset() {
+	*msg = MsgVersion{}
+}
+
 // NewMsgVersion returns a new bitcoin version message that conforms to the
 // Message interface using the passed parameters and defaults for the remaining
 // fields.
@@ -271,7 +413,7 @@ func validateUserAgent(userAgent string) error {
 	if len(userAgent) > MaxUserAgentLen {
 		str := fmt.Sprintf("user agent too long [len %v, max %v]",
 			len(userAgent), MaxUserAgentLen)
-		return messageError("MsgVersion", str)
+		return messageError("MsgVersion", ErrElementTooLarge, str)
 	}
 
 	return nil
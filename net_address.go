@@ -5,7 +5,8 @@
 package wire
 
 import (
-	"encoding/binary"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net"
 	"time"
@@ -57,6 +58,91 @@ func (na *NetAddress) AddService(service ServiceFlag) {
 	na.Services |= service
 }
 
+// MarshalBinary returns the bitcoin protocol encoding of the address,
+// including its timestamp as in addr messages, at the latest protocol version.
+// This is part of the encoding.BinaryMarshaler interface implementation.
+func (na *NetAddress) MarshalBinary() ([]byte, error) {
+	aw := &appendWriter{b: make([]byte, 0, maxNetAddressPayload(ProtocolVersion))}
+	if err := writeNetAddress(aw, ProtocolVersion, na, true); err != nil {
+		return nil, err
+	}
+
+	return aw.b, nil
+}
+
+// UnmarshalBinary decodes data, which must hold exactly the bitcoin protocol
+// encoding of an address including its timestamp at the latest protocol
+// version, into the receiver.  This is part of the encoding.BinaryUnmarshaler
+// interface implementation.
+func (na *NetAddress) UnmarshalBinary(data []byte) error {
+	return decodeExact(data, "network address", func(r io.Reader) error {
+		return readNetAddress(r, ProtocolVersion, na, true)
+	})
+}
+
+// WriteTo writes the bitcoin protocol encoding of the address, including its
+// timestamp, to w and returns the number of bytes written.  This is part of the
+// io.WriterTo interface implementation.
+func (na *NetAddress) WriteTo(w io.Writer) (int64, error) {
+	return writeTo(na, w)
+}
+
+// ReadFrom decodes the receiver from the bitcoin protocol encoding of an
+// address including its timestamp read from r until EOF and returns the number
+// of bytes read.  This is part of the io.ReaderFrom interface implementation.
+func (na *NetAddress) ReadFrom(r io.Reader) (int64, error) {
+	return readFrom(na, r, maxNetAddressPayload(ProtocolVersion))
+}
+
+// netAddressJSON is the JSON representation of a network address.
+type netAddressJSON struct {
+	Timestamp uint32       `json:"timestamp"`
+	Services  jsonServices `json:"services"`
+	IP        string       `json:"ip"`
+	Port      uint16       `json:"port"`
+}
+
+// MarshalJSON returns the JSON representation of the address, which holds its
+// timestamp as a unix time, its services by name and its IP address in
+// textual form.  This is part of the json.Marshaler interface implementation.
+func (na NetAddress) MarshalJSON() ([]byte, error) {
+	// Represent the 16 bytes which are encoded, so a nil IP reads as "::".
+	var ip [16]byte
+	if na.IP != nil {
+		copy(ip[:], na.IP.To16())
+	}
+
+	return json.Marshal(netAddressJSON{
+		Timestamp: uint32(na.Timestamp.Unix()),
+		Services:  jsonServices(na.Services),
+		IP:        net.IP(ip[:]).String(),
+		Port:      na.Port,
+	})
+}
+
+// UnmarshalJSON sets the receiver from the JSON representation returned by
+// MarshalJSON.  This is part of the json.Unmarshaler interface implementation.
+func (na *NetAddress) UnmarshalJSON(data []byte) error {
+	var j netAddressJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(j.IP)
+	if ip == nil {
+		return jsonError(fmt.Sprintf("invalid IP address %q", j.IP))
+	}
+
+	*na = NetAddress{
+		Timestamp: time.Unix(int64(j.Timestamp), 0),
+		Services:  ServiceFlag(j.Services),
+		IP:        ip.To16(),
+		Port:      j.Port,
+	}
+
+	return nil
+}
+
 // NewNetAddressIPPort returns a new NetAddress using the provided IP, port, and
 // supported services with defaults for the remaining fields.
 func NewNetAddressIPPort(ip net.IP, port uint16, services ServiceFlag) *NetAddress {
@@ -97,18 +183,28 @@ func readNetAddress(r io.Reader, pver uint32, na *NetAddress, ts bool) error {
 	// stop working somewhere around 2106.  Also timestamp wasn't added until
 	// protocol version >= NetAddressTimeVersion
 	if ts && pver >= NetAddressTimeVersion {
-		err := readElement(r, (*uint32Time)(&na.Timestamp))
+		var sec uint32
+
+		err := readUint32(r, &sec)
 		if err != nil {
 			return err
 		}
+
+		na.Timestamp = time.Unix(int64(sec), 0)
+	}
+
+	err := readUint64(r, &na.Services)
+	if err != nil {
+		return err
 	}
 
-	err := readElements(r, &na.Services, &ip)
+	err = readFixed(r, ip[:])
 	if err != nil {
 		return err
 	}
+
 	// Sigh.  Bitcoin protocol mixes little and big endian.
-	port, err := binarySerializer.Uint16(r, bigEndian)
+	port, err := readPort(r)
 	if err != nil {
 		return err
 	}
@@ -131,7 +227,7 @@ func writeNetAddress(w io.Writer, pver uint32, na *NetAddress, ts bool) error {
 	// stop working somewhere around 2106.  Also timestamp wasn't added until
 	// until protocol version >= NetAddressTimeVersion.
 	if ts && pver >= NetAddressTimeVersion {
-		err := writeElement(w, uint32(na.Timestamp.Unix()))
+		err := writeUint32(w, uint32(na.Timestamp.Unix()))
 		if err != nil {
 			return err
 		}
@@ -143,11 +239,16 @@ func writeNetAddress(w io.Writer, pver uint32, na *NetAddress, ts bool) error {
 		copy(ip[:], na.IP.To16())
 	}
 
-	err := writeElements(w, na.Services, ip)
+	err := writeUint64(w, na.Services)
+	if err != nil {
+		return err
+	}
+
+	err = writeFixed(w, ip[:])
 	if err != nil {
 		return err
 	}
 
 	// Sigh.  Bitcoin protocol mixes little and big endian.
-	return binary.Write(w, bigEndian, na.Port)
+	return writePort(w, na.Port)
 }
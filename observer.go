@@ -0,0 +1,169 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Direction identifies whether a message was read or written.
+type Direction uint8
+
+// These constants define the directions of observed messages.
+const (
+	// DirectionRead is the direction of messages read by a Codec.
+	DirectionRead Direction = iota
+
+	// DirectionWrite is the direction of messages written by a Codec.
+	DirectionWrite
+)
+
+// String returns the Direction in human-readable form.
+func (d Direction) String() string {
+	switch d {
+	case DirectionRead:
+		return "read"
+	case DirectionWrite:
+		return "write"
+	}
+
+	return fmt.Sprintf("Unknown Direction (%d)", uint8(d))
+}
+
+// MessageEvent describes a message read or written by a Codec.
+type MessageEvent struct {
+	// Command is the command of the message.
+	Command string
+
+	// Direction is the direction of the message.
+	Direction Direction
+
+	// HeaderBytes is the number of header bytes read or written.
+	HeaderBytes int
+
+	// PayloadBytes is the number of payload bytes read or written.
+	PayloadBytes int
+
+	// Duration is the time spent decoding the payload of a read message, which
+	// for streaming reads includes reading it, or encoding and writing a
+	// written message.  For batched writes only the encoding is timed since
+	// the messages are written together.
+	Duration time.Duration
+
+	// Err is the error the read or write failed with, if any.
+	Err error
+
+	// Kind is the kind of Err when it is, or wraps, a *MessageError.  It is
+	// zero otherwise.
+	Kind ErrorKind
+}
+
+// Observer is notified of every message a Codec reads or writes once the
+// header of the message is known, including messages which fail validation or
+// decoding.  Reads which fail before a complete header has been read, such as
+// at the end of a stream, are not observed.
+//
+// Observers are called synchronously from the read and write paths and must
+// therefore be fast and safe for concurrent access.
+type Observer interface {
+	ObserveMessage(ev MessageEvent)
+}
+
+// ObserverFunc is an adapter which allows an ordinary function to be used as
+// an Observer.
+type ObserverFunc func(ev MessageEvent)
+
+// ObserveMessage calls f(ev).  This is part of the Observer interface
+// implementation.
+func (f ObserverFunc) ObserveMessage(ev MessageEvent) {
+	f(ev)
+}
+
+// SetObserver sets the observer notified of the messages read and written by
+// the package-level read and write functions.  A nil observer disables
+// observation.
+//
+// This function is safe for concurrent access.
+func SetObserver(o Observer) {
+	defaultCodec.SetObserver(o)
+}
+
+// SetObserver sets the observer notified of the messages read and written by
+// the codec.  A nil observer disables observation.
+func (c *Codec) SetObserver(o Observer) {
+	c.mtx.Lock()
+	c.observer = o
+	c.mtx.Unlock()
+}
+
+// observation records a single message event for an observer.  A nil
+// observation records nothing, which keeps unobserved codecs cheap.
+type observation struct {
+	o     Observer
+	ev    MessageEvent
+	start time.Time
+}
+
+// observe returns an observation of a message with the provided command in
+// direction d, or nil when the codec has no observer.
+func (c *Codec) observe(d Direction, cmd string) *observation {
+	c.mtx.RLock()
+	o := c.observer
+	c.mtx.RUnlock()
+
+	if o == nil {
+		return nil
+	}
+
+	return &observation{o: o, ev: MessageEvent{Command: cmd, Direction: d}}
+}
+
+// begin starts timing the observed message.
+func (ob *observation) begin() {
+	if ob != nil {
+		ob.start = time.Now()
+	}
+}
+
+// stop stops timing the observed message.
+func (ob *observation) stop() {
+	if ob != nil && !ob.start.IsZero() {
+		ob.ev.Duration = time.Since(ob.start)
+		ob.start = time.Time{}
+	}
+}
+
+// end notifies the observer of the message with the provided byte counts and
+// error.
+func (ob *observation) end(hdrBytes, payloadBytes int, err error) {
+	if ob == nil {
+		return
+	}
+
+	ob.stop()
+
+	ob.ev.HeaderBytes = hdrBytes
+	ob.ev.PayloadBytes = payloadBytes
+	ob.ev.Err = err
+
+	if err != nil {
+		_ = errors.As(err, &ob.ev.Kind)
+	}
+
+	ob.o.ObserveMessage(ob.ev)
+}
+
+// writtenHeaderBytes returns how many of the n bytes written for a message
+// using the provided codec settings belong to its header.
+func writtenHeaderBytes(n int, p codecParams) int {
+	hdrSize := MessageHeaderSize
+	if p.extended || uint64(max(n-MessageHeaderSize, 0)) >= extLengthMarker {
+		hdrSize = ExtendedMessageHeaderSize
+	}
+
+	return min(n, hdrSize)
+}
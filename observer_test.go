@@ -0,0 +1,264 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventRecorder is an Observer which records the events it is notified of.
+type eventRecorder struct {
+	mtx    sync.Mutex
+	events []MessageEvent
+}
+
+// ObserveMessage records ev.  This is part of the Observer interface
+// implementation.
+func (r *eventRecorder) ObserveMessage(ev MessageEvent) {
+	r.mtx.Lock()
+	r.events = append(r.events, ev)
+	r.mtx.Unlock()
+}
+
+// take returns the recorded events and forgets them.
+func (r *eventRecorder) take() []MessageEvent {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	events := r.events
+	r.events = nil
+
+	return events
+}
+
+// TestObserverReads ensures observers are notified of the messages read on
+// every read path with their sizes and errors.
+func TestObserverReads(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	block := encodeFrame(t, codec, &blockOne)
+	blockPayload := len(block) - MessageHeaderSize
+
+	corrupt := bytes.Clone(block)
+	corrupt[len(corrupt)-1] ^= 0xff
+
+	wrongNet := encodeFrame(t, NewCodec(TestNet, ProtocolVersion), NewMsgPing(7))
+
+	var rec eventRecorder
+
+	codec.SetObserver(&rec)
+
+	reads := map[string]func(r io.Reader) (int, Message, error){
+		"Read": func(r io.Reader) (int, Message, error) {
+			n, msg, _, err := codec.Read(r)
+			return n, msg, err
+		},
+		"ReadStreaming": codec.ReadStreaming,
+		"DecodeBytes": func(r io.Reader) (int, Message, error) {
+			b, err := io.ReadAll(r)
+			require.NoError(t, err)
+
+			n, msg, _, err := codec.DecodeBytes(b)
+
+			return n, msg, err
+		},
+		"ReadFrame": func(r io.Reader) (int, Message, error) {
+			n, f, err := codec.ReadFrame(r)
+			if err != nil {
+				return n, nil, err
+			}
+
+			msg, err := f.Decode(ProtocolVersion, BaseEncoding)
+
+			return n, msg, err
+		},
+		"ReadFrameStreaming": func(r io.Reader) (int, Message, error) {
+			n, f, err := codec.ReadFrameStreaming(r)
+			if err != nil {
+				return n, nil, err
+			}
+
+			msg, err := f.Decode(ProtocolVersion, BaseEncoding)
+
+			return n, msg, err
+		},
+	}
+
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := read(bytes.NewReader(block))
+			require.NoError(t, err)
+
+			_, _, err = read(bytes.NewReader(corrupt))
+			require.ErrorIs(t, err, ErrChecksumMismatch)
+
+			_, _, err = read(bytes.NewReader(wrongNet))
+			require.ErrorIs(t, err, ErrWrongNetwork)
+
+			// Reads which fail before the header is complete are not
+			// observed.
+			_, _, err = read(bytes.NewReader(block[:10]))
+			require.Error(t, err)
+
+			events := rec.take()
+			require.Len(t, events, 3)
+
+			assert.Equal(t, CmdBlock, events[0].Command)
+			assert.Equal(t, DirectionRead, events[0].Direction)
+			assert.Equal(t, MessageHeaderSize, events[0].HeaderBytes)
+			assert.Equal(t, blockPayload, events[0].PayloadBytes)
+			assert.Positive(t, events[0].Duration)
+			require.NoError(t, events[0].Err)
+			assert.Zero(t, events[0].Kind)
+
+			assert.Equal(t, CmdBlock, events[1].Command)
+			assert.Equal(t, blockPayload, events[1].PayloadBytes)
+			require.ErrorIs(t, events[1].Err, ErrChecksumMismatch)
+			assert.Equal(t, ErrChecksumMismatch, events[1].Kind)
+
+			assert.Equal(t, CmdPing, events[2].Command)
+			assert.Equal(t, ErrWrongNetwork, events[2].Kind)
+		})
+	}
+
+	codec.SetObserver(nil)
+
+	_, _, _, err := codec.Read(bytes.NewReader(block))
+	require.NoError(t, err)
+	assert.Empty(t, rec.take())
+}
+
+// TestObserverFrames ensures frames are observed exactly once, when they are
+// first consumed.
+func TestObserverFrames(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	ping := encodeFrame(t, codec, NewMsgPing(7))
+
+	var rec eventRecorder
+
+	codec.SetObserver(&rec)
+
+	_, f, err := codec.ReadFrame(bytes.NewReader(ping))
+	require.NoError(t, err)
+	assert.Empty(t, rec.take())
+
+	for range 2 {
+		_, err = f.Decode(ProtocolVersion, BaseEncoding)
+		require.NoError(t, err)
+	}
+
+	events := rec.take()
+	require.Len(t, events, 1)
+	assert.Equal(t, CmdPing, events[0].Command)
+	assert.Equal(t, MessageHeaderSize, events[0].HeaderBytes)
+	assert.Equal(t, 8, events[0].PayloadBytes)
+
+	_, f, err = codec.ReadFrameStreaming(bytes.NewReader(ping))
+	require.NoError(t, err)
+
+	n, err := f.Discard()
+	require.NoError(t, err)
+	assert.Equal(t, 8, n)
+
+	_, err = f.Decode(ProtocolVersion, BaseEncoding)
+	require.ErrorIs(t, err, ErrUnsupported)
+
+	events = rec.take()
+	require.Len(t, events, 1)
+	assert.Equal(t, 8, events[0].PayloadBytes)
+	require.NoError(t, events[0].Err)
+
+	// Truncated payloads are observed as soon as ReadFrame fails.
+	_, _, err = codec.ReadFrame(bytes.NewReader(ping[:len(ping)-1]))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	events = rec.take()
+	require.Len(t, events, 1)
+	assert.Equal(t, 7, events[0].PayloadBytes)
+	require.ErrorIs(t, events[0].Err, io.ErrUnexpectedEOF)
+}
+
+// TestObserverWrites ensures observers are notified of the messages written on
+// every write path with their sizes and errors.
+func TestObserverWrites(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	ping := encodeFrame(t, codec, NewMsgPing(7))
+
+	var rec eventRecorder
+
+	codec.SetObserver(&rec)
+
+	writes := map[string]func(w io.Writer, msg Message) (int, error){
+		"Write":          codec.Write,
+		"WriteStreaming": codec.WriteStreaming,
+		"WriteBatch": func(w io.Writer, msg Message) (int, error) {
+			counts, err := codec.WriteBatch(w, msg)
+			if len(counts) == 0 {
+				return 0, err
+			}
+
+			return counts[0], err
+		},
+	}
+
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			n, err := write(io.Discard, NewMsgPing(7))
+			require.NoError(t, err)
+			assert.Equal(t, len(ping), n)
+
+			_, err = write(io.Discard, &fakeMessage{command: "toolongcommandname"})
+			require.ErrorIs(t, err, ErrInvalidCommand)
+
+			events := rec.take()
+			require.Len(t, events, 2)
+
+			assert.Equal(t, CmdPing, events[0].Command)
+			assert.Equal(t, DirectionWrite, events[0].Direction)
+			assert.Equal(t, MessageHeaderSize, events[0].HeaderBytes)
+			assert.Equal(t, len(ping)-MessageHeaderSize, events[0].PayloadBytes)
+			require.NoError(t, events[0].Err)
+
+			assert.Equal(t, 0, events[1].HeaderBytes+events[1].PayloadBytes)
+			assert.Equal(t, ErrInvalidCommand, events[1].Kind)
+		})
+	}
+
+	// Extended headers are attributed to the header.
+	extCodec := NewCodec(MainNet, ProtocolVersion)
+	extCodec.SetExtendedMessages(true)
+	extCodec.SetObserver(&rec)
+
+	n, err := extCodec.Write(io.Discard, NewMsgPing(7))
+	require.NoError(t, err)
+
+	events := rec.take()
+	require.Len(t, events, 1)
+	assert.Equal(t, ExtendedMessageHeaderSize, events[0].HeaderBytes)
+	assert.Equal(t, n-ExtendedMessageHeaderSize, events[0].PayloadBytes)
+
+	// Failed batch writes are attributed to the message identified by the
+	// batch error.
+	_, err = codec.WriteBatch(erroringWriter{}, NewMsgPing(7), NewMsgPing(8))
+	require.Error(t, err)
+
+	events = rec.take()
+	require.Len(t, events, 2)
+	require.NoError(t, events[0].Err)
+	require.Error(t, events[1].Err)
+	assert.Equal(t, len(ping)-MessageHeaderSize, events[1].PayloadBytes)
+}
+
+// TestDirectionStringer tests the stringized output for the Direction type.
+func TestDirectionStringer(t *testing.T) {
+	assert.Equal(t, "read", DirectionRead.String())
+	assert.Equal(t, "write", DirectionWrite.String())
+	assert.Equal(t, "Unknown Direction (9)", Direction(9).String())
+}
@@ -0,0 +1,397 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+// AssociationIDTypeUUID is the type byte of association IDs made of a UUID,
+// which is the only type in use.
+const AssociationIDTypeUUID byte = 0x01
+
+var (
+	// ErrAssociationClosed is returned when using an association, or one
+	// of its streams, which has been torn down.
+	ErrAssociationClosed = errors.New("association closed")
+
+	// ErrUnknownAssociation is returned when a createstrm message refers
+	// to an association which does not exist.
+	ErrUnknownAssociation = errors.New("unknown association")
+
+	// ErrInvalidStreamType is returned when a createstrm message asks for
+	// a stream type other than the data stream types.
+	ErrInvalidStreamType = errors.New("invalid stream type")
+
+	// ErrDuplicateStream is returned when a createstrm message asks for a
+	// stream the association already has.
+	ErrDuplicateStream = errors.New("duplicate stream")
+
+	// ErrStreamRejected is returned when the remote peer rejects a
+	// createstrm message.
+	ErrStreamRejected = errors.New("stream rejected")
+
+	// ErrAssociationMismatch is returned when the remote peer confirms an
+	// association or a stream other than the one requested.
+	ErrAssociationMismatch = errors.New("association mismatch")
+)
+
+// NewAssociationID returns a new random association ID made of the
+// AssociationIDTypeUUID type byte followed by a version 4 UUID.
+func NewAssociationID() ([]byte, error) {
+	id := make([]byte, 17)
+	id[0] = AssociationIDTypeUUID
+
+	if _, err := rand.Read(id[1:]); err != nil {
+		return nil, err
+	}
+
+	// Set the version and variant bits of the UUID as per RFC 9562.
+	id[7] = id[7]&0x0f | 0x40
+	id[9] = id[9]&0x3f | 0x80
+
+	return id, nil
+}
+
+// isDataStream returns whether t is one of the data stream types which extra
+// connections of an association carry.
+func isDataStream(t wire.StreamType) bool {
+	return t >= wire.StreamTypeData1 && t <= wire.StreamTypeData4
+}
+
+// Stream is one connection of an association.  Every association has a
+// general stream, the connection the version handshake took place on, and
+// may have up to one data stream of each data stream type.
+type Stream struct {
+	assoc  *Association
+	typ    wire.StreamType
+	policy string
+	conn   net.Conn
+	codec  *wire.Codec
+	wmtx   sync.Mutex
+}
+
+// Association returns the association the stream belongs to.
+func (s *Stream) Association() *Association {
+	return s.assoc
+}
+
+// Type returns the type of the stream.
+func (s *Stream) Type() wire.StreamType {
+	return s.typ
+}
+
+// Policy returns the name of the stream policy the stream was created for.
+// It is empty for the general stream.
+func (s *Stream) Policy() string {
+	return s.policy
+}
+
+// Conn returns the connection of the stream.
+func (s *Stream) Conn() net.Conn {
+	return s.conn
+}
+
+// Codec returns the codec messages are read and written with on the stream.
+// It is configured with the negotiated settings of the association.
+func (s *Stream) Codec() *wire.Codec {
+	return s.codec
+}
+
+// ReadMessage reads the next message from the stream.  It must not be called
+// concurrently.
+//
+// A read which times out before any byte of the message has been read, as
+// reported by a *wire.TimeoutError in wire.StageHeader, leaves the stream
+// usable.  Any other failure closes the stream, and when it is the general
+// stream the whole association is torn down.
+func (s *Stream) ReadMessage(ctx context.Context) (wire.Message, error) {
+	n, msg, _, err := s.codec.ReadContext(ctx, s.conn)
+	if err != nil {
+		return nil, s.failed(n, wire.StageHeader, err)
+	}
+
+	return msg, nil
+}
+
+// WriteMessage writes msg to the stream.  It is safe for concurrent access.
+//
+// A write which times out before any byte has been written leaves the stream
+// usable.  Any other failure closes the stream, and when it is the general
+// stream the whole association is torn down.
+func (s *Stream) WriteMessage(ctx context.Context, msg wire.Message) error {
+	s.wmtx.Lock()
+	n, err := s.codec.WriteContext(ctx, s.conn, msg)
+	s.wmtx.Unlock()
+
+	if err != nil {
+		return s.failed(n, wire.StageWrite, err)
+	}
+
+	return nil
+}
+
+// Close closes the stream.  Closing the general stream tears down the whole
+// association.
+func (s *Stream) Close() error {
+	s.assoc.removeStream(s, ErrAssociationClosed)
+	return nil
+}
+
+// failed closes the stream after an operation which processed n bytes failed
+// with err, unless it timed out in stage without touching the stream.  It
+// returns err, or ErrAssociationClosed when the association was torn down
+// beforehand.
+func (s *Stream) failed(n int, stage wire.MessageStage, err error) error {
+	var te *wire.TimeoutError
+	if n == 0 && errors.As(err, &te) && te.Stage == stage {
+		return err
+	}
+
+	if cause := s.assoc.Err(); cause != nil {
+		return fmt.Errorf("%w: %w", ErrAssociationClosed, cause)
+	}
+
+	s.assoc.removeStream(s, err)
+
+	return err
+}
+
+// Association ties the streams of a multistream association to one logical
+// peer.  The association lives as long as its general stream: when the
+// general stream fails or is closed, every stream is closed.  A data stream
+// which fails is closed on its own, and traffic for it should fall back to the
+// general stream.
+//
+// Associations with peers which do not support multistreams only have a
+// general stream.
+//
+// WriteMessage routes each outbound message to a stream according to the
+// stream policy of the association, see StreamPolicy.
+//
+// All methods are safe for concurrent access.
+type Association struct {
+	id      []byte
+	peer    *NegotiatedPeer
+	general *Stream
+	onClose func(*Association)
+
+	mtx     sync.Mutex
+	streams map[wire.StreamType]*Stream
+	policy  *StreamPolicy
+	err     error
+	done    chan struct{}
+}
+
+// newAssociation returns a new association with id and the general stream
+// on conn, negotiated as summarised by np.  onClose is called once when the
+// association is torn down.
+func newAssociation(id []byte, np *NegotiatedPeer, conn net.Conn, codec *wire.Codec,
+	onClose func(*Association),
+) *Association {
+	a := &Association{
+		id:      id,
+		peer:    np,
+		onClose: onClose,
+		streams: make(map[wire.StreamType]*Stream),
+		done:    make(chan struct{}),
+	}
+
+	a.general = &Stream{
+		assoc: a,
+		typ:   wire.StreamTypeGeneral,
+		conn:  conn,
+		codec: codec,
+	}
+	a.streams[wire.StreamTypeGeneral] = a.general
+
+	return a
+}
+
+// ID returns the association ID, or nil when the remote peer does not support
+// multistreams.
+func (a *Association) ID() []byte {
+	return a.id
+}
+
+// Peer returns the summary of the version handshake on the general stream.
+func (a *Association) Peer() *NegotiatedPeer {
+	return a.peer
+}
+
+// Multistream returns whether the remote peer supports multistreams, that is
+// whether the association may have data streams.
+func (a *Association) Multistream() bool {
+	return len(a.id) != 0
+}
+
+// General returns the general stream.  It is returned even once the
+// association has been torn down, in which case using it fails.
+func (a *Association) General() *Stream {
+	return a.general
+}
+
+// Stream returns the open stream of type t, or nil when there is none.
+func (a *Association) Stream(t wire.StreamType) *Stream {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	return a.streams[t]
+}
+
+// Streams returns the open streams ordered by type.
+func (a *Association) Streams() []*Stream {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	streams := make([]*Stream, 0, len(a.streams))
+	for _, s := range a.streams {
+		streams = append(streams, s)
+	}
+
+	slices.SortFunc(streams, func(x, y *Stream) int {
+		return int(x.typ) - int(y.typ)
+	})
+
+	return streams
+}
+
+// Policy returns the stream policy which routes the outbound messages of the
+// association.  It is the wire.DefaultStreamPolicy policy until one has been
+// agreed on.
+func (a *Association) Policy() *StreamPolicy {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.policy == nil {
+		return defaultPolicy
+	}
+
+	return a.policy
+}
+
+// StreamFor returns the stream which carries msg under the stream policy of
+// the association.  Messages routed to a data stream the association does not
+// have, or no longer has, fall back to the general stream.
+func (a *Association) StreamFor(msg wire.Message) *Stream {
+	t := a.Policy().StreamType(ClassOf(msg))
+
+	if t != wire.StreamTypeGeneral {
+		if s := a.Stream(t); s != nil {
+			return s
+		}
+	}
+
+	return a.general
+}
+
+// WriteMessage writes msg to the stream returned by StreamFor, so that it
+// only queues behind messages the stream policy routes alike.
+func (a *Association) WriteMessage(ctx context.Context, msg wire.Message) error {
+	return a.StreamFor(msg).WriteMessage(ctx, msg)
+}
+
+// Done returns a channel which is closed once the association has been torn
+// down.
+func (a *Association) Done() <-chan struct{} {
+	return a.done
+}
+
+// Err returns the reason the association was torn down, or nil while it is
+// alive.  It is ErrAssociationClosed when the association was closed with
+// Close.
+func (a *Association) Err() error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	return a.err
+}
+
+// Close tears down the association, closing every stream.
+func (a *Association) Close() error {
+	a.teardown(ErrAssociationClosed)
+	return nil
+}
+
+// addStream adds the data stream s, failing when the association already has
+// a stream of its type or has been torn down.
+func (a *Association) addStream(s *Stream) error {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.err != nil {
+		return ErrAssociationClosed
+	}
+
+	if _, ok := a.streams[s.typ]; ok {
+		return fmt.Errorf("%w: %v", ErrDuplicateStream, s.typ)
+	}
+
+	a.streams[s.typ] = s
+
+	return nil
+}
+
+// setPolicy sets the stream policy of the association to the one registered
+// under name, unless a policy has already been set.
+func (a *Association) setPolicy(name string) {
+	p := resolveStreamPolicy(name)
+
+	a.mtx.Lock()
+	if a.policy == nil {
+		a.policy = p
+	}
+	a.mtx.Unlock()
+}
+
+// removeStream closes s, tearing down the association with err as the reason
+// when s is the general stream.
+func (a *Association) removeStream(s *Stream, err error) {
+	if s.typ == wire.StreamTypeGeneral {
+		a.teardown(err)
+		return
+	}
+
+	a.mtx.Lock()
+	if a.streams[s.typ] == s {
+		delete(a.streams, s.typ)
+	}
+	a.mtx.Unlock()
+
+	_ = s.conn.Close()
+}
+
+// teardown closes every stream with err as the reason unless the association
+// has already been torn down.
+func (a *Association) teardown(err error) {
+	a.mtx.Lock()
+	if a.err != nil {
+		a.mtx.Unlock()
+		return
+	}
+
+	a.err = err
+	streams := a.streams
+	a.streams = nil
+	a.mtx.Unlock()
+
+	for _, s := range streams {
+		_ = s.conn.Close()
+	}
+
+	close(a.done)
+
+	if a.onClose != nil {
+		a.onClose(a)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+// TestNewAssociationID ensures association IDs are random version 4 UUIDs
+// prefixed with their type.
+func TestNewAssociationID(t *testing.T) {
+	id, err := NewAssociationID()
+	require.NoError(t, err)
+	require.Len(t, id, 17)
+	assert.Equal(t, AssociationIDTypeUUID, id[0])
+	assert.Equal(t, byte(0x40), id[7]&0xf0)
+	assert.Equal(t, byte(0x80), id[9]&0xc0)
+
+	other, err := NewAssociationID()
+	require.NoError(t, err)
+	assert.NotEqual(t, id, other)
+}
+
+// newPipeAssociation returns an association whose general stream and data
+// stream of type Data1 are net.Pipe connections, along with the remote ends.
+func newPipeAssociation(t *testing.T) (*Association, net.Conn, net.Conn) {
+	t.Helper()
+
+	general, remoteGeneral := net.Pipe()
+	data, remoteData := net.Pipe()
+
+	t.Cleanup(func() {
+		_ = remoteGeneral.Close()
+		_ = remoteData.Close()
+	})
+
+	id, err := NewAssociationID()
+	require.NoError(t, err)
+
+	np := &NegotiatedPeer{ProtocolVersion: wire.ProtocolVersion}
+	codec := wire.NewCodec(wire.MainNet, wire.ProtocolVersion)
+
+	a := newAssociation(id, np, general, codec, nil)
+	require.NoError(t, a.addStream(&Stream{assoc: a, typ: wire.StreamTypeData1, conn: data, codec: codec}))
+
+	err = a.addStream(&Stream{assoc: a, typ: wire.StreamTypeData1, conn: data, codec: codec})
+	require.ErrorIs(t, err, ErrDuplicateStream)
+
+	return a, remoteGeneral, remoteData
+}
+
+// TestAssociationStreamFailures ensures a timed out read leaves a stream
+// usable, a failed data stream is dropped on its own and a failed general
+// stream tears down the association.
+func TestAssociationStreamFailures(t *testing.T) {
+	a, remoteGeneral, remoteData := newPipeAssociation(t)
+	data := a.Stream(wire.StreamTypeData1)
+
+	// Nothing has been read, so the stream stays usable.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := data.ReadMessage(ctx)
+
+	var te *wire.TimeoutError
+	require.ErrorAs(t, err, &te)
+	assert.Same(t, data, a.Stream(wire.StreamTypeData1))
+
+	// Failures past the first byte close the stream.
+	go func() {
+		_, _ = remoteData.Write([]byte{0x01, 0x02})
+		_ = remoteData.Close()
+	}()
+
+	_, err = data.ReadMessage(context.Background())
+	require.Error(t, err)
+	assert.Nil(t, a.Stream(wire.StreamTypeData1))
+	require.NoError(t, a.Err())
+
+	select {
+	case <-a.Done():
+		require.FailNow(t, "association torn down by a data stream")
+	default:
+	}
+
+	// The general stream takes the association with it.
+	_ = remoteGeneral.Close()
+
+	_, cause := a.General().ReadMessage(context.Background())
+	require.Error(t, cause)
+	require.ErrorIs(t, a.Err(), cause)
+	<-a.Done()
+
+	err = a.General().WriteMessage(context.Background(), wire.NewMsgPing(1))
+	require.ErrorIs(t, err, ErrAssociationClosed)
+
+	err = a.addStream(&Stream{assoc: a, typ: wire.StreamTypeData2})
+	require.ErrorIs(t, err, ErrAssociationClosed)
+
+	// Tearing down is idempotent.
+	require.NoError(t, a.Close())
+	require.ErrorIs(t, a.Err(), cause)
+}
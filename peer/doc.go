@@ -0,0 +1,159 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package peer implements the connection-level behaviour shared by every node
+speaking the bitcoin protocol on top of the messages provided by package wire.
+
+# Version Handshake
+
+Before any other message may be exchanged, both ends of a connection perform
+the version handshake:
+
+	Outbound                              Inbound
+	----------------------------------------------------------------------------
+	version message (MsgVersion)  ---->
+	                              <----   version message (MsgVersion)
+	                              <----   verack message (MsgVerAck)
+	                              <----   sendheaders, sendcmpct, feefilter
+	                                      messages (optional)
+	                              <----   protoconf message (MsgProtoconf)
+	verack message (MsgVerAck)    ---->
+	sendheaders, sendcmpct,       ---->
+	feefilter messages (optional)
+	protoconf message             ---->
+
+The inbound side waits for the version of the outbound side before sending its
+own.  Each side answers the version it receives with a verack, the optional
+sendheaders, sendcmpct and feefilter messages it is configured with and, when
+the negotiated protocol version is at least wire.ProtoconfVersion, a protoconf
+advertising the largest payload it accepts and the stream policies it
+supports.  The handshake completes once the verack and, when expected, the
+protoconf of the remote peer have been received.  A remote peer which sends
+nothing within a short grace period after its verack is taken not to support
+protoconf.  The optional messages of the remote peer received on the way are
+handed to the caller.
+
+Handshake is a state machine which implements these rules without performing
+any I/O, which makes it usable with any transport.  Its Run method, and the
+Negotiate function, drive it over an io.ReadWriter such as a net.Conn:
+
+	np, err := peer.Negotiate(ctx, conn, &peer.Config{
+		Net:         wire.MainNet,
+		Services:    wire.SFNodeNetwork,
+		SendHeaders: true,
+	}, false)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	fmt.Printf("connected to %s at protocol version %d\n", np.UserAgent,
+		np.ProtocolVersion)
+
+The result of a successful handshake is a NegotiatedPeer which summarises what
+both sides agreed on.  Messages which are out of order, versions older than
+the configured minimum and connections to the local node itself, detected by
+a version nonce the local node sent, are rejected with the errors listed
+below.  Since the handshake is subject to a timeout, a peer which stalls does
+not hold the connection open indefinitely.
+
+# Multistream Associations
+
+BSV peers may spread their traffic over several TCP connections, called
+streams, which together form an association.  The connection the version
+handshake takes place on is the general stream.  The connecting side
+advertises a new association ID in its version message, which the accepting
+side confirms by advertising the same ID in its own.  The connecting side
+then opens a connection for each data stream (wire.StreamTypeData1 to
+wire.StreamTypeData4) its stream policy routes messages to, whose first
+message is a createstrm message (MsgCreateStream) referring to the
+association, which the accepting side answers with a streamack message
+(MsgStreamAck) or a reject message.
+
+AssociationManager drives this protocol on both sides.  Connect establishes
+an outbound association and Accept handles each inbound connection, whether
+it starts a new association or joins an existing one:
+
+	m, err := peer.NewAssociationManager(&peer.ManagerConfig{
+		Handshake: peer.Config{Net: wire.MainNet},
+	})
+	...
+	a, err := m.Connect(ctx, "203.0.113.1:8333")
+	if err != nil {
+		return err
+	}
+	err = a.WriteMessage(ctx, msg)
+
+An association lives as long as its general stream.  A data stream which
+fails, or which Connect cannot open, is left out on its own, while a general
+stream which fails or is closed tears down every stream of the association.
+Failures are noticed by the ReadMessage and WriteMessage methods of the
+streams, so every stream should have a goroutine reading from it.
+
+# Stream Policies
+
+Stream policies decide which stream of an association carries each outbound
+message.  Both sides advertise the names of the policies they support in their
+protoconf message, and the connecting side creates its data streams for the
+first policy of its own list which the accepting side also supports, as
+returned by SelectStreamPolicy.  The accepting side adopts the policy the data
+streams are created for when it supports it, and otherwise selects one the
+same way.  The WriteMessage method of an association
+routes messages accordingly, by message class, to the general stream or a data
+stream.  Messages routed to a data stream the association lacks go on the
+general stream.
+
+Two policies are provided: wire.DefaultStreamPolicy carries everything on the
+general stream, while wire.BlockPriorityStreamPolicy carries blocks, compact
+blocks and headers on wire.StreamTypeData1 so that they never queue behind
+transactions.  Further policies can be registered with RegisterStreamPolicy:
+
+	err := peer.RegisterStreamPolicy(&peer.StreamPolicy{
+		Name: "TxSplit",
+		Routes: map[peer.MessageClass]wire.StreamType{
+			peer.ClassBlock: wire.StreamTypeData1,
+			peer.ClassTx:    wire.StreamTypeData2,
+		},
+	})
+
+# Keepalive
+
+Keepalive pings the remote peer at regular intervals, tracks the last, minimum
+and smoothed round-trip times of the pings, and answers the pings of the
+remote peer.  A remote peer which does not answer a ping in time is reported
+to the OnStall callback and ends Run with an error wrapping ErrPingTimeout.
+Keepalive does not read from the connection, so the goroutine reading it hands
+every message over:
+
+	k := peer.NewKeepalive(&peer.KeepaliveConfig{}, a.Peer().ProtocolVersion)
+	go func() {
+		if err := k.Run(ctx, a); errors.Is(err, peer.ErrPingTimeout) {
+			a.Close()
+		}
+	}()
+	for {
+		msg, err := a.General().ReadMessage(ctx)
+		if err != nil {
+			return err
+		}
+		if k.HandleMessage(msg) {
+			continue
+		}
+		...
+	}
+
+Peers at protocol version wire.BIP0031Version or older do not answer pings,
+so they are pinged merely to keep the connection alive.
+
+# Errors
+
+Failures caused by the remote peer wrap one of the sentinel errors
+ErrUnexpectedMessage, ErrObsoleteVersion, ErrSelfConnection,
+ErrInvalidProtoconf, ErrUnknownAssociation, ErrInvalidStreamType,
+ErrDuplicateStream, ErrStreamRejected, ErrAssociationMismatch and
+ErrPingTimeout, which can be tested with errors.Is.  Errors reading and writing
+messages are returned as they are by the wire package, for example a
+*wire.TimeoutError when the handshake does not complete in time.
+*/
+package peer
@@ -0,0 +1,807 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+const (
+	// DefaultMinProtocolVersion is the lowest protocol version accepted
+	// from remote peers unless configured otherwise.
+	DefaultMinProtocolVersion = wire.MultipleAddressVersion
+
+	// DefaultHandshakeTimeout is the time the handshake may take unless
+	// configured otherwise.
+	DefaultHandshakeTimeout = 30 * time.Second
+
+	// DefaultProtoconfTimeout is the time the remote peer may take to send
+	// its protoconf message after its verack unless configured otherwise.
+	DefaultProtoconfTimeout = time.Second
+
+	// LegacyMaxRecvPayloadLength is the largest payload accepted by peers
+	// which do not send a protoconf message.  It is also the smallest
+	// value a protoconf message may advertise.
+	LegacyMaxRecvPayloadLength uint32 = 1024 * 1024
+
+	// maxHandshakeMessages is the largest number of messages a handshake
+	// sends: version, verack, protoconf, sendheaders, sendcmpct and
+	// feefilter.
+	maxHandshakeMessages = 6
+)
+
+var (
+	// ErrUnexpectedMessage is returned when the remote peer sends a
+	// message which is not allowed at the current stage of the handshake,
+	// such as anything other than a version message first or a second
+	// verack.
+	ErrUnexpectedMessage = errors.New("unexpected message")
+
+	// ErrObsoleteVersion is returned when the remote peer advertises a
+	// protocol version lower than the configured minimum.
+	ErrObsoleteVersion = errors.New("obsolete protocol version")
+
+	// ErrSelfConnection is returned when the remote peer sends a version
+	// nonce the local node sent, which means it is connected to itself.
+	ErrSelfConnection = errors.New("connected to self")
+
+	// ErrInvalidProtoconf is returned when the remote peer sends a
+	// protoconf message with values no correct implementation sends.
+	ErrInvalidProtoconf = errors.New("invalid protoconf")
+
+	// ErrHandshakeFinished is returned when a message is passed to a
+	// handshake which has already completed or failed.
+	ErrHandshakeFinished = errors.New("handshake already finished")
+)
+
+// State is the stage a Handshake is at.
+type State uint8
+
+// These constants define the states of a Handshake in the order they are
+// reached.
+const (
+	// StateAwaitingVersion is the initial state in which the version of
+	// the remote peer has not been received yet.
+	StateAwaitingVersion State = iota
+
+	// StateAwaitingVerAck is the state in which the version of the remote
+	// peer has been received and answered, and its verack is expected.
+	StateAwaitingVerAck
+
+	// StateAwaitingProtoconf is the state in which the verack of the
+	// remote peer has been received and, since the negotiated protocol
+	// version supports it, its protoconf is expected.
+	StateAwaitingProtoconf
+
+	// StateComplete is the state of a handshake which has succeeded.
+	StateComplete
+
+	// StateFailed is the state of a handshake which has failed or has
+	// been aborted.
+	StateFailed
+)
+
+// Map of states back to their constant names for pretty printing.
+var stateStrings = map[State]string{
+	StateAwaitingVersion:   "StateAwaitingVersion",
+	StateAwaitingVerAck:    "StateAwaitingVerAck",
+	StateAwaitingProtoconf: "StateAwaitingProtoconf",
+	StateComplete:          "StateComplete",
+	StateFailed:            "StateFailed",
+}
+
+// String returns the State in human-readable form.
+func (s State) String() string {
+	if str, ok := stateStrings[s]; ok {
+		return str
+	}
+
+	return fmt.Sprintf("Unknown State (%d)", uint8(s))
+}
+
+// Config holds the settings the local node advertises during the handshake.
+// The zero value of every field is valid and selects the documented default.
+type Config struct {
+	// Net is the bitcoin network the connection is on.
+	Net wire.BitcoinNet
+
+	// ProtocolVersion is the highest protocol version the local node
+	// supports.  Zero means wire.ProtocolVersion.
+	ProtocolVersion uint32
+
+	// MinProtocolVersion is the lowest protocol version accepted from the
+	// remote peer.  Zero means DefaultMinProtocolVersion.
+	MinProtocolVersion uint32
+
+	// Services are the services the local node advertises.
+	Services wire.ServiceFlag
+
+	// UserAgent is the user agent the local node advertises.  Empty means
+	// wire.DefaultUserAgent.
+	UserAgent string
+
+	// LastBlock is the height of the best block of the local node.
+	LastBlock int32
+
+	// DisableRelayTx asks the remote peer not to announce transactions.
+	DisableRelayTx bool
+
+	// AssociationID is the multistream association ID the local node
+	// advertises.  Empty means the local node does not support
+	// multistreams.
+	AssociationID []byte
+
+	// EchoAssociationID makes an inbound handshake advertise the
+	// association ID of the remote peer instead of AssociationID, which is
+	// how the accepting side of a multistream association confirms it.
+	EchoAssociationID bool
+
+	// LocalAddr and RemoteAddr are the addresses of the local node and the
+	// remote peer advertised in the version message.  Nil means an
+	// unspecified address.
+	LocalAddr  *wire.NetAddress
+	RemoteAddr *wire.NetAddress
+
+	// MaxRecvPayloadLength is the largest payload the local node accepts,
+	// advertised in its protoconf message.  Zero means
+	// wire.DefaultMaxRecvPayloadLength.
+	MaxRecvPayloadLength uint32
+
+	// StreamPolicies are the names of the stream policies the local node
+	// supports in order of preference, advertised in its protoconf
+	// message.  Nil means only wire.DefaultStreamPolicy.
+	StreamPolicies []string
+
+	// SendHeaders asks the remote peer to announce blocks with headers
+	// rather than inventory once the handshake has completed.
+	SendHeaders bool
+
+	// SendCmpct asks the remote peer to announce blocks with compact
+	// blocks once the handshake has completed.
+	SendCmpct bool
+
+	// FeeFilter, when positive, is the minimum fee rate in satoshis per
+	// kilobyte of the transactions the remote peer should announce.
+	FeeFilter int64
+
+	// Timeout is the time the handshake may take when driven by Run.
+	// Zero means DefaultHandshakeTimeout.
+	Timeout time.Duration
+
+	// ProtoconfTimeout is the time the remote peer may take to send its
+	// protoconf message after its verack before Run completes the
+	// handshake as if the remote peer does not send one.  Zero means
+	// DefaultProtoconfTimeout.
+	ProtoconfTimeout time.Duration
+
+	// Nonces holds the version nonces of the handshakes in progress on the
+	// local node.  Handshakes sharing it detect connections from the
+	// local node to itself, so every handshake of a node should use the
+	// same NonceSet.  Nil means only the nonce of the handshake itself is
+	// checked.
+	Nonces *NonceSet
+}
+
+// NegotiatedPeer summarises the outcome of a successful handshake.
+type NegotiatedPeer struct {
+	// Inbound is whether the remote peer initiated the connection.
+	Inbound bool
+
+	// ProtocolVersion is the protocol version both peers use, that is
+	// the lower of the versions they advertised.
+	ProtocolVersion uint32
+
+	// Services are the services the remote peer advertised.
+	Services wire.ServiceFlag
+
+	// UserAgent is the user agent the remote peer advertised.
+	UserAgent string
+
+	// LastBlock is the height of the best block of the remote peer when
+	// it sent its version.
+	LastBlock int32
+
+	// RelayTx is whether the remote peer wants transactions announced to
+	// it.
+	RelayTx bool
+
+	// AssociationID is the multistream association ID the remote peer
+	// advertised, or nil when it does not support multistreams.
+	AssociationID []byte
+
+	// MaxRecvPayloadLength is the largest payload the remote peer accepts.
+	// It is LegacyMaxRecvPayloadLength when the remote peer did not send a
+	// protoconf message.
+	MaxRecvPayloadLength uint32
+
+	// StreamPolicies are the names of the stream policies the remote peer
+	// supports, or only wire.DefaultStreamPolicy when it did not send a
+	// protoconf message.
+	StreamPolicies []string
+
+	// Version is the version message the remote peer sent.
+	Version *wire.MsgVersion
+
+	// Pending holds the messages the remote peer sent during the handshake
+	// which the handshake does not consume, such as its sendheaders and
+	// feefilter messages, in the order they were received.  They should
+	// be processed before any further message is read.
+	Pending []wire.Message
+}
+
+// Configure applies the negotiated protocol version and the maximum payload
+// length of the remote peer to codec, so messages are read and written the way
+// the remote peer expects.
+func (np *NegotiatedPeer) Configure(codec *wire.Codec) {
+	codec.SetProtocolVersion(np.ProtocolVersion)
+	codec.SetMaxRecvPayloadLength(uint64(np.MaxRecvPayloadLength))
+}
+
+// NonceSet is a set of version nonces shared by the handshakes of a node to
+// detect connections to itself.  It is safe for concurrent access.
+type NonceSet struct {
+	mtx    sync.Mutex
+	nonces map[uint64]struct{}
+}
+
+// NewNonceSet returns a new empty NonceSet.
+func NewNonceSet() *NonceSet {
+	return &NonceSet{nonces: make(map[uint64]struct{})}
+}
+
+// add adds nonce to the set.
+func (s *NonceSet) add(nonce uint64) {
+	s.mtx.Lock()
+	s.nonces[nonce] = struct{}{}
+	s.mtx.Unlock()
+}
+
+// remove removes nonce from the set.
+func (s *NonceSet) remove(nonce uint64) {
+	s.mtx.Lock()
+	delete(s.nonces, nonce)
+	s.mtx.Unlock()
+}
+
+// Contains returns whether nonce was sent by a handshake in progress.
+func (s *NonceSet) Contains(nonce uint64) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	_, ok := s.nonces[nonce]
+
+	return ok
+}
+
+// Handshake is the state machine of the version handshake on one connection.
+// It performs no I/O: Start returns the messages to send when the connection
+// is established and Receive processes each message received from the remote
+// peer and returns the messages to send in response.  The messages must be
+// sent in the order they are returned.  Run drives a Handshake over an
+// io.ReadWriter.
+//
+// A Handshake is not safe for concurrent access.
+type Handshake struct {
+	cfg     Config
+	inbound bool
+	nonce   uint64
+	started bool
+	state   State
+	pver    uint32
+	peer    NegotiatedPeer
+}
+
+// NewHandshake returns a new Handshake which advertises the settings of cfg.
+// inbound is whether the remote peer initiated the connection, in which case
+// the version of the remote peer is awaited before the local one is sent.
+func NewHandshake(cfg *Config, inbound bool) (*Handshake, error) {
+	c := *cfg
+	if c.ProtocolVersion == 0 {
+		c.ProtocolVersion = wire.ProtocolVersion
+	}
+
+	if c.MinProtocolVersion == 0 {
+		c.MinProtocolVersion = DefaultMinProtocolVersion
+	}
+
+	if c.UserAgent == "" {
+		c.UserAgent = wire.DefaultUserAgent
+	}
+
+	if c.MaxRecvPayloadLength == 0 {
+		c.MaxRecvPayloadLength = wire.DefaultMaxRecvPayloadLength
+	}
+
+	if c.StreamPolicies == nil {
+		c.StreamPolicies = []string{wire.DefaultStreamPolicy}
+	}
+
+	if c.Timeout == 0 {
+		c.Timeout = DefaultHandshakeTimeout
+	}
+
+	if c.ProtoconfTimeout == 0 {
+		c.ProtoconfTimeout = DefaultProtoconfTimeout
+	}
+
+	switch {
+	case len(c.UserAgent) > wire.MaxUserAgentLen:
+		return nil, fmt.Errorf("user agent is longer than %d bytes", wire.MaxUserAgentLen)
+
+	case len(c.AssociationID) > wire.MaxAssociationIDLen:
+		return nil, fmt.Errorf("association ID is longer than %d bytes", wire.MaxAssociationIDLen)
+
+	case len(c.StreamPolicies) > wire.MaxNumStreamPolicies:
+		return nil, fmt.Errorf("more than %d stream policies", wire.MaxNumStreamPolicies)
+
+	case c.MaxRecvPayloadLength < LegacyMaxRecvPayloadLength:
+		return nil, fmt.Errorf("maximum receive payload length is less than %d",
+			LegacyMaxRecvPayloadLength)
+	}
+
+	nonce, err := wire.RandomUint64()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handshake{
+		cfg:     c,
+		inbound: inbound,
+		nonce:   nonce,
+		pver:    c.ProtocolVersion,
+	}, nil
+}
+
+// Nonce returns the nonce of the version message the handshake sends.
+func (h *Handshake) Nonce() uint64 {
+	return h.nonce
+}
+
+// State returns the stage the handshake is at.
+func (h *Handshake) State() State {
+	return h.state
+}
+
+// ProtocolVersion returns the protocol version messages must be read and
+// written with at the current stage of the handshake.  It is the local
+// protocol version until the version of the remote peer is received, and the
+// negotiated one from then on.
+func (h *Handshake) ProtocolVersion() uint32 {
+	return h.pver
+}
+
+// Peer returns the summary of the handshake once it has completed, and nil
+// before.
+func (h *Handshake) Peer() *NegotiatedPeer {
+	if h.state != StateComplete {
+		return nil
+	}
+
+	return &h.peer
+}
+
+// Start starts the handshake and returns the messages to send, which is the
+// version message for outbound connections and nothing for inbound ones.  It
+// must be called once before Receive.
+func (h *Handshake) Start() []wire.Message {
+	if h.started {
+		return nil
+	}
+
+	h.started = true
+
+	if h.cfg.Nonces != nil {
+		h.cfg.Nonces.add(h.nonce)
+	}
+
+	if h.inbound {
+		return nil
+	}
+
+	return []wire.Message{h.versionMsg()}
+}
+
+// Abort marks the handshake as failed unless it has already finished.  It
+// must be called when a handshake is abandoned, for example because the
+// connection failed, so its nonce is no longer considered.
+func (h *Handshake) Abort() {
+	if h.state != StateComplete {
+		h.finish(StateFailed)
+	}
+}
+
+// Receive processes msg received from the remote peer and returns the
+// messages to send in response.  Any error fails the handshake, after which
+// the connection should be closed.
+func (h *Handshake) Receive(msg wire.Message) ([]wire.Message, error) {
+	if h.state == StateComplete || h.state == StateFailed {
+		return nil, ErrHandshakeFinished
+	}
+
+	if !h.started {
+		h.Start()
+	}
+
+	out, err := h.receive(msg)
+	if err != nil {
+		h.finish(StateFailed)
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// SkipProtoconf completes a handshake awaiting the protoconf message of the
+// remote peer as if the remote peer does not send one, so it uses the legacy
+// defaults.  Callers which drive the handshake themselves should call it when
+// no message has been received within Config.ProtoconfTimeout of the verack,
+// since a remote peer which does not support protoconf may send nothing
+// further until it hears from the local node.  A protoconf message received
+// afterwards is an ordinary message to the caller.
+func (h *Handshake) SkipProtoconf() error {
+	switch h.state {
+	case StateComplete, StateFailed:
+		return ErrHandshakeFinished
+
+	case StateAwaitingProtoconf:
+		h.complete()
+		return nil
+	}
+
+	return fmt.Errorf("protoconf is not awaited in %v", h.state)
+}
+
+// receive processes msg according to the state of the handshake.
+func (h *Handshake) receive(msg wire.Message) ([]wire.Message, error) {
+	switch m := msg.(type) {
+	case *wire.MsgVersion:
+		if h.state != StateAwaitingVersion {
+			return nil, h.unexpected(msg)
+		}
+
+		return h.receiveVersion(m)
+
+	case *wire.MsgVerAck:
+		if h.state != StateAwaitingVerAck {
+			return nil, h.unexpected(msg)
+		}
+
+		h.receiveVerAck()
+
+		return nil, nil
+
+	case *wire.MsgProtoconf:
+		if h.state != StateAwaitingProtoconf {
+			return nil, h.unexpected(msg)
+		}
+
+		return nil, h.receiveProtoconf(m)
+
+	case *wire.MsgSendHeaders, *wire.MsgSendcmpct, *wire.MsgFeeFilter:
+		// The remote peer may only send these once it has sent its
+		// verack.
+		if h.state != StateAwaitingProtoconf {
+			return nil, h.unexpected(msg)
+		}
+
+		h.peer.Pending = append(h.peer.Pending, msg)
+
+		return nil, nil
+	}
+
+	// A remote peer which moves on to other messages after its verack
+	// does not send a protoconf message.
+	if h.state != StateAwaitingProtoconf {
+		return nil, h.unexpected(msg)
+	}
+
+	h.peer.Pending = append(h.peer.Pending, msg)
+	h.complete()
+
+	return nil, nil
+}
+
+// receiveVersion validates the version of the remote peer and answers it.
+func (h *Handshake) receiveVersion(msg *wire.MsgVersion) ([]wire.Message, error) {
+	if msg.ProtocolVersion < 0 || uint32(msg.ProtocolVersion) < h.cfg.MinProtocolVersion {
+		return nil, fmt.Errorf("%w: %d is lower than the minimum of %d",
+			ErrObsoleteVersion, msg.ProtocolVersion, h.cfg.MinProtocolVersion)
+	}
+
+	if msg.Nonce == h.nonce || h.cfg.Nonces != nil && h.cfg.Nonces.Contains(msg.Nonce) {
+		return nil, fmt.Errorf("%w: received version nonce %d", ErrSelfConnection, msg.Nonce)
+	}
+
+	h.pver = min(h.cfg.ProtocolVersion, uint32(msg.ProtocolVersion))
+	h.peer = NegotiatedPeer{
+		Inbound:         h.inbound,
+		ProtocolVersion: h.pver,
+		Services:        msg.Services,
+		UserAgent:       msg.UserAgent,
+		LastBlock:       msg.LastBlock,
+		RelayTx:         !msg.DisableRelayTx,
+		AssociationID:   msg.AssociationID,
+		Version:         msg,
+	}
+	h.state = StateAwaitingVerAck
+
+	if h.inbound && h.cfg.EchoAssociationID {
+		h.cfg.AssociationID = msg.AssociationID
+	}
+
+	out := make([]wire.Message, 0, maxHandshakeMessages)
+	if h.inbound {
+		out = append(out, h.versionMsg())
+	}
+
+	out = append(out, wire.NewMsgVerAck())
+
+	// The optional messages go before the protoconf message so a remote
+	// peer which completes its handshake on the protoconf message has
+	// received them all by then.
+	if h.cfg.SendHeaders && h.pver >= wire.SendHeadersVersion {
+		out = append(out, wire.NewMsgSendHeaders())
+	}
+
+	if h.cfg.SendCmpct {
+		out = append(out, wire.NewMsgSendcmpct(true))
+	}
+
+	if h.cfg.FeeFilter > 0 && h.pver >= wire.FeeFilterVersion {
+		out = append(out, wire.NewMsgFeeFilter(h.cfg.FeeFilter))
+	}
+
+	if h.pver >= wire.ProtoconfVersion {
+		out = append(out, &wire.MsgProtoconf{
+			NumberOfFields:       2,
+			MaxRecvPayloadLength: h.cfg.MaxRecvPayloadLength,
+			StreamPolicies:       slices.Clone(h.cfg.StreamPolicies),
+		})
+	}
+
+	return out, nil
+}
+
+// receiveVerAck completes the handshake once the remote peer has acknowledged
+// the version of the local node, unless its protoconf message is awaited.
+func (h *Handshake) receiveVerAck() {
+	if h.pver < wire.ProtoconfVersion {
+		h.complete()
+		return
+	}
+
+	h.state = StateAwaitingProtoconf
+}
+
+// receiveProtoconf validates and records the protoconf of the remote peer.
+func (h *Handshake) receiveProtoconf(msg *wire.MsgProtoconf) error {
+	if msg.MaxRecvPayloadLength < LegacyMaxRecvPayloadLength {
+		return fmt.Errorf("%w: maximum receive payload length %d is less than %d",
+			ErrInvalidProtoconf, msg.MaxRecvPayloadLength, LegacyMaxRecvPayloadLength)
+	}
+
+	if len(msg.StreamPolicies) == 0 {
+		return fmt.Errorf("%w: no stream policies", ErrInvalidProtoconf)
+	}
+
+	h.peer.MaxRecvPayloadLength = msg.MaxRecvPayloadLength
+	h.peer.StreamPolicies = msg.StreamPolicies
+	h.complete()
+
+	return nil
+}
+
+// unexpected returns the error for msg which is not allowed in the current
+// state.
+func (h *Handshake) unexpected(msg wire.Message) error {
+	return fmt.Errorf("%w: %s in %v", ErrUnexpectedMessage, msg.Command(), h.state)
+}
+
+// complete finishes the handshake successfully, filling in the defaults for a
+// remote peer which did not send a protoconf message.
+func (h *Handshake) complete() {
+	if h.peer.StreamPolicies == nil {
+		h.peer.MaxRecvPayloadLength = LegacyMaxRecvPayloadLength
+		h.peer.StreamPolicies = []string{wire.DefaultStreamPolicy}
+	}
+
+	h.finish(StateComplete)
+}
+
+// finish moves the handshake to its final state and forgets its nonce.
+func (h *Handshake) finish(state State) {
+	h.state = state
+
+	if h.cfg.Nonces != nil {
+		h.cfg.Nonces.remove(h.nonce)
+	}
+}
+
+// versionMsg returns the version message of the local node.
+func (h *Handshake) versionMsg() *wire.MsgVersion {
+	unspecified := wire.NewNetAddressIPPort(net.IPv4zero, 0, 0)
+
+	me, you := h.cfg.LocalAddr, h.cfg.RemoteAddr
+	if me == nil {
+		me = unspecified
+	}
+
+	if you == nil {
+		you = unspecified
+	}
+
+	msg := wire.NewMsgVersion(me, you, h.nonce, h.cfg.LastBlock)
+	msg.ProtocolVersion = int32(h.cfg.ProtocolVersion)
+	msg.Services = h.cfg.Services
+	msg.UserAgent = h.cfg.UserAgent
+	msg.DisableRelayTx = h.cfg.DisableRelayTx
+	msg.AssociationID = h.cfg.AssociationID
+
+	return msg
+}
+
+// Run performs the handshake over rw, reading and writing messages with codec,
+// and returns the summary of the handshake once it has completed.  A nil codec
+// uses a new wire.Codec for the configured network.  On success, the
+// negotiated settings have been applied to codec with
+// NegotiatedPeer.Configure.
+//
+// The handshake must complete within the configured timeout and is
+// interrupted when ctx is done.  Messages are written concurrently with
+// reading, so both sides may write at once even over a transport which does
+// not buffer, such as net.Pipe.  Run returns once every message of the
+// handshake has been written.  When rw does not support deadlines, as
+// net.Conn does, a blocked read or write cannot be interrupted and the
+// timeouts only apply between messages.
+//
+// When the remote peer sends no message within the configured protoconf
+// timeout of its verack, the handshake completes with SkipProtoconf.  A
+// protoconf message cut short by the protoconf timeout fails the handshake.
+//
+// On failure the handshake is aborted and the connection should be closed.
+func (h *Handshake) Run(ctx context.Context, rw io.ReadWriter, codec *wire.Codec) (*NegotiatedPeer, error) {
+	return h.run(ctx, rw, codec, nil)
+}
+
+// run performs the handshake like Run.  When first is not nil, it is processed
+// as the first message received from the remote peer, which lets a caller
+// that already read it to decide how to handle the connection hand it over.
+func (h *Handshake) run(ctx context.Context, rw io.ReadWriter, codec *wire.Codec,
+	first wire.Message,
+) (*NegotiatedPeer, error) {
+	if codec == nil {
+		codec = wire.NewCodec(h.cfg.Net, h.cfg.ProtocolVersion)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.cfg.Timeout)
+	defer cancel()
+
+	defer h.Abort()
+
+	var (
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	// The queue never blocks since a handshake sends a bounded number of
+	// messages.
+	queue := make(chan wire.Message, maxHandshakeMessages)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for msg := range queue {
+			if ctx.Err() != nil {
+				continue
+			}
+
+			if _, err := codec.WriteContext(ctx, rw, msg); err != nil {
+				fail(err)
+			}
+		}
+	}()
+
+	codec.SetProtocolVersion(h.pver)
+
+	for _, msg := range h.Start() {
+		queue <- msg
+	}
+
+	for h.state != StateComplete {
+		msg := first
+		first = nil
+
+		if msg == nil {
+			var (
+				skip bool
+				err  error
+			)
+
+			msg, skip, err = h.read(ctx, rw, codec)
+			if err != nil {
+				fail(err)
+				break
+			}
+
+			if skip {
+				// SkipProtoconf cannot fail while the protoconf
+				// message is awaited.
+				_ = h.SkipProtoconf()
+				break
+			}
+		}
+
+		out, err := h.Receive(msg)
+		if err != nil {
+			fail(err)
+			break
+		}
+
+		codec.SetProtocolVersion(h.pver)
+
+		for _, m := range out {
+			queue <- m
+		}
+	}
+
+	close(queue)
+	<-done
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	h.peer.Configure(codec)
+
+	return &h.peer, nil
+}
+
+// read reads the next message of the handshake from rw.  While the protoconf
+// message of the remote peer is awaited, the read is limited to the protoconf
+// timeout and skip reports that it passed before any byte was received.
+func (h *Handshake) read(ctx context.Context, rw io.Reader, codec *wire.Codec) (wire.Message, bool, error) {
+	if h.state != StateAwaitingProtoconf {
+		_, msg, _, err := codec.ReadContext(ctx, rw)
+		return msg, false, err
+	}
+
+	graceCtx, cancel := context.WithTimeout(ctx, h.cfg.ProtoconfTimeout)
+	defer cancel()
+
+	n, msg, _, err := codec.ReadContext(graceCtx, rw)
+	if err != nil && n == 0 && expired(graceCtx) && !expired(ctx) {
+		return nil, true, nil
+	}
+
+	return msg, false, err
+}
+
+// Negotiate performs the handshake described by cfg over rw with a new
+// Handshake.  See Handshake.Run for details.
+func Negotiate(ctx context.Context, rw io.ReadWriter, cfg *Config, inbound bool) (*NegotiatedPeer, error) {
+	h, err := NewHandshake(cfg, inbound)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.Run(ctx, rw, nil)
+}
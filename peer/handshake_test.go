@@ -0,0 +1,476 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+// newVersion returns a version message of a remote peer advertising pver.
+func newVersion(pver uint32, nonce uint64) *wire.MsgVersion {
+	addr := wire.NewNetAddressIPPort(net.IPv4zero, 0, 0)
+	msg := wire.NewMsgVersion(addr, addr, nonce, 0)
+	msg.ProtocolVersion = int32(pver)
+
+	return msg
+}
+
+// commands returns the commands of msgs.
+func commands(msgs []wire.Message) []string {
+	cmds := make([]string, len(msgs))
+	for i, msg := range msgs {
+		cmds[i] = msg.Command()
+	}
+
+	return cmds
+}
+
+// negotiatePair performs the handshake between an outbound peer configured
+// with outCfg and an inbound peer configured with inCfg over net.Pipe.
+func negotiatePair(t *testing.T, outCfg, inCfg *Config) (*NegotiatedPeer, *NegotiatedPeer, error, error) {
+	t.Helper()
+
+	outConn, inConn := net.Pipe()
+	t.Cleanup(func() {
+		_ = outConn.Close()
+		_ = inConn.Close()
+	})
+
+	type result struct {
+		np  *NegotiatedPeer
+		err error
+	}
+
+	inResult := make(chan result, 1)
+
+	go func() {
+		np, err := Negotiate(context.Background(), inConn, inCfg, true)
+		if err != nil {
+			_ = inConn.Close()
+		}
+
+		inResult <- result{np, err}
+	}()
+
+	outNP, outErr := Negotiate(context.Background(), outConn, outCfg, false)
+	if outErr != nil {
+		_ = outConn.Close()
+	}
+
+	in := <-inResult
+
+	return outNP, in.np, outErr, in.err
+}
+
+// TestNegotiate ensures two peers negotiate over a transport which does not
+// buffer and each summarises what the other advertised.
+func TestNegotiate(t *testing.T) {
+	assocID := append([]byte{0x01}, make([]byte, 16)...)
+
+	outCfg := &Config{
+		Net:                  wire.MainNet,
+		Services:             wire.SFNodeNetwork | wire.SFNodeBloom,
+		UserAgent:            "/outbound:1.0/",
+		LastBlock:            100,
+		AssociationID:        assocID,
+		MaxRecvPayloadLength: 4 * 1024 * 1024,
+		StreamPolicies:       []string{wire.BlockPriorityStreamPolicy, wire.DefaultStreamPolicy},
+		SendHeaders:          true,
+		FeeFilter:            1000,
+	}
+	inCfg := &Config{
+		Net:             wire.MainNet,
+		ProtocolVersion: wire.FeeFilterVersion + 2,
+		UserAgent:       "/inbound:2.0/",
+		LastBlock:       200,
+		DisableRelayTx:  true,
+		SendCmpct:       true,
+	}
+
+	out, in, outErr, inErr := negotiatePair(t, outCfg, inCfg)
+	require.NoError(t, outErr)
+	require.NoError(t, inErr)
+
+	// The outbound peer sees the inbound one.
+	assert.False(t, out.Inbound)
+	assert.Equal(t, inCfg.ProtocolVersion, out.ProtocolVersion)
+	assert.Equal(t, wire.ServiceFlag(0), out.Services)
+	assert.Equal(t, "/inbound:2.0/", out.UserAgent)
+	assert.Equal(t, int32(200), out.LastBlock)
+	assert.False(t, out.RelayTx)
+	assert.Empty(t, out.AssociationID)
+	assert.Equal(t, wire.DefaultMaxRecvPayloadLength, out.MaxRecvPayloadLength)
+	assert.Equal(t, []string{wire.DefaultStreamPolicy}, out.StreamPolicies)
+	assert.Equal(t, []string{wire.CmdSendcmpct}, commands(out.Pending))
+
+	// The inbound peer sees the outbound one.
+	assert.True(t, in.Inbound)
+	assert.Equal(t, inCfg.ProtocolVersion, in.ProtocolVersion)
+	assert.Equal(t, outCfg.Services, in.Services)
+	assert.Equal(t, "/outbound:1.0/", in.UserAgent)
+	assert.Equal(t, int32(100), in.LastBlock)
+	assert.True(t, in.RelayTx)
+	assert.Equal(t, assocID, in.AssociationID)
+	assert.Equal(t, outCfg.MaxRecvPayloadLength, in.MaxRecvPayloadLength)
+	assert.Equal(t, outCfg.StreamPolicies, in.StreamPolicies)
+	assert.Equal(t, []string{wire.CmdSendHeaders, wire.CmdFeeFilter}, commands(in.Pending))
+	assert.Equal(t, int64(1000), in.Pending[1].(*wire.MsgFeeFilter).MinFee)
+}
+
+// TestNegotiateLegacy ensures peers which do not support protoconf complete
+// the handshake with the legacy defaults.
+func TestNegotiateLegacy(t *testing.T) {
+	out, in, outErr, inErr := negotiatePair(t, &Config{}, &Config{ProtocolVersion: wire.BIP0037Version})
+	require.NoError(t, outErr)
+	require.NoError(t, inErr)
+
+	for _, np := range []*NegotiatedPeer{out, in} {
+		assert.Equal(t, wire.BIP0037Version, np.ProtocolVersion)
+		assert.Equal(t, LegacyMaxRecvPayloadLength, np.MaxRecvPayloadLength)
+		assert.Equal(t, []string{wire.DefaultStreamPolicy}, np.StreamPolicies)
+		assert.Empty(t, np.Pending)
+	}
+}
+
+// TestNegotiateSilentProtoconf ensures a remote peer which supports protoconf
+// by version but sends nothing after its verack does not stall the handshake
+// until its timeout.
+func TestNegotiateSilentProtoconf(t *testing.T) {
+	conn, remote := net.Pipe()
+	defer conn.Close()
+	defer remote.Close()
+
+	// The remote peer answers the version like a legacy node and then
+	// waits for the local node.
+	go func() {
+		_, _, _, err := wire.ReadMessageN(remote, wire.ProtocolVersion, wire.MainNet)
+		if err != nil {
+			return
+		}
+
+		for _, msg := range []wire.Message{newVersion(wire.ProtoconfVersion, 1), wire.NewMsgVerAck()} {
+			if _, err = wire.WriteMessageN(remote, msg, wire.ProtocolVersion, wire.MainNet); err != nil {
+				return
+			}
+		}
+
+		for {
+			if _, _, _, err = wire.ReadMessageN(remote, wire.ProtocolVersion, wire.MainNet); err != nil {
+				return
+			}
+		}
+	}()
+
+	start := time.Now()
+	cfg := &Config{Net: wire.MainNet, ProtoconfTimeout: 50 * time.Millisecond}
+
+	np, err := Negotiate(context.Background(), conn, cfg, false)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), DefaultHandshakeTimeout/2)
+
+	assert.Equal(t, wire.ProtoconfVersion, np.ProtocolVersion)
+	assert.Equal(t, LegacyMaxRecvPayloadLength, np.MaxRecvPayloadLength)
+	assert.Equal(t, []string{wire.DefaultStreamPolicy}, np.StreamPolicies)
+	assert.Empty(t, np.Pending)
+}
+
+// TestNegotiateSelfConnection ensures a node connected to itself notices on
+// the inbound side.
+func TestNegotiateSelfConnection(t *testing.T) {
+	nonces := NewNonceSet()
+
+	_, _, outErr, inErr := negotiatePair(t, &Config{Nonces: nonces}, &Config{Nonces: nonces})
+
+	require.ErrorIs(t, inErr, ErrSelfConnection)
+	require.Error(t, outErr)
+
+	// Finished handshakes forget their nonces.
+	assert.Empty(t, nonces.nonces)
+}
+
+// TestNegotiateTimeout ensures a peer which stalls does not hold the
+// handshake open beyond its timeout or the context.
+func TestNegotiateTimeout(t *testing.T) {
+	conn, remote := net.Pipe()
+	defer conn.Close()
+	defer remote.Close()
+
+	start := time.Now()
+	_, err := Negotiate(context.Background(), conn, &Config{Timeout: 50 * time.Millisecond}, true)
+
+	var te *wire.TimeoutError
+	require.ErrorAs(t, err, &te)
+	assert.True(t, te.Timeout())
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	_, err = Negotiate(ctx, conn, &Config{}, true)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+// TestNegotiateRemoteFailure ensures errors reading from a connection the
+// remote peer closed are returned.
+func TestNegotiateRemoteFailure(t *testing.T) {
+	conn, remote := net.Pipe()
+	defer conn.Close()
+
+	go func() {
+		_, _, _, _ = wire.ReadMessageN(remote, wire.ProtocolVersion, wire.MainNet)
+		_ = remote.Close()
+	}()
+
+	_, err := Negotiate(context.Background(), conn, &Config{}, false)
+	require.Error(t, err)
+}
+
+// TestHandshakeMessages ensures the handshake sends the configured messages in
+// order and moves through its states.
+func TestHandshakeMessages(t *testing.T) {
+	nonces := NewNonceSet()
+
+	h, err := NewHandshake(&Config{
+		Services:    wire.SFNodeNetwork,
+		SendHeaders: true,
+		SendCmpct:   true,
+		FeeFilter:   500,
+		Nonces:      nonces,
+	}, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, StateAwaitingVersion, h.State())
+	assert.Equal(t, wire.ProtocolVersion, h.ProtocolVersion())
+
+	// Inbound handshakes wait for the version of the remote peer.
+	assert.Empty(t, h.Start())
+	assert.True(t, nonces.Contains(h.Nonce()))
+
+	out, err := h.Receive(newVersion(wire.ProtoconfVersion, 1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		wire.CmdVersion, wire.CmdVerAck, wire.CmdSendHeaders, wire.CmdSendcmpct,
+		wire.CmdFeeFilter, wire.CmdProtoconf,
+	}, commands(out))
+	assert.Equal(t, StateAwaitingVerAck, h.State())
+	assert.Equal(t, wire.ProtoconfVersion, h.ProtocolVersion())
+
+	version := out[0].(*wire.MsgVersion)
+	assert.Equal(t, h.Nonce(), version.Nonce)
+	assert.Equal(t, wire.SFNodeNetwork, version.Services)
+	assert.Equal(t, int32(wire.ProtocolVersion), version.ProtocolVersion)
+	assert.Equal(t, wire.DefaultMaxRecvPayloadLength, out[5].(*wire.MsgProtoconf).MaxRecvPayloadLength)
+
+	out, err = h.Receive(wire.NewMsgVerAck())
+	require.NoError(t, err)
+	assert.Empty(t, out)
+	assert.Equal(t, StateAwaitingProtoconf, h.State())
+	assert.Nil(t, h.Peer())
+
+	// A remote peer which moves on without a protoconf uses the defaults.
+	_, err = h.Receive(wire.NewMsgSendHeaders())
+	require.NoError(t, err)
+
+	ping := wire.NewMsgPing(7)
+	_, err = h.Receive(ping)
+	require.NoError(t, err)
+	assert.Equal(t, StateComplete, h.State())
+	assert.False(t, nonces.Contains(h.Nonce()))
+
+	np := h.Peer()
+	require.NotNil(t, np)
+	assert.Equal(t, LegacyMaxRecvPayloadLength, np.MaxRecvPayloadLength)
+	assert.Equal(t, []string{wire.CmdSendHeaders, wire.CmdPing}, commands(np.Pending))
+
+	_, err = h.Receive(ping)
+	require.ErrorIs(t, err, ErrHandshakeFinished)
+
+	// The negotiated settings apply to a codec.
+	codec := wire.NewCodec(wire.MainNet, wire.ProtocolVersion)
+	np.Configure(codec)
+	assert.Equal(t, wire.ProtoconfVersion, codec.ProtocolVersion())
+	assert.Equal(t, uint64(LegacyMaxRecvPayloadLength), codec.MaxRecvPayloadLength())
+}
+
+// TestHandshakeOrdering ensures messages out of order and invalid values fail
+// the handshake.
+func TestHandshakeOrdering(t *testing.T) {
+	version := newVersion(wire.ProtocolVersion, 1)
+	verack := wire.NewMsgVerAck()
+	protoconf := wire.NewMsgProtoconf(0, false)
+
+	tests := []struct {
+		name string
+		msgs []wire.Message
+		err  error
+	}{
+		{"verack first", []wire.Message{verack}, ErrUnexpectedMessage},
+		{"ping first", []wire.Message{wire.NewMsgPing(1)}, ErrUnexpectedMessage},
+		{"duplicate version", []wire.Message{version, version}, ErrUnexpectedMessage},
+		{"protoconf before verack", []wire.Message{version, protoconf}, ErrUnexpectedMessage},
+		{"sendheaders before verack", []wire.Message{version, wire.NewMsgSendHeaders()}, ErrUnexpectedMessage},
+		{"ping before verack", []wire.Message{version, wire.NewMsgPing(1)}, ErrUnexpectedMessage},
+		{"duplicate verack", []wire.Message{version, verack, verack}, ErrUnexpectedMessage},
+		{"obsolete version", []wire.Message{newVersion(wire.MultipleAddressVersion-1, 1)}, ErrObsoleteVersion},
+		{"negative version", []wire.Message{newVersion(1<<31, 1)}, ErrObsoleteVersion},
+		{
+			"small max payload",
+			[]wire.Message{version, verack, &wire.MsgProtoconf{
+				NumberOfFields: 2, MaxRecvPayloadLength: LegacyMaxRecvPayloadLength - 1,
+				StreamPolicies: []string{wire.DefaultStreamPolicy},
+			}},
+			ErrInvalidProtoconf,
+		},
+		{
+			"no stream policies",
+			[]wire.Message{version, verack, &wire.MsgProtoconf{
+				NumberOfFields: 2, MaxRecvPayloadLength: LegacyMaxRecvPayloadLength,
+			}},
+			ErrInvalidProtoconf,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			h, err := NewHandshake(&Config{}, false)
+			require.NoError(t, err)
+			require.Len(t, h.Start(), 1)
+
+			last := len(test.msgs) - 1
+			for _, msg := range test.msgs[:last] {
+				_, err = h.Receive(msg)
+				require.NoError(t, err)
+			}
+
+			_, err = h.Receive(test.msgs[last])
+			require.ErrorIs(t, err, test.err)
+			assert.Equal(t, StateFailed, h.State())
+			assert.Nil(t, h.Peer())
+		})
+	}
+
+	// A complete handshake with a protoconf.
+	h, err := NewHandshake(&Config{}, false)
+	require.NoError(t, err)
+
+	for _, msg := range []wire.Message{version, verack, protoconf} {
+		_, err = h.Receive(msg)
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, StateComplete, h.State())
+	assert.Equal(t, protoconf.StreamPolicies, h.Peer().StreamPolicies)
+}
+
+// TestHandshakeSkipProtoconf ensures the protoconf message may only be
+// skipped while it is awaited.
+func TestHandshakeSkipProtoconf(t *testing.T) {
+	h, err := NewHandshake(&Config{}, false)
+	require.NoError(t, err)
+	require.Len(t, h.Start(), 1)
+	require.Error(t, h.SkipProtoconf())
+
+	for _, msg := range []wire.Message{newVersion(wire.ProtocolVersion, 1), wire.NewMsgVerAck()} {
+		_, err = h.Receive(msg)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, h.SkipProtoconf())
+	assert.Equal(t, StateComplete, h.State())
+	assert.Equal(t, LegacyMaxRecvPayloadLength, h.Peer().MaxRecvPayloadLength)
+	require.ErrorIs(t, h.SkipProtoconf(), ErrHandshakeFinished)
+
+	// A late protoconf is no longer part of the handshake.
+	_, err = h.Receive(wire.NewMsgProtoconf(0, false))
+	require.ErrorIs(t, err, ErrHandshakeFinished)
+}
+
+// TestHandshakeSelfConnection ensures a version nonce sent by the handshake
+// itself or another handshake sharing its NonceSet is rejected, and that
+// aborted handshakes forget their nonce.
+func TestHandshakeSelfConnection(t *testing.T) {
+	nonces := NewNonceSet()
+
+	outbound, err := NewHandshake(&Config{Nonces: nonces}, false)
+	require.NoError(t, err)
+
+	sent := outbound.Start()
+	require.Len(t, sent, 1)
+
+	inbound, err := NewHandshake(&Config{Nonces: nonces}, true)
+	require.NoError(t, err)
+
+	_, err = inbound.Receive(sent[0])
+	require.ErrorIs(t, err, ErrSelfConnection)
+
+	outbound.Abort()
+	assert.Equal(t, StateFailed, outbound.State())
+	assert.False(t, nonces.Contains(outbound.Nonce()))
+
+	// Without a shared set, only the nonce of the handshake itself counts.
+	h, err := NewHandshake(&Config{}, true)
+	require.NoError(t, err)
+
+	_, err = h.Receive(newVersion(wire.ProtocolVersion, h.Nonce()))
+	require.ErrorIs(t, err, ErrSelfConnection)
+}
+
+// TestHandshakeEchoAssociationID ensures an inbound handshake confirms the
+// association ID of the remote peer when asked to.
+func TestHandshakeEchoAssociationID(t *testing.T) {
+	version := newVersion(wire.ProtocolVersion, 1)
+	version.AssociationID = []byte{0x01, 0x02, 0x03}
+
+	for _, echo := range []bool{false, true} {
+		h, err := NewHandshake(&Config{AssociationID: []byte{0x01, 0xff}, EchoAssociationID: echo}, true)
+		require.NoError(t, err)
+
+		out, err := h.Receive(version)
+		require.NoError(t, err)
+
+		want := []byte{0x01, 0xff}
+		if echo {
+			want = version.AssociationID
+		}
+
+		assert.Equal(t, want, out[0].(*wire.MsgVersion).AssociationID)
+	}
+}
+
+// TestNewHandshakeErrors ensures invalid configurations are rejected.
+func TestNewHandshakeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"user agent", Config{UserAgent: "/" + strings.Repeat("a", wire.MaxUserAgentLen) + "/"}},
+		{"association ID", Config{AssociationID: make([]byte, wire.MaxAssociationIDLen+1)}},
+		{"stream policies", Config{StreamPolicies: make([]string, wire.MaxNumStreamPolicies+1)}},
+		{"max payload", Config{MaxRecvPayloadLength: LegacyMaxRecvPayloadLength - 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewHandshake(&test.cfg, false)
+			require.Error(t, err)
+		})
+	}
+}
+
+// TestStateString tests the stringized output for the State type.
+func TestStateString(t *testing.T) {
+	assert.Equal(t, "StateAwaitingVersion", StateAwaitingVersion.String())
+	assert.Equal(t, "StateFailed", StateFailed.String())
+	assert.Equal(t, "Unknown State (99)", State(99).String())
+}
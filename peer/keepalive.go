@@ -0,0 +1,293 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+const (
+	// DefaultPingInterval is the time between pings unless configured
+	// otherwise.
+	DefaultPingInterval = 2 * time.Minute
+
+	// DefaultPingTimeout is the time the remote peer has to answer a ping
+	// unless configured otherwise.
+	DefaultPingTimeout = 30 * time.Second
+
+	// maxPendingPongs is the number of pings of the remote peer which may
+	// await their pong.  Pings beyond it are not answered.
+	maxPendingPongs = 8
+)
+
+// ErrPingTimeout is returned when the remote peer does not answer a ping in
+// time.
+var ErrPingTimeout = errors.New("ping timeout")
+
+// MessageWriter is implemented by the streams and associations messages can
+// be written to.
+type MessageWriter interface {
+	WriteMessage(ctx context.Context, msg wire.Message) error
+}
+
+// KeepaliveConfig holds the settings of a Keepalive.  The zero value of every
+// field is valid and selects the documented default.
+type KeepaliveConfig struct {
+	// Interval is the time between pings.  Zero means
+	// DefaultPingInterval.
+	Interval time.Duration
+
+	// Timeout is the time the remote peer has to answer a ping with a
+	// pong.  Zero means DefaultPingTimeout.
+	Timeout time.Duration
+
+	// OnStall, when not nil, is called with the error Run returns when the
+	// remote peer does not answer a ping in time, which wraps
+	// ErrPingTimeout.
+	OnStall func(err error)
+}
+
+// Latency summarises the round-trip times measured by a Keepalive.
+type Latency struct {
+	// Last is the most recent round-trip time.
+	Last time.Duration
+
+	// Min is the smallest round-trip time.
+	Min time.Duration
+
+	// Smoothed is the exponentially weighted moving average of the
+	// round-trip times, which gives each new sample a weight of 1/8.
+	Smoothed time.Duration
+
+	// Samples is the number of round-trip times measured.
+	Samples uint64
+}
+
+// Keepalive pings the remote peer of a connection at regular intervals,
+// measures the round-trip time of each ping and detects remote peers which
+// stop answering.  It also answers the pings of the remote peer.
+//
+// Keepalive does not read from the connection: whoever reads it hands every
+// message to HandleMessage.  Run writes the pings and pongs.
+//
+// Peers at protocol version wire.BIP0031Version or older neither send nonces
+// in their pings nor answer them with pongs.  They are sent pings merely to
+// keep the connection alive, so no round-trip time is measured and no stall
+// is detected, and their pings are not answered.
+//
+// All methods are safe for concurrent access.
+type Keepalive struct {
+	cfg    KeepaliveConfig
+	legacy bool
+	pongs  chan uint64
+
+	mtx     sync.Mutex
+	nonce   uint64
+	sentAt  time.Time
+	pending bool
+	latency Latency
+}
+
+// NewKeepalive returns a new Keepalive with the settings of cfg for a remote
+// peer negotiated at protocol version pver.
+func NewKeepalive(cfg *KeepaliveConfig, pver uint32) *Keepalive {
+	c := *cfg
+	if c.Interval <= 0 {
+		c.Interval = DefaultPingInterval
+	}
+
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultPingTimeout
+	}
+
+	return &Keepalive{
+		cfg:    c,
+		legacy: pver <= wire.BIP0031Version,
+		pongs:  make(chan uint64, maxPendingPongs),
+	}
+}
+
+// Latency returns the round-trip times measured so far.
+func (k *Keepalive) Latency() Latency {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	return k.latency
+}
+
+// HandleMessage processes msg, read from the connection, and reports whether
+// it was a ping or pong message, which need no further processing.  A pong
+// which answers the outstanding ping yields a round-trip time, and a ping is
+// answered by Run.
+func (k *Keepalive) HandleMessage(msg wire.Message) bool {
+	switch msg := msg.(type) {
+	case *wire.MsgPing:
+		if k.legacy {
+			return true
+		}
+
+		select {
+		case k.pongs <- msg.Nonce:
+		default:
+		}
+
+		return true
+
+	case *wire.MsgPong:
+		k.receivedPong(msg.Nonce)
+		return true
+
+	default:
+		return false
+	}
+}
+
+// receivedPong records the round-trip time of the outstanding ping when the
+// pong with nonce answers it.
+func (k *Keepalive) receivedPong(nonce uint64) {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	if !k.pending || nonce != k.nonce {
+		return
+	}
+
+	rtt := time.Since(k.sentAt)
+	k.pending = false
+
+	l := &k.latency
+	l.Last = rtt
+
+	if l.Samples == 0 {
+		l.Min = rtt
+		l.Smoothed = rtt
+	} else {
+		l.Min = min(l.Min, rtt)
+		l.Smoothed += (rtt - l.Smoothed) / 8
+	}
+
+	l.Samples++
+}
+
+// Run writes a ping to w right away and then every interval, skipping pings
+// while one is outstanding, along with the pongs answering the pings of the
+// remote peer.  It returns when ctx is done, a write fails or the remote peer
+// does not answer a ping in time, in which case the error wraps
+// ErrPingTimeout and is also passed to the OnStall callback.
+//
+// Run must not be called concurrently.
+func (k *Keepalive) Run(ctx context.Context, w MessageWriter) error {
+	ticker := time.NewTicker(k.cfg.Interval)
+	defer ticker.Stop()
+
+	timer := time.NewTimer(k.cfg.Timeout)
+	timer.Stop()
+
+	defer timer.Stop()
+
+	// timeout is the channel of timer once a ping has been sent.  Pings
+	// answered in time are ignored when it fires.
+	var timeout <-chan time.Time
+
+	ping := func() error {
+		sent, err := k.ping(ctx, w)
+		if err != nil || !sent {
+			return err
+		}
+
+		timer.Reset(k.cfg.Timeout)
+		timeout = timer.C
+
+		return nil
+	}
+
+	if err := ping(); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-ticker.C:
+			if err := ping(); err != nil {
+				return err
+			}
+
+		case <-timeout:
+			if err := k.stalled(); err != nil {
+				return err
+			}
+
+			timeout = nil
+
+		case nonce := <-k.pongs:
+			if err := w.WriteMessage(ctx, wire.NewMsgPong(nonce)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ping writes a ping to w unless one is outstanding, and reports whether it
+// awaits a pong.
+func (k *Keepalive) ping(ctx context.Context, w MessageWriter) (bool, error) {
+	if k.legacy {
+		return false, w.WriteMessage(ctx, wire.NewMsgPing(0))
+	}
+
+	nonce, err := wire.RandomUint64()
+	if err != nil {
+		return false, err
+	}
+
+	// The ping is recorded before it is written, since the pong may be
+	// read before the write returns.
+	k.mtx.Lock()
+	if k.pending {
+		k.mtx.Unlock()
+		return false, nil
+	}
+
+	k.nonce = nonce
+	k.sentAt = time.Now()
+	k.pending = true
+	k.mtx.Unlock()
+
+	if err := w.WriteMessage(ctx, wire.NewMsgPing(nonce)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// stalled returns the error for the outstanding ping once its timeout has
+// expired, after passing it to the OnStall callback, or nil when the ping has
+// been answered in the meantime.
+func (k *Keepalive) stalled() error {
+	k.mtx.Lock()
+	pending, nonce := k.pending, k.nonce
+	k.mtx.Unlock()
+
+	if !pending {
+		return nil
+	}
+
+	err := fmt.Errorf("%w: no pong for nonce %d within %v", ErrPingTimeout, nonce,
+		k.cfg.Timeout)
+
+	if k.cfg.OnStall != nil {
+		k.cfg.OnStall(err)
+	}
+
+	return err
+}
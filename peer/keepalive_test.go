@@ -0,0 +1,213 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+// writerFunc adapts a function to the MessageWriter interface.
+type writerFunc func(ctx context.Context, msg wire.Message) error
+
+func (f writerFunc) WriteMessage(ctx context.Context, msg wire.Message) error {
+	return f(ctx, msg)
+}
+
+// recorder is a MessageWriter which records the messages written to it.
+type recorder struct {
+	mtx  sync.Mutex
+	msgs []wire.Message
+}
+
+func (r *recorder) WriteMessage(_ context.Context, msg wire.Message) error {
+	r.mtx.Lock()
+	r.msgs = append(r.msgs, msg)
+	r.mtx.Unlock()
+
+	return nil
+}
+
+// commands returns the commands of the messages written so far.
+func (r *recorder) commands() []string {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	return commands(r.msgs)
+}
+
+// TestKeepalive ensures pongs yield round-trip times over a real stream and
+// the pings of the remote peer are answered.
+func TestKeepalive(t *testing.T) {
+	a, remoteGeneral, _ := newPipeAssociation(t)
+
+	k := NewKeepalive(&KeepaliveConfig{Interval: 10 * time.Millisecond, Timeout: time.Second},
+		wire.ProtocolVersion)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- k.Run(ctx, a)
+	}()
+
+	// The local side reads its general stream and hands everything over.
+	go func() {
+		for {
+			msg, err := a.General().ReadMessage(ctx)
+			if err != nil {
+				return
+			}
+
+			assert.True(t, k.HandleMessage(msg))
+		}
+	}()
+
+	// The remote side answers pings and pings once itself.
+	codec := wire.NewCodec(wire.MainNet, wire.ProtocolVersion)
+	_, err := codec.WriteContext(ctx, remoteGeneral, wire.NewMsgPing(42))
+	require.NoError(t, err)
+
+	var gotPong bool
+
+	for !gotPong || k.Latency().Samples < 3 {
+		_, msg, _, err := codec.ReadContext(ctx, remoteGeneral)
+		require.NoError(t, err)
+
+		switch msg := msg.(type) {
+		case *wire.MsgPing:
+			time.Sleep(time.Millisecond)
+
+			_, err = codec.WriteContext(ctx, remoteGeneral, wire.NewMsgPong(msg.Nonce))
+			require.NoError(t, err)
+
+		case *wire.MsgPong:
+			assert.Equal(t, uint64(42), msg.Nonce)
+
+			gotPong = true
+		}
+	}
+
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	l := k.Latency()
+	assert.GreaterOrEqual(t, l.Samples, uint64(3))
+	assert.GreaterOrEqual(t, l.Min, time.Millisecond)
+	assert.LessOrEqual(t, l.Min, l.Last)
+	assert.LessOrEqual(t, l.Min, l.Smoothed)
+}
+
+// TestKeepaliveLatency ensures the round-trip time statistics are computed
+// as documented.
+func TestKeepaliveLatency(t *testing.T) {
+	k := NewKeepalive(&KeepaliveConfig{}, wire.ProtocolVersion)
+
+	for _, rtt := range []time.Duration{80, 40, 120} {
+		k.pending, k.nonce, k.sentAt = true, 7, time.Now().Add(-rtt*time.Millisecond)
+
+		// Pongs with the wrong nonce are ignored.
+		assert.True(t, k.HandleMessage(wire.NewMsgPong(8)))
+		require.True(t, k.pending)
+
+		assert.True(t, k.HandleMessage(wire.NewMsgPong(7)))
+		require.False(t, k.pending)
+	}
+
+	// Pongs without an outstanding ping are ignored.
+	k.HandleMessage(wire.NewMsgPong(7))
+
+	l := k.Latency()
+	assert.Equal(t, uint64(3), l.Samples)
+	assert.InDelta(t, 120*time.Millisecond, l.Last, float64(10*time.Millisecond))
+	assert.InDelta(t, 40*time.Millisecond, l.Min, float64(10*time.Millisecond))
+
+	// 80, then 80 + (40 - 80) / 8 = 75, then 75 + (120 - 75) / 8 = 80.625.
+	assert.InDelta(t, 80625*time.Microsecond, l.Smoothed, float64(10*time.Millisecond))
+
+	assert.False(t, k.HandleMessage(wire.NewMsgVerAck()))
+}
+
+// TestKeepaliveStall ensures a remote peer which does not answer pings is
+// detected and reported once.
+func TestKeepaliveStall(t *testing.T) {
+	var stalls []error
+
+	w := &recorder{}
+	k := NewKeepalive(&KeepaliveConfig{
+		Interval: 5 * time.Millisecond,
+		Timeout:  30 * time.Millisecond,
+		OnStall:  func(err error) { stalls = append(stalls, err) },
+	}, wire.ProtocolVersion)
+
+	err := k.Run(context.Background(), w)
+	require.ErrorIs(t, err, ErrPingTimeout)
+	require.Len(t, stalls, 1)
+	assert.Equal(t, err, stalls[0])
+
+	// No ping is sent while one is outstanding.
+	assert.Equal(t, []string{wire.CmdPing}, w.commands())
+	assert.Zero(t, k.Latency().Samples)
+}
+
+// TestKeepaliveLegacy ensures peers which predate pongs are pinged without
+// ever being considered stalled, and their pings are not answered.
+func TestKeepaliveLegacy(t *testing.T) {
+	w := &recorder{}
+	k := NewKeepalive(&KeepaliveConfig{
+		Interval: 5 * time.Millisecond,
+		Timeout:  5 * time.Millisecond,
+		OnStall:  func(error) { assert.Fail(t, "legacy peer stalled") },
+	}, wire.BIP0031Version)
+
+	assert.True(t, k.HandleMessage(wire.NewMsgPing(0)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, k.Run(ctx, w), context.DeadlineExceeded)
+
+	cmds := w.commands()
+	assert.Greater(t, len(cmds), 1)
+	assert.NotContains(t, cmds, wire.CmdPong)
+	assert.Zero(t, k.Latency().Samples)
+
+	// The pings are encoded without a nonce.
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go func() {
+		_ = wire.WriteMessage(client, w.msgs[0], wire.BIP0031Version, wire.MainNet)
+	}()
+
+	n, msg, _, err := wire.ReadMessageN(server, wire.BIP0031Version, wire.MainNet)
+	require.NoError(t, err)
+	assert.Equal(t, wire.MessageHeaderSize, n)
+	assert.Equal(t, wire.CmdPing, msg.Command())
+}
+
+// TestKeepaliveWriteError ensures a failed write stops Run.
+func TestKeepaliveWriteError(t *testing.T) {
+	errWrite := errors.New("write failed")
+
+	k := NewKeepalive(&KeepaliveConfig{}, wire.ProtocolVersion)
+
+	err := k.Run(context.Background(), writerFunc(func(context.Context, wire.Message) error {
+		return errWrite
+	}))
+	require.ErrorIs(t, err, errWrite)
+}
@@ -0,0 +1,590 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+// DefaultStreamTimeout is the time the createstrm and streamack exchange may
+// take unless configured otherwise.
+const DefaultStreamTimeout = 10 * time.Second
+
+// ManagerConfig holds the settings of an AssociationManager.  The zero value
+// of every field is valid and selects the documented default.
+type ManagerConfig struct {
+	// Handshake is the configuration of the version handshake on the
+	// general stream.  Its AssociationID is ignored: outbound associations
+	// use a new association ID and inbound ones confirm the one of the
+	// remote peer.  Nil Nonces means a NonceSet shared by the handshakes of
+	// the manager.
+	Handshake Config
+
+	// StreamTypes limits the data streams opened for outbound
+	// associations with peers which support multistreams to those listed,
+	// in order.  Only the data streams the stream policy of the
+	// association routes messages to are opened.  Nil means every one of
+	// them, in order of type.
+	StreamTypes []wire.StreamType
+
+	// StreamPolicy is the name of the stream policy data streams are
+	// created for and outbound associations route their messages with.
+	// Empty means the policy SelectStreamPolicy picks from the stream
+	// policies of Handshake and those of the remote peer.
+	StreamPolicy string
+
+	// StreamTimeout is the time the createstrm and streamack exchange of
+	// each data stream may take, including establishing its connection.
+	// It also bounds the wait for the first message of an accepted
+	// connection.  Zero means DefaultStreamTimeout.
+	StreamTimeout time.Duration
+
+	// Dial establishes the connections of outbound associations.  Nil
+	// means the DialContext method of a zero net.Dialer.
+	Dial func(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// AssociationManager drives the multistream protocol.  It establishes
+// outbound associations by performing the version handshake with a new
+// association ID and opening a connection for each configured data stream,
+// and accepts inbound connections as either the general stream of a new
+// association or a data stream of an existing one.
+//
+// All methods are safe for concurrent access.
+type AssociationManager struct {
+	cfg ManagerConfig
+
+	mtx sync.Mutex
+	// inbound holds the inbound associations by ID, which accepted data
+	// streams join.
+	inbound map[string]*Association
+	// pending holds a channel for the ID of each inbound association whose
+	// handshake is in progress, closed when it finishes.
+	pending map[string]chan struct{}
+	// all holds every live association.
+	all    map[*Association]struct{}
+	closed bool
+}
+
+// NewAssociationManager returns a new AssociationManager with the settings of
+// cfg.
+func NewAssociationManager(cfg *ManagerConfig) (*AssociationManager, error) {
+	c := *cfg
+	if c.Handshake.Nonces == nil {
+		c.Handshake.Nonces = NewNonceSet()
+	}
+
+	if c.Handshake.StreamPolicies == nil {
+		c.Handshake.StreamPolicies = []string{wire.DefaultStreamPolicy}
+	}
+
+	if c.StreamTimeout == 0 {
+		c.StreamTimeout = DefaultStreamTimeout
+	}
+
+	if c.Dial == nil {
+		c.Dial = new(net.Dialer).DialContext
+	}
+
+	seen := make(map[wire.StreamType]bool, len(c.StreamTypes))
+	for _, t := range c.StreamTypes {
+		if !isDataStream(t) {
+			return nil, fmt.Errorf("%w: %v is not a data stream", ErrInvalidStreamType, t)
+		}
+
+		if seen[t] {
+			return nil, fmt.Errorf("%w: %v", ErrDuplicateStream, t)
+		}
+
+		seen[t] = true
+	}
+
+	if len(c.StreamPolicy) > wire.MaxUserAgentLen {
+		return nil, fmt.Errorf("stream policy name is longer than %d bytes", wire.MaxUserAgentLen)
+	}
+
+	// Catch invalid handshake settings up front rather than on every
+	// connection.
+	if _, err := NewHandshake(&c.Handshake, false); err != nil {
+		return nil, err
+	}
+
+	return &AssociationManager{
+		cfg:     c,
+		inbound: make(map[string]*Association),
+		pending: make(map[string]chan struct{}),
+		all:     make(map[*Association]struct{}),
+	}, nil
+}
+
+// Connect establishes an outbound association with the peer at addr.  It
+// performs the version handshake advertising a new association ID and, when
+// the remote peer confirms it, opens a connection for each data stream the
+// stream policy routes messages to, limited to the configured stream types.
+//
+// A data stream which cannot be opened, because the remote peer rejects,
+// misanswers or does not answer its createstrm message in time, is left out
+// and its messages go on the general stream, as when a data stream fails
+// later on.  Any other failure, including the cancellation of ctx, tears down
+// the association.
+func (m *AssociationManager) Connect(ctx context.Context, addr string) (*Association, error) {
+	id, err := NewAssociationID()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := m.cfg.Handshake
+	cfg.AssociationID = id
+	cfg.EchoAssociationID = false
+
+	h, err := NewHandshake(&cfg, false)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := m.cfg.Dial(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	codec := wire.NewCodec(cfg.Net, h.ProtocolVersion())
+
+	np, err := h.Run(ctx, conn, codec)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	// Peers which do not support multistreams do not confirm the
+	// association.
+	if len(np.AssociationID) == 0 {
+		id = nil
+	} else if !bytes.Equal(np.AssociationID, id) {
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w: remote peer confirmed association %x instead of %x",
+			ErrAssociationMismatch, np.AssociationID, id)
+	}
+
+	a := newAssociation(id, np, conn, codec, m.forget)
+	if err := m.track(a, ""); err != nil {
+		a.teardown(err)
+		return nil, err
+	}
+
+	if !a.Multistream() {
+		return a, nil
+	}
+
+	policy := m.cfg.StreamPolicy
+	if policy == "" {
+		policy = SelectStreamPolicy(cfg.StreamPolicies, np.StreamPolicies).Name
+	}
+
+	a.setPolicy(policy)
+
+	for _, t := range m.streamTypes(a.Policy()) {
+		err := m.openStream(ctx, a, addr, t, policy)
+		if err == nil {
+			continue
+		}
+
+		if expired(ctx) || errors.Is(err, ErrAssociationClosed) {
+			a.teardown(err)
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// expired returns whether ctx is done or has passed its deadline.  The latter
+// catches reads which timed out on a connection deadline taken from ctx
+// before ctx itself noticed.
+func expired(ctx context.Context) bool {
+	if ctx.Err() != nil {
+		return true
+	}
+
+	deadline, ok := ctx.Deadline()
+
+	return ok && !time.Now().Before(deadline)
+}
+
+// streamTypes returns the types of the data streams opened for outbound
+// associations using the stream policy p.
+func (m *AssociationManager) streamTypes(p *StreamPolicy) []wire.StreamType {
+	types := p.StreamTypes()
+	if m.cfg.StreamTypes == nil {
+		return types
+	}
+
+	var limited []wire.StreamType
+
+	for _, t := range m.cfg.StreamTypes {
+		if slices.Contains(types, t) {
+			limited = append(limited, t)
+		}
+	}
+
+	return limited
+}
+
+// openStream opens the data stream of type t of the outbound association a
+// with the peer at addr for the stream policy named policy.
+func (m *AssociationManager) openStream(ctx context.Context, a *Association, addr string,
+	t wire.StreamType, policy string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.StreamTimeout)
+	defer cancel()
+
+	conn, err := m.cfg.Dial(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	s := &Stream{
+		assoc:  a,
+		typ:    t,
+		policy: policy,
+		conn:   conn,
+		codec:  m.newCodec(a.peer),
+	}
+
+	err = m.createStream(ctx, s)
+	if err == nil {
+		err = a.addStream(s)
+	}
+
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	return nil
+}
+
+// createStream performs the createstrm and streamack exchange on the
+// connection of the new data stream s.
+func (m *AssociationManager) createStream(ctx context.Context, s *Stream) error {
+	id := s.assoc.id
+
+	_, err := s.codec.WriteContext(ctx, s.conn, wire.NewMsgCreateStream(id, s.typ, s.policy))
+	if err != nil {
+		return err
+	}
+
+	_, msg, _, err := s.codec.ReadContext(ctx, s.conn)
+	if err != nil {
+		return err
+	}
+
+	switch msg := msg.(type) {
+	case *wire.MsgStreamAck:
+		if !bytes.Equal(msg.AssociationID, id) || msg.StreamType != s.typ {
+			return fmt.Errorf("%w: remote peer acknowledged stream %v of association %x "+
+				"instead of stream %v of %x", ErrAssociationMismatch, msg.StreamType,
+				msg.AssociationID, s.typ, id)
+		}
+
+		return nil
+
+	case *wire.MsgReject:
+		return fmt.Errorf("%w: %v: %s", ErrStreamRejected, msg.Code, msg.Reason)
+
+	default:
+		return fmt.Errorf("%w: %s in reply to createstrm", ErrUnexpectedMessage, msg.Command())
+	}
+}
+
+// Accept handles the inbound connection conn.  When its first message is a
+// version message, the version handshake is performed and the connection
+// becomes the general stream of a new association.  When it is a createstrm
+// message, the connection joins the association it refers to as a data
+// stream once the streamack message has been sent.  Either way the stream is
+// returned.
+//
+// A createstrm message which refers to an unknown association, asks for a
+// stream type other than the data stream types or for a stream the
+// association already has is answered with a reject message.  On failure
+// conn is closed.
+func (m *AssociationManager) Accept(ctx context.Context, conn net.Conn) (*Stream, error) {
+	s, err := m.accept(ctx, conn)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// accept handles the inbound connection conn as described by Accept.
+func (m *AssociationManager) accept(ctx context.Context, conn net.Conn) (*Stream, error) {
+	cfg := m.cfg.Handshake
+	cfg.EchoAssociationID = true
+
+	h, err := NewHandshake(&cfg, true)
+	if err != nil {
+		return nil, err
+	}
+
+	codec := wire.NewCodec(cfg.Net, h.ProtocolVersion())
+
+	firstCtx, cancel := context.WithTimeout(ctx, m.cfg.StreamTimeout)
+	_, first, _, err := codec.ReadContext(firstCtx, conn)
+
+	cancel()
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch msg := first.(type) {
+	case *wire.MsgVersion:
+		return m.acceptGeneral(ctx, conn, codec, h, msg)
+
+	case *wire.MsgCreateStream:
+		return m.acceptData(ctx, conn, msg)
+
+	default:
+		return nil, fmt.Errorf("%w: %s as first message", ErrUnexpectedMessage, first.Command())
+	}
+}
+
+// acceptGeneral completes the version handshake h which started with version
+// on conn and returns the general stream of the new association.
+func (m *AssociationManager) acceptGeneral(ctx context.Context, conn net.Conn, codec *wire.Codec,
+	h *Handshake, version *wire.MsgVersion,
+) (*Stream, error) {
+	key := string(version.AssociationID)
+	if key != "" {
+		// Data streams may arrive before the handshake has completed on
+		// this side, so they wait for it.
+		done := make(chan struct{})
+		defer close(done)
+
+		m.mtx.Lock()
+		if _, ok := m.pending[key]; ok || m.inbound[key] != nil {
+			m.mtx.Unlock()
+			return nil, fmt.Errorf("%w: association %x is already in use", ErrAssociationMismatch,
+				version.AssociationID)
+		}
+
+		m.pending[key] = done
+		m.mtx.Unlock()
+
+		defer func() {
+			m.mtx.Lock()
+			delete(m.pending, key)
+			m.mtx.Unlock()
+		}()
+	}
+
+	np, err := h.run(ctx, conn, codec, version)
+	if err != nil {
+		return nil, err
+	}
+
+	a := newAssociation(np.AssociationID, np, conn, codec, m.forget)
+	if err := m.track(a, key); err != nil {
+		a.teardown(err)
+		return nil, err
+	}
+
+	return a.general, nil
+}
+
+// acceptData adds conn as the data stream requested by msg to the association
+// it refers to and acknowledges it.
+func (m *AssociationManager) acceptData(ctx context.Context, conn net.Conn,
+	msg *wire.MsgCreateStream,
+) (*Stream, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.cfg.StreamTimeout)
+	defer cancel()
+
+	a, err := m.lookup(ctx, msg.AssociationID)
+	if err != nil {
+		m.reject(ctx, conn, m.cfg.Handshake.ProtocolVersion, err)
+		return nil, err
+	}
+
+	s := &Stream{
+		assoc:  a,
+		typ:    msg.StreamType,
+		policy: msg.StreamPolicyName,
+		conn:   conn,
+		codec:  m.newCodec(a.peer),
+	}
+
+	if !isDataStream(msg.StreamType) {
+		err = fmt.Errorf("%w: %v", ErrInvalidStreamType, msg.StreamType)
+	} else {
+		err = a.addStream(s)
+	}
+
+	if err != nil {
+		m.reject(ctx, conn, a.peer.ProtocolVersion, err)
+		return nil, err
+	}
+
+	ack := wire.NewMsgStreamAck(msg.AssociationID, msg.StreamType)
+	if _, err := s.codec.WriteContext(ctx, conn, ack); err != nil {
+		a.removeStream(s, err)
+		return nil, err
+	}
+
+	// The association routes its messages with the policy its data streams
+	// were created for, provided it is one the local node supports.
+	a.setPolicy(m.acceptedPolicy(msg.StreamPolicyName, a.peer))
+
+	return s, nil
+}
+
+// acceptedPolicy returns the name of the stream policy an inbound association
+// with the peer negotiated as summarised by np uses when its data streams are
+// created for the policy named name.  That is name when the local node
+// advertises it, and otherwise the policy SelectStreamPolicy picks from the
+// stream policies of the remote peer, which is the connecting side, and those
+// of the local node.
+func (m *AssociationManager) acceptedPolicy(name string, np *NegotiatedPeer) string {
+	local := m.cfg.Handshake.StreamPolicies
+	if slices.Contains(local, name) {
+		return name
+	}
+
+	return SelectStreamPolicy(np.StreamPolicies, local).Name
+}
+
+// lookup returns the inbound association with id, waiting for its handshake
+// to complete when it is in progress.
+func (m *AssociationManager) lookup(ctx context.Context, id []byte) (*Association, error) {
+	key := string(id)
+
+	m.mtx.Lock()
+	a, done := m.inbound[key], m.pending[key]
+	m.mtx.Unlock()
+
+	if a == nil && done != nil {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		m.mtx.Lock()
+		a = m.inbound[key]
+		m.mtx.Unlock()
+	}
+
+	if a == nil {
+		return nil, fmt.Errorf("%w: %x", ErrUnknownAssociation, id)
+	}
+
+	return a, nil
+}
+
+// reject answers the createstrm message received on conn which failed with
+// err with a reject message at protocol version pver, provided it supports
+// reject messages.  A zero pver means the local protocol version.
+func (m *AssociationManager) reject(ctx context.Context, conn net.Conn, pver uint32, err error) {
+	if pver == 0 {
+		pver = wire.ProtocolVersion
+	}
+
+	if pver < wire.RejectVersion {
+		return
+	}
+
+	code := wire.RejectInvalid
+	if errors.Is(err, ErrDuplicateStream) {
+		code = wire.RejectDuplicate
+	}
+
+	msg := wire.NewMsgReject(wire.CmdCreateStream, code, err.Error())
+	_, _ = wire.NewCodec(m.cfg.Handshake.Net, pver).WriteContext(ctx, conn, msg)
+}
+
+// newCodec returns a codec for a data stream of an association negotiated as
+// summarised by np.
+func (m *AssociationManager) newCodec(np *NegotiatedPeer) *wire.Codec {
+	codec := wire.NewCodec(m.cfg.Handshake.Net, np.ProtocolVersion)
+	np.Configure(codec)
+
+	return codec
+}
+
+// track registers the new association a, under key when it is an inbound
+// association which may be joined by data streams.
+func (m *AssociationManager) track(a *Association, key string) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.closed {
+		return ErrAssociationClosed
+	}
+
+	m.all[a] = struct{}{}
+
+	if key != "" {
+		m.inbound[key] = a
+	}
+
+	return nil
+}
+
+// forget unregisters the association a once it has been torn down.
+func (m *AssociationManager) forget(a *Association) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	delete(m.all, a)
+
+	if key := string(a.id); m.inbound[key] == a {
+		delete(m.inbound, key)
+	}
+}
+
+// Association returns the live inbound association with id, or nil when there
+// is none.
+func (m *AssociationManager) Association(id []byte) *Association {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.inbound[string(id)]
+}
+
+// Associations returns every live association.
+func (m *AssociationManager) Associations() []*Association {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	assocs := make([]*Association, 0, len(m.all))
+	for a := range m.all {
+		assocs = append(assocs, a)
+	}
+
+	return assocs
+}
+
+// Close tears down every association and makes the manager refuse new ones.
+func (m *AssociationManager) Close() error {
+	m.mtx.Lock()
+	m.closed = true
+	m.mtx.Unlock()
+
+	for _, a := range m.Associations() {
+		_ = a.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,504 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+// acceptResult is the outcome of accepting one connection.
+type acceptResult struct {
+	stream *Stream
+	err    error
+}
+
+// serve accepts connections on a local listener with m until the test ends
+// and returns the address of the listener along with the results.
+func serve(t *testing.T, m *AssociationManager) (string, <-chan acceptResult) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	results := make(chan acceptResult, 16)
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				s, err := m.Accept(context.Background(), conn)
+				results <- acceptResult{s, err}
+			}()
+		}
+	}()
+
+	t.Cleanup(func() {
+		_ = l.Close()
+		_ = m.Close()
+	})
+
+	return l.Addr().String(), results
+}
+
+// newManager returns a new AssociationManager for cfg which is closed when
+// the test ends.
+func newManager(t *testing.T, cfg *ManagerConfig) *AssociationManager {
+	t.Helper()
+
+	m, err := NewAssociationManager(cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = m.Close() })
+
+	return m
+}
+
+// nextResult returns the next accept result.
+func nextResult(t *testing.T, results <-chan acceptResult) acceptResult {
+	t.Helper()
+
+	select {
+	case r := <-results:
+		return r
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no connection accepted")
+		return acceptResult{}
+	}
+}
+
+// waitDone waits for the association to be torn down.
+func waitDone(t *testing.T, a *Association) {
+	t.Helper()
+
+	select {
+	case <-a.Done():
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "association not torn down")
+	}
+}
+
+// registerPolicy registers the stream policy name with routes until the test
+// ends.
+func registerPolicy(t *testing.T, name string, routes map[MessageClass]wire.StreamType) {
+	t.Helper()
+
+	require.NoError(t, RegisterStreamPolicy(&StreamPolicy{Name: name, Routes: routes}))
+	t.Cleanup(func() { UnregisterStreamPolicy(name) })
+}
+
+// TestAssociationManager ensures an outbound association opens every data
+// stream its stream policy routes to, the inbound side ties them to one
+// association, and both tear down once the general stream dies.
+func TestAssociationManager(t *testing.T) {
+	registerPolicy(t, "Spread", map[MessageClass]wire.StreamType{
+		ClassBlock: wire.StreamTypeData1,
+		ClassTx:    wire.StreamTypeData2,
+		ClassInv:   wire.StreamTypeData3,
+		ClassAddr:  wire.StreamTypeData4,
+	})
+
+	server := newManager(t, &ManagerConfig{Handshake: Config{StreamPolicies: []string{"Spread"}}})
+	addr, results := serve(t, server)
+
+	client := newManager(t, &ManagerConfig{StreamPolicy: "Spread"})
+
+	a, err := client.Connect(context.Background(), addr)
+	require.NoError(t, err)
+	require.True(t, a.Multistream())
+	require.Len(t, a.ID(), 17)
+
+	types := func(streams []*Stream) []wire.StreamType {
+		list := make([]wire.StreamType, len(streams))
+		for i, s := range streams {
+			list[i] = s.Type()
+		}
+
+		return list
+	}
+
+	all := []wire.StreamType{
+		wire.StreamTypeGeneral, wire.StreamTypeData1, wire.StreamTypeData2,
+		wire.StreamTypeData3, wire.StreamTypeData4,
+	}
+	assert.Equal(t, all, types(a.Streams()))
+	assert.Equal(t, "Spread", a.Stream(wire.StreamTypeData1).Policy())
+
+	// Every connection joins the same inbound association.
+	var remote *Association
+
+	for range all {
+		r := nextResult(t, results)
+		require.NoError(t, r.err)
+
+		if remote == nil {
+			remote = r.stream.Association()
+		}
+
+		assert.Same(t, remote, r.stream.Association())
+	}
+
+	assert.Equal(t, a.ID(), remote.ID())
+	assert.Same(t, remote, server.Association(a.ID()))
+	assert.Equal(t, all, types(remote.Streams()))
+	assert.Equal(t, "Spread", remote.Stream(wire.StreamTypeData3).Policy())
+	assert.Equal(t, "Spread", remote.Policy().Name)
+
+	clientData1 := a.Stream(wire.StreamTypeData1)
+	remoteData1 := remote.Stream(wire.StreamTypeData1)
+
+	// Messages flow over the data streams.
+	ctx := context.Background()
+	require.NoError(t, a.Stream(wire.StreamTypeData2).WriteMessage(ctx, wire.NewMsgPing(42)))
+
+	msg, err := remote.Stream(wire.StreamTypeData2).ReadMessage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, wire.NewMsgPing(42), msg)
+
+	// A data stream which dies is dropped on its own.
+	require.NoError(t, a.Stream(wire.StreamTypeData4).Close())
+
+	_, err = remote.Stream(wire.StreamTypeData4).ReadMessage(ctx)
+	require.Error(t, err)
+	assert.Nil(t, remote.Stream(wire.StreamTypeData4))
+	assert.NoError(t, remote.Err())
+
+	// Once the general stream dies, everything is torn down.
+	require.NoError(t, a.General().Close())
+	waitDone(t, a)
+	require.ErrorIs(t, a.Err(), ErrAssociationClosed)
+	assert.Empty(t, a.Streams())
+	assert.Empty(t, client.Associations())
+
+	_, err = remote.General().ReadMessage(ctx)
+	require.Error(t, err)
+	waitDone(t, remote)
+	assert.Nil(t, server.Association(a.ID()))
+
+	_, err = remoteData1.ReadMessage(ctx)
+	require.Error(t, err)
+
+	err = clientData1.WriteMessage(ctx, wire.NewMsgPing(1))
+	require.ErrorIs(t, err, ErrAssociationClosed)
+}
+
+// TestAssociationManagerStreamTypes ensures only the data streams the stream
+// policy routes to are opened, limited to the configured stream types.
+func TestAssociationManagerStreamTypes(t *testing.T) {
+	registerPolicy(t, "BlockTx", map[MessageClass]wire.StreamType{
+		ClassBlock: wire.StreamTypeData1,
+		ClassTx:    wire.StreamTypeData2,
+	})
+
+	tests := []struct {
+		name       string
+		policy     string
+		configured []wire.StreamType
+		want       []wire.StreamType
+	}{
+		{"policy routes", "BlockTx", nil, []wire.StreamType{wire.StreamTypeData1, wire.StreamTypeData2}},
+		{"configured order", "BlockTx",
+			[]wire.StreamType{wire.StreamTypeData2, wire.StreamTypeData1},
+			[]wire.StreamType{wire.StreamTypeData2, wire.StreamTypeData1}},
+		{"limited", "BlockTx",
+			[]wire.StreamType{wire.StreamTypeData2, wire.StreamTypeData3},
+			[]wire.StreamType{wire.StreamTypeData2}},
+		{"no routes", wire.DefaultStreamPolicy, nil, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newManager(t, &ManagerConfig{StreamPolicy: test.policy, StreamTypes: test.configured})
+
+			p, ok := LookupStreamPolicy(test.policy)
+			require.True(t, ok)
+			assert.Equal(t, test.want, m.streamTypes(p))
+		})
+	}
+
+	server := newManager(t, &ManagerConfig{})
+	addr, results := serve(t, server)
+
+	client := newManager(t, &ManagerConfig{
+		StreamPolicy: "BlockTx",
+		StreamTypes:  []wire.StreamType{wire.StreamTypeData2, wire.StreamTypeData3},
+	})
+
+	a, err := client.Connect(context.Background(), addr)
+	require.NoError(t, err)
+	assert.Len(t, a.Streams(), 2)
+	assert.NotNil(t, a.Stream(wire.StreamTypeData2))
+
+	for range 2 {
+		require.NoError(t, nextResult(t, results).err)
+	}
+}
+
+// TestAssociationManagerLegacyPeer ensures peers which do not support
+// multistreams yield an association with only a general stream.
+func TestAssociationManagerLegacyPeer(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if _, err := Negotiate(context.Background(), conn, &Config{}, true); err == nil {
+			_, _, _, _ = wire.ReadMessageN(conn, wire.ProtocolVersion, 0)
+		}
+	}()
+
+	client := newManager(t, &ManagerConfig{})
+
+	a, err := client.Connect(context.Background(), l.Addr().String())
+	require.NoError(t, err)
+	assert.False(t, a.Multistream())
+	assert.Nil(t, a.ID())
+	assert.Len(t, a.Streams(), 1)
+	assert.Nil(t, a.Stream(wire.StreamTypeData1))
+}
+
+// TestAssociationManagerRejects ensures createstrm messages which cannot be
+// honoured are rejected on both sides.
+func TestAssociationManagerRejects(t *testing.T) {
+	server := newManager(t, &ManagerConfig{})
+	addr, results := serve(t, server)
+
+	client := newManager(t, &ManagerConfig{StreamPolicy: wire.BlockPriorityStreamPolicy})
+
+	a, err := client.Connect(context.Background(), addr)
+	require.NoError(t, err)
+
+	for range 2 {
+		require.NoError(t, nextResult(t, results).err)
+	}
+
+	unknownID, err := NewAssociationID()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		msg  *wire.MsgCreateStream
+		err  error
+	}{
+		{"unknown association", wire.NewMsgCreateStream(unknownID, wire.StreamTypeData2, ""), ErrUnknownAssociation},
+		{"general stream", wire.NewMsgCreateStream(a.ID(), wire.StreamTypeGeneral, ""), ErrInvalidStreamType},
+		{"unknown stream type", wire.NewMsgCreateStream(a.ID(), 6, ""), ErrInvalidStreamType},
+		{"duplicate stream", wire.NewMsgCreateStream(a.ID(), wire.StreamTypeData1, ""), ErrDuplicateStream},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conn, err := net.Dial("tcp", addr)
+			require.NoError(t, err)
+			defer conn.Close()
+
+			require.NoError(t, wire.WriteMessage(conn, test.msg, wire.ProtocolVersion, 0))
+
+			_, msg, _, err := wire.ReadMessageN(conn, wire.ProtocolVersion, 0)
+			require.NoError(t, err)
+
+			reject, ok := msg.(*wire.MsgReject)
+			require.True(t, ok)
+			assert.Equal(t, wire.CmdCreateStream, reject.Cmd)
+
+			require.ErrorIs(t, nextResult(t, results).err, test.err)
+		})
+	}
+
+	// Only data streams may be opened.
+	_, err = NewAssociationManager(&ManagerConfig{StreamTypes: []wire.StreamType{wire.StreamTypeGeneral}})
+	require.ErrorIs(t, err, ErrInvalidStreamType)
+
+	_, err = NewAssociationManager(&ManagerConfig{
+		StreamTypes: []wire.StreamType{wire.StreamTypeData1, wire.StreamTypeData1},
+	})
+	require.ErrorIs(t, err, ErrDuplicateStream)
+}
+
+// streamPeer accepts an association on a local listener and answers the
+// createstrm message of its first data stream with answer.  It returns the
+// address of the listener along with the general stream once its handshake
+// has completed.
+func streamPeer(t *testing.T, answer func(conn net.Conn, msg *wire.MsgCreateStream)) (string, <-chan net.Conn) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	general := make(chan net.Conn, 1)
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		_, err = Negotiate(context.Background(), conn, &Config{EchoAssociationID: true}, true)
+		if err != nil {
+			_ = conn.Close()
+			return
+		}
+
+		general <- conn
+
+		data, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer data.Close()
+
+		_, msg, _, err := wire.ReadMessageN(data, wire.ProtocolVersion, 0)
+		if err != nil {
+			return
+		}
+
+		answer(data, msg.(*wire.MsgCreateStream))
+
+		// Wait for the client to give up.
+		_, _, _, _ = wire.ReadMessageN(data, wire.ProtocolVersion, 0)
+	}()
+
+	return l.Addr().String(), general
+}
+
+// TestAssociationManagerStreamErrors ensures a peer which rejects, stalls on
+// or misanswers a createstrm message only costs the outbound association that
+// data stream.
+func TestAssociationManagerStreamErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer func(conn net.Conn, msg *wire.MsgCreateStream)
+	}{
+		{
+			name: "rejected",
+			answer: func(conn net.Conn, msg *wire.MsgCreateStream) {
+				reject := wire.NewMsgReject(wire.CmdCreateStream, wire.RejectInvalid, "no")
+				_ = wire.WriteMessage(conn, reject, wire.ProtocolVersion, 0)
+			},
+		},
+		{
+			name: "wrong stream",
+			answer: func(conn net.Conn, msg *wire.MsgCreateStream) {
+				ack := wire.NewMsgStreamAck(msg.AssociationID, wire.StreamTypeData4)
+				_ = wire.WriteMessage(conn, ack, wire.ProtocolVersion, 0)
+			},
+		},
+		{
+			name: "unexpected message",
+			answer: func(conn net.Conn, msg *wire.MsgCreateStream) {
+				_ = wire.WriteMessage(conn, wire.NewMsgPing(1), wire.ProtocolVersion, 0)
+			},
+		},
+		{
+			name:   "stalled",
+			answer: func(conn net.Conn, msg *wire.MsgCreateStream) {},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			addr, general := streamPeer(t, test.answer)
+
+			client := newManager(t, &ManagerConfig{
+				StreamPolicy:  wire.BlockPriorityStreamPolicy,
+				StreamTimeout: 100 * time.Millisecond,
+			})
+
+			a, err := client.Connect(context.Background(), addr)
+			require.NoError(t, err)
+			assert.Len(t, a.Streams(), 1)
+			assert.Nil(t, a.Stream(wire.StreamTypeData1))
+			assert.Len(t, client.Associations(), 1)
+
+			// Blocks go on the general stream instead, which is alive.
+			assert.Same(t, a.General(), a.StreamFor(&wire.MsgBlock{}))
+
+			conn := <-general
+			defer conn.Close()
+
+			ctx := context.Background()
+			require.NoError(t, a.WriteMessage(ctx, wire.NewMsgPing(7)))
+
+			_, msg, _, err := wire.ReadMessageN(conn, wire.ProtocolVersion, 0)
+			require.NoError(t, err)
+			assert.Equal(t, wire.NewMsgPing(7), msg)
+		})
+	}
+
+	// Giving up on the association as a whole tears it down.
+	addr, general := streamPeer(t, func(conn net.Conn, msg *wire.MsgCreateStream) {})
+
+	client := newManager(t, &ManagerConfig{StreamPolicy: wire.BlockPriorityStreamPolicy})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Connect(ctx, addr)
+	require.Error(t, err)
+	assert.Empty(t, client.Associations())
+
+	// The general stream is closed once any messages the client sent
+	// along with the handshake have been read.
+	conn := <-general
+	defer conn.Close()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	for {
+		if _, _, _, err = wire.ReadMessageN(conn, wire.ProtocolVersion, 0); err != nil {
+			break
+		}
+	}
+
+	require.ErrorIs(t, err, io.EOF)
+}
+
+// TestAssociationManagerSelfConnection ensures a manager which connects to
+// itself notices.
+func TestAssociationManagerSelfConnection(t *testing.T) {
+	m := newManager(t, &ManagerConfig{})
+	addr, results := serve(t, m)
+
+	_, err := m.Connect(context.Background(), addr)
+	require.Error(t, err)
+	require.ErrorIs(t, nextResult(t, results).err, ErrSelfConnection)
+}
+
+// TestAssociationManagerClose ensures closing the manager tears down its
+// associations and refuses new ones.
+func TestAssociationManagerClose(t *testing.T) {
+	server := newManager(t, &ManagerConfig{})
+	addr, _ := serve(t, server)
+
+	client := newManager(t, &ManagerConfig{StreamTypes: []wire.StreamType{}})
+
+	a, err := client.Connect(context.Background(), addr)
+	require.NoError(t, err)
+	assert.Len(t, a.Streams(), 1)
+
+	require.NoError(t, client.Close())
+	waitDone(t, a)
+
+	_, err = client.Connect(context.Background(), addr)
+	require.ErrorIs(t, err, ErrAssociationClosed)
+}
@@ -0,0 +1,329 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+// MessageClass groups the commands a stream policy routes alike.
+type MessageClass uint8
+
+// These constants define the message classes.
+const (
+	// ClassOther is the class of commands which are not known to this
+	// package, such as those of custom messages.
+	ClassOther MessageClass = iota
+
+	// ClassControl is the class of the messages which set up and control
+	// the session, such as version, protoconf and reject.
+	ClassControl
+
+	// ClassBlock is the class of full and filtered blocks.
+	ClassBlock
+
+	// ClassCmpct is the class of compact blocks and the messages which
+	// complete them.
+	ClassCmpct
+
+	// ClassHeaders is the class of block headers and their requests.
+	ClassHeaders
+
+	// ClassTx is the class of transactions.
+	ClassTx
+
+	// ClassInv is the class of inventory announcements and requests.
+	ClassInv
+
+	// ClassPing is the class of ping and pong messages.
+	ClassPing
+
+	// ClassAddr is the class of address announcements and requests.
+	ClassAddr
+
+	// ClassFilter is the class of bloom and committed filter messages.
+	ClassFilter
+)
+
+// Map of message classes back to their constant names for pretty printing.
+var classStrings = map[MessageClass]string{
+	ClassOther:   "ClassOther",
+	ClassControl: "ClassControl",
+	ClassBlock:   "ClassBlock",
+	ClassCmpct:   "ClassCmpct",
+	ClassHeaders: "ClassHeaders",
+	ClassTx:      "ClassTx",
+	ClassInv:     "ClassInv",
+	ClassPing:    "ClassPing",
+	ClassAddr:    "ClassAddr",
+	ClassFilter:  "ClassFilter",
+}
+
+// String returns the MessageClass in human-readable form.
+func (c MessageClass) String() string {
+	if s, ok := classStrings[c]; ok {
+		return s
+	}
+
+	return fmt.Sprintf("Unknown MessageClass (%d)", uint8(c))
+}
+
+// Commands of the compact block messages, which package wire does not
+// implement but which may be registered with wire.RegisterMessage.
+const (
+	cmdCmpctBlock  = "cmpctblock"
+	cmdGetBlockTxn = "getblocktxn"
+	cmdBlockTxn    = "blocktxn"
+)
+
+// commandClasses maps the commands known to this package to their class.
+var commandClasses = map[string]MessageClass{
+	wire.CmdVersion:      ClassControl,
+	wire.CmdVerAck:       ClassControl,
+	wire.CmdProtoconf:    ClassControl,
+	wire.CmdSendHeaders:  ClassControl,
+	wire.CmdSendcmpct:    ClassControl,
+	wire.CmdFeeFilter:    ClassControl,
+	wire.CmdReject:       ClassControl,
+	wire.CmdCreateStream: ClassControl,
+	wire.CmdStreamAck:    ClassControl,
+	wire.CmdAuthch:       ClassControl,
+	wire.CmdAuthresp:     ClassControl,
+
+	wire.CmdBlock:       ClassBlock,
+	wire.CmdMerkleBlock: ClassBlock,
+
+	cmdCmpctBlock:  ClassCmpct,
+	cmdGetBlockTxn: ClassCmpct,
+	cmdBlockTxn:    ClassCmpct,
+
+	wire.CmdHeaders:    ClassHeaders,
+	wire.CmdGetHeaders: ClassHeaders,
+
+	wire.CmdTx:         ClassTx,
+	wire.CmdExtendedTx: ClassTx,
+
+	wire.CmdInv:       ClassInv,
+	wire.CmdGetData:   ClassInv,
+	wire.CmdNotFound:  ClassInv,
+	wire.CmdGetBlocks: ClassInv,
+	wire.CmdMemPool:   ClassInv,
+
+	wire.CmdPing: ClassPing,
+	wire.CmdPong: ClassPing,
+
+	wire.CmdAddr:    ClassAddr,
+	wire.CmdGetAddr: ClassAddr,
+
+	wire.CmdFilterAdd:    ClassFilter,
+	wire.CmdFilterClear:  ClassFilter,
+	wire.CmdFilterLoad:   ClassFilter,
+	wire.CmdGetCFilters:  ClassFilter,
+	wire.CmdGetCFHeaders: ClassFilter,
+	wire.CmdGetCFCheckpt: ClassFilter,
+	wire.CmdCFilter:      ClassFilter,
+	wire.CmdCFHeaders:    ClassFilter,
+	wire.CmdCFCheckpt:    ClassFilter,
+}
+
+// CommandClass returns the class of the messages with command, which is
+// ClassOther for commands unknown to this package.
+func CommandClass(command string) MessageClass {
+	if c, ok := commandClasses[command]; ok {
+		return c
+	}
+
+	return ClassOther
+}
+
+// ClassOf returns the class of msg.
+func ClassOf(msg wire.Message) MessageClass {
+	return CommandClass(msg.Command())
+}
+
+// StreamPolicy decides which stream of an association carries each outbound
+// message.  Peers advertise the names of the policies they support in their
+// protoconf message and use the first policy of the connecting side which
+// both support.
+//
+// A StreamPolicy must not be modified once it has been registered.
+type StreamPolicy struct {
+	// Name is the name the policy is advertised under.
+	Name string
+
+	// Routes maps message classes to the type of the stream which carries
+	// them.  Classes without a route are carried by the general stream.
+	Routes map[MessageClass]wire.StreamType
+}
+
+// StreamType returns the type of the stream which carries messages of class.
+func (p *StreamPolicy) StreamType(class MessageClass) wire.StreamType {
+	if t, ok := p.Routes[class]; ok {
+		return t
+	}
+
+	return wire.StreamTypeGeneral
+}
+
+// StreamTypes returns the data stream types the policy routes messages to,
+// ordered by type.
+func (p *StreamPolicy) StreamTypes() []wire.StreamType {
+	var types []wire.StreamType
+
+	for _, t := range p.Routes {
+		if isDataStream(t) && !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+
+	slices.Sort(types)
+
+	return types
+}
+
+var (
+	// defaultPolicy carries every message on the general stream.  It is the
+	// policy of associations without a common policy.
+	defaultPolicy = &StreamPolicy{Name: wire.DefaultStreamPolicy}
+
+	// blockPriorityPolicy carries block traffic on the first data stream
+	// so that it never queues behind transactions, which keep the general
+	// stream.
+	blockPriorityPolicy = &StreamPolicy{
+		Name: wire.BlockPriorityStreamPolicy,
+		Routes: map[MessageClass]wire.StreamType{
+			ClassBlock:   wire.StreamTypeData1,
+			ClassCmpct:   wire.StreamTypeData1,
+			ClassHeaders: wire.StreamTypeData1,
+		},
+	}
+)
+
+var (
+	// policyMtx guards streamPolicies.
+	policyMtx sync.RWMutex
+
+	// streamPolicies holds the registered stream policies by name.
+	streamPolicies = map[string]*StreamPolicy{
+		defaultPolicy.Name:       defaultPolicy,
+		blockPriorityPolicy.Name: blockPriorityPolicy,
+	}
+)
+
+// RegisterStreamPolicy registers p under its name, which must not contain a
+// comma since protoconf messages use it as separator.  Its routes may only
+// lead to the general stream and the data streams.  It is an error to register
+// a policy under a name which is already registered.
+//
+// This function is safe for concurrent access.
+func RegisterStreamPolicy(p *StreamPolicy) error {
+	if p == nil {
+		return errors.New("nil stream policy")
+	}
+
+	switch {
+	case p.Name == "":
+		return errors.New("empty stream policy name")
+
+	case strings.Contains(p.Name, ","):
+		return fmt.Errorf("stream policy name %q contains a comma", p.Name)
+
+	case len(p.Name) > wire.MaxUserAgentLen:
+		return fmt.Errorf("stream policy name is longer than %d bytes", wire.MaxUserAgentLen)
+	}
+
+	for class, t := range p.Routes {
+		if t != wire.StreamTypeGeneral && !isDataStream(t) {
+			return fmt.Errorf("%w: %v routed to %v", ErrInvalidStreamType, class, t)
+		}
+	}
+
+	policyMtx.Lock()
+	defer policyMtx.Unlock()
+
+	if _, ok := streamPolicies[p.Name]; ok {
+		return fmt.Errorf("stream policy %q is already registered", p.Name)
+	}
+
+	streamPolicies[p.Name] = &StreamPolicy{Name: p.Name, Routes: maps.Clone(p.Routes)}
+
+	return nil
+}
+
+// UnregisterStreamPolicy removes the registration for name, including those
+// of the policies provided by this package, and reports whether it was
+// registered.  Associations without a registered common policy use
+// wire.DefaultStreamPolicy regardless.
+//
+// This function is safe for concurrent access.
+func UnregisterStreamPolicy(name string) bool {
+	policyMtx.Lock()
+	defer policyMtx.Unlock()
+
+	_, ok := streamPolicies[name]
+	delete(streamPolicies, name)
+
+	return ok
+}
+
+// LookupStreamPolicy returns the stream policy registered under name and
+// whether one was found.
+//
+// This function is safe for concurrent access.
+func LookupStreamPolicy(name string) (*StreamPolicy, bool) {
+	policyMtx.RLock()
+	p, ok := streamPolicies[name]
+	policyMtx.RUnlock()
+
+	return p, ok
+}
+
+// RegisteredStreamPolicies returns a sorted list of the names of the
+// registered stream policies.
+//
+// This function is safe for concurrent access.
+func RegisteredStreamPolicies() []string {
+	policyMtx.RLock()
+	names := slices.Sorted(maps.Keys(streamPolicies))
+	policyMtx.RUnlock()
+
+	return names
+}
+
+// SelectStreamPolicy returns the first registered policy of local, the
+// policies of the connecting side in order of preference, which remote also
+// lists.  It returns the wire.DefaultStreamPolicy policy when there is none.
+//
+// This function is safe for concurrent access.
+func SelectStreamPolicy(local, remote []string) *StreamPolicy {
+	for _, name := range local {
+		if !slices.Contains(remote, name) {
+			continue
+		}
+
+		if p, ok := LookupStreamPolicy(name); ok {
+			return p
+		}
+	}
+
+	return defaultPolicy
+}
+
+// resolveStreamPolicy returns the stream policy registered under name, or the
+// wire.DefaultStreamPolicy policy when there is none.
+func resolveStreamPolicy(name string) *StreamPolicy {
+	if p, ok := LookupStreamPolicy(name); ok {
+		return p
+	}
+
+	return defaultPolicy
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bsv-blockchain/go-wire"
+)
+
+// TestMessageClass ensures commands map to the expected classes.
+func TestMessageClass(t *testing.T) {
+	tests := []struct {
+		msg  wire.Message
+		want MessageClass
+	}{
+		{wire.NewMsgVerAck(), ClassControl},
+		{wire.NewMsgSendHeaders(), ClassControl},
+		{wire.NewMsgBlock(&wire.BlockHeader{}), ClassBlock},
+		{wire.NewMsgHeaders(), ClassHeaders},
+		{wire.NewMsgGetHeaders(), ClassHeaders},
+		{wire.NewMsgTx(1), ClassTx},
+		{wire.NewMsgInv(), ClassInv},
+		{wire.NewMsgGetData(), ClassInv},
+		{wire.NewMsgPing(1), ClassPing},
+		{wire.NewMsgPong(1), ClassPing},
+		{wire.NewMsgAddr(), ClassAddr},
+		{wire.NewMsgFilterClear(), ClassFilter},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.want, ClassOf(test.msg), test.msg.Command())
+	}
+
+	assert.Equal(t, ClassCmpct, CommandClass("cmpctblock"))
+	assert.Equal(t, ClassOther, CommandClass("custom"))
+
+	assert.Equal(t, "ClassBlock", ClassBlock.String())
+	assert.Equal(t, "Unknown MessageClass (200)", MessageClass(200).String())
+}
+
+// TestStreamPolicyRegistry ensures the built-in policies are registered and
+// custom ones can be registered and unregistered.
+func TestStreamPolicyRegistry(t *testing.T) {
+	assert.Equal(t, []string{wire.BlockPriorityStreamPolicy, wire.DefaultStreamPolicy},
+		RegisteredStreamPolicies())
+
+	p, ok := LookupStreamPolicy(wire.BlockPriorityStreamPolicy)
+	require.True(t, ok)
+	assert.Equal(t, wire.StreamTypeData1, p.StreamType(ClassBlock))
+	assert.Equal(t, wire.StreamTypeGeneral, p.StreamType(ClassTx))
+	assert.Equal(t, []wire.StreamType{wire.StreamTypeData1}, p.StreamTypes())
+
+	p, ok = LookupStreamPolicy(wire.DefaultStreamPolicy)
+	require.True(t, ok)
+	assert.Equal(t, wire.StreamTypeGeneral, p.StreamType(ClassBlock))
+	assert.Empty(t, p.StreamTypes())
+
+	custom := &StreamPolicy{
+		Name: "TxSplit",
+		Routes: map[MessageClass]wire.StreamType{
+			ClassTx:  wire.StreamTypeData2,
+			ClassInv: wire.StreamTypeData2,
+		},
+	}
+	require.NoError(t, RegisterStreamPolicy(custom))
+
+	t.Cleanup(func() {
+		UnregisterStreamPolicy(custom.Name)
+	})
+
+	// The registered policy is a copy.
+	custom.Routes[ClassBlock] = wire.StreamTypeData3
+
+	p, ok = LookupStreamPolicy("TxSplit")
+	require.True(t, ok)
+	assert.Equal(t, wire.StreamTypeData2, p.StreamType(ClassTx))
+	assert.Equal(t, wire.StreamTypeGeneral, p.StreamType(ClassBlock))
+	assert.Equal(t, []wire.StreamType{wire.StreamTypeData2}, p.StreamTypes())
+
+	invalid := []*StreamPolicy{
+		nil,
+		{},
+		{Name: "a,b"},
+		{Name: string(make([]byte, wire.MaxUserAgentLen+1))},
+		{Name: "Bad", Routes: map[MessageClass]wire.StreamType{ClassTx: wire.StreamTypeUnknown}},
+		{Name: "Bad", Routes: map[MessageClass]wire.StreamType{ClassTx: wire.StreamTypeData4 + 1}},
+		{Name: "TxSplit"},
+		{Name: wire.DefaultStreamPolicy},
+	}
+
+	for i, p := range invalid {
+		assert.Error(t, RegisterStreamPolicy(p), "policy %d", i)
+	}
+
+	assert.True(t, UnregisterStreamPolicy("TxSplit"))
+	assert.False(t, UnregisterStreamPolicy("TxSplit"))
+
+	_, ok = LookupStreamPolicy("TxSplit")
+	assert.False(t, ok)
+}
+
+// TestSelectStreamPolicy ensures the first policy of the connecting side
+// which both sides support is selected.
+func TestSelectStreamPolicy(t *testing.T) {
+	both := []string{wire.BlockPriorityStreamPolicy, wire.DefaultStreamPolicy}
+
+	tests := []struct {
+		name          string
+		local, remote []string
+		want          string
+	}{
+		{"common", both, both, wire.BlockPriorityStreamPolicy},
+		{"local preference", both, []string{wire.DefaultStreamPolicy, wire.BlockPriorityStreamPolicy},
+			wire.BlockPriorityStreamPolicy},
+		{"remote subset", both, []string{wire.DefaultStreamPolicy}, wire.DefaultStreamPolicy},
+		{"unregistered", []string{"Unknown", wire.BlockPriorityStreamPolicy},
+			[]string{"Unknown", wire.BlockPriorityStreamPolicy}, wire.BlockPriorityStreamPolicy},
+		{"none in common", []string{wire.BlockPriorityStreamPolicy}, []string{"Other"}, wire.DefaultStreamPolicy},
+		{"nothing", nil, nil, wire.DefaultStreamPolicy},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, SelectStreamPolicy(test.local, test.remote).Name)
+		})
+	}
+}
+
+// TestAssociationRouting ensures an association routes messages as its
+// policy says and falls back to the general stream.
+func TestAssociationRouting(t *testing.T) {
+	a, _, _ := newPipeAssociation(t)
+
+	block := wire.NewMsgBlock(&wire.BlockHeader{})
+	tx := wire.NewMsgTx(1)
+
+	// Without an agreed policy everything goes on the general stream.
+	assert.Equal(t, wire.DefaultStreamPolicy, a.Policy().Name)
+	assert.Same(t, a.General(), a.StreamFor(block))
+
+	a.setPolicy(wire.BlockPriorityStreamPolicy)
+	a.setPolicy(wire.DefaultStreamPolicy)
+	assert.Equal(t, wire.BlockPriorityStreamPolicy, a.Policy().Name)
+
+	assert.Same(t, a.Stream(wire.StreamTypeData1), a.StreamFor(block))
+	assert.Same(t, a.Stream(wire.StreamTypeData1), a.StreamFor(wire.NewMsgHeaders()))
+	assert.Same(t, a.General(), a.StreamFor(tx))
+	assert.Same(t, a.General(), a.StreamFor(wire.NewMsgPing(1)))
+
+	// Once the data stream is gone, block traffic falls back.
+	require.NoError(t, a.Stream(wire.StreamTypeData1).Close())
+	assert.Same(t, a.General(), a.StreamFor(block))
+}
+
+// TestBlockPriority ensures block traffic never queues behind transactions
+// under the BlockPriority policy.
+func TestBlockPriority(t *testing.T) {
+	a, remoteGeneral, remoteData := newPipeAssociation(t)
+	a.setPolicy(wire.BlockPriorityStreamPolicy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Nobody reads the general stream, so the transaction is stuck.
+	txDone := make(chan error, 1)
+
+	go func() {
+		txDone <- a.WriteMessage(ctx, wire.NewMsgTx(1))
+	}()
+
+	received := make(chan wire.Message, 3)
+
+	go func() {
+		codec := wire.NewCodec(wire.MainNet, wire.ProtocolVersion)
+
+		for {
+			_, msg, _, err := codec.ReadContext(ctx, remoteData)
+			if err != nil {
+				return
+			}
+
+			received <- msg
+		}
+	}()
+
+	msgs := []wire.Message{
+		wire.NewMsgBlock(&wire.BlockHeader{}),
+		wire.NewMsgHeaders(),
+		wire.NewMsgGetHeaders(),
+	}
+
+	for _, msg := range msgs {
+		require.NoError(t, a.WriteMessage(ctx, msg))
+		assert.Equal(t, msg.Command(), (<-received).Command())
+	}
+
+	select {
+	case err := <-txDone:
+		require.FailNow(t, "transaction written without a reader", "%v", err)
+	default:
+	}
+
+	// The transaction is written once the general stream is read.
+	codec := wire.NewCodec(wire.MainNet, wire.ProtocolVersion)
+
+	_, msg, _, err := codec.ReadContext(ctx, remoteGeneral)
+	require.NoError(t, err)
+	assert.Equal(t, wire.CmdTx, msg.Command())
+	require.NoError(t, <-txDone)
+}
+
+// TestAssociationManagerPolicy ensures both sides of an association agree on
+// the stream policy preferred by the connecting side.
+func TestAssociationManagerPolicy(t *testing.T) {
+	both := []string{wire.BlockPriorityStreamPolicy, wire.DefaultStreamPolicy}
+
+	tests := []struct {
+		name           string
+		client, server []string
+		policy         string
+		want           string
+		wantRemote     string
+	}{
+		{"common", both, both, "", wire.BlockPriorityStreamPolicy, wire.BlockPriorityStreamPolicy},
+		{"server default only", both, nil, "", wire.DefaultStreamPolicy, wire.DefaultStreamPolicy},
+		{"client default only", nil, both, "", wire.DefaultStreamPolicy, wire.DefaultStreamPolicy},
+		{"unsupported by server", nil, nil, wire.BlockPriorityStreamPolicy,
+			wire.BlockPriorityStreamPolicy, wire.DefaultStreamPolicy},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newManager(t, &ManagerConfig{Handshake: Config{StreamPolicies: test.server}})
+			addr, results := serve(t, server)
+
+			client := newManager(t, &ManagerConfig{
+				Handshake:    Config{StreamPolicies: test.client},
+				StreamPolicy: test.policy,
+			})
+
+			a, err := client.Connect(context.Background(), addr)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, a.Policy().Name)
+
+			// Only the data streams the policy routes to are opened.
+			data := a.Policy().StreamTypes()
+			assert.Len(t, a.Streams(), 1+len(data))
+
+			for _, typ := range data {
+				assert.Equal(t, test.want, a.Stream(typ).Policy())
+			}
+
+			var remote *Association
+
+			for range 1 + len(data) {
+				r := nextResult(t, results)
+				require.NoError(t, r.err)
+
+				remote = r.stream.Association()
+			}
+
+			// The inbound side only adopts policies it supports.
+			assert.Equal(t, test.wantRemote, remote.Policy().Name)
+		})
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+// The functions in this file are the typed encoding and decoding primitives
+// used by the message implementations.  Unlike readElement and writeElement
+// they do not box their values in an interface or go through a type switch,
+// so they can be inlined, and they read directly from and write directly to
+// in-memory buffers and the slices of AppendBsvEncode, only borrowing from
+// binarySerializer for other readers and writers.
+
+// nextFixed returns the next n bytes of r without copying them when r is one
+// of the in-memory readers used by the decoding functions.  The bool result
+// reports whether r is such a reader; the caller must fall back to reading
+// from r when it is not.
+//
+// Mirroring io.ReadFull, io.EOF is returned when no bytes remain and
+// io.ErrUnexpectedEOF when fewer than n remain.
+func nextFixed(r io.Reader, n int) ([]byte, bool, error) {
+	var buf *bytes.Buffer
+
+	switch br := r.(type) {
+	case *sliceReader:
+		b, err := br.next(uint64(n))
+		return b, true, err
+
+	case *tracedBuffer:
+		buf = br.Buffer
+
+	case *bytes.Buffer:
+		buf = br
+
+	default:
+		return nil, false, nil
+	}
+
+	b := buf.Next(n)
+
+	switch len(b) {
+	case n:
+		return b, true, nil
+	case 0:
+		return nil, true, io.EOF
+	}
+
+	return b, true, io.ErrUnexpectedEOF
+}
+
+// readUint8 reads a single byte from r into v.
+func readUint8[T ~uint8](r io.Reader, v *T) error {
+	if b, ok, err := nextFixed(r, 1); ok {
+		if err != nil {
+			return err
+		}
+
+		*v = T(b[0])
+
+		return nil
+	}
+
+	rv, err := binarySerializer.Uint8(r)
+	if err != nil {
+		return err
+	}
+
+	*v = T(rv)
+
+	return nil
+}
+
+// readUint16 reads a little endian 16-bit integer from r into v.
+func readUint16[T ~uint16](r io.Reader, v *T) error {
+	if b, ok, err := nextFixed(r, 2); ok {
+		if err != nil {
+			return err
+		}
+
+		*v = T(binary.LittleEndian.Uint16(b))
+
+		return nil
+	}
+
+	rv, err := binarySerializer.Uint16(r, littleEndian)
+	if err != nil {
+		return err
+	}
+
+	*v = T(rv)
+
+	return nil
+}
+
+// readUint32 reads a little endian 32-bit integer from r into v.
+func readUint32[T ~uint32 | ~int32](r io.Reader, v *T) error {
+	if b, ok, err := nextFixed(r, 4); ok {
+		if err != nil {
+			return err
+		}
+
+		*v = T(binary.LittleEndian.Uint32(b))
+
+		return nil
+	}
+
+	rv, err := binarySerializer.Uint32(r, littleEndian)
+	if err != nil {
+		return err
+	}
+
+	*v = T(rv)
+
+	return nil
+}
+
+// readUint64 reads a little endian 64-bit integer from r into v.
+func readUint64[T ~uint64 | ~int64](r io.Reader, v *T) error {
+	if b, ok, err := nextFixed(r, 8); ok {
+		if err != nil {
+			return err
+		}
+
+		*v = T(binary.LittleEndian.Uint64(b))
+
+		return nil
+	}
+
+	rv, err := binarySerializer.Uint64(r, littleEndian)
+	if err != nil {
+		return err
+	}
+
+	*v = T(rv)
+
+	return nil
+}
+
+// readPort reads a big endian 16-bit port number from r.
+func readPort(r io.Reader) (uint16, error) {
+	if b, ok, err := nextFixed(r, 2); ok {
+		if err != nil {
+			return 0, err
+		}
+
+		return binary.BigEndian.Uint16(b), nil
+	}
+
+	return binarySerializer.Uint16(r, bigEndian)
+}
+
+// readBool reads a single byte from r into v, treating any non-zero value as
+// true.
+func readBool(r io.Reader, v *bool) error {
+	var rv uint8
+	if err := readUint8(r, &rv); err != nil {
+		return err
+	}
+
+	*v = rv != 0x00
+
+	return nil
+}
+
+// readFixed reads exactly len(dst) bytes from r into dst.  It is used for
+// fixed size byte arrays such as checksums, commands and IP addresses.
+func readFixed(r io.Reader, dst []byte) error {
+	if b, ok, err := nextFixed(r, len(dst)); ok {
+		copy(dst, b)
+		return err
+	}
+
+	_, err := io.ReadFull(r, dst)
+
+	return err
+}
+
+// readHash reads a hash from r into h.
+func readHash(r io.Reader, h *chainhash.Hash) error {
+	return readFixed(r, h[:])
+}
+
+// readLengthBytes reads a byte array prefixed by its length as a little
+// endian 32-bit integer, rather than a varint, from r and stores the length in
+// length.  Lengths larger than maxAllowed are rejected before allocating.
+// The funcName and fieldName parameters are only used for the error message.
+func readLengthBytes(r io.Reader, length *uint32, maxAllowed uint64,
+	funcName, fieldName string,
+) ([]byte, error) {
+	if err := readUint32(r, length); err != nil {
+		return nil, err
+	}
+
+	if uint64(*length) > maxAllowed {
+		str := fmt.Sprintf("%s is larger than the max allowed size "+
+			"[count %d, max %d]", fieldName, *length, maxAllowed)
+
+		return nil, messageError(funcName, ErrElementTooLarge, str)
+	}
+
+	b := make([]byte, *length)
+	if err := readFixed(r, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// writeUint8 writes v to w as a single byte.
+func writeUint8[T ~uint8](w io.Writer, v T) error {
+	switch bw := w.(type) {
+	case *bytes.Buffer:
+		return bw.WriteByte(uint8(v))
+
+	case *appendWriter:
+		bw.b = append(bw.b, uint8(v))
+		return nil
+	}
+
+	return binarySerializer.PutUint8(w, uint8(v))
+}
+
+// writeUint16 writes v to w as a little endian 16-bit integer.
+func writeUint16[T ~uint16](w io.Writer, v T) error {
+	switch bw := w.(type) {
+	case *bytes.Buffer:
+		_, err := bw.Write(binary.LittleEndian.AppendUint16(bw.AvailableBuffer(), uint16(v)))
+		return err
+
+	case *appendWriter:
+		bw.b = binary.LittleEndian.AppendUint16(bw.b, uint16(v))
+		return nil
+	}
+
+	return binarySerializer.PutUint16(w, littleEndian, uint16(v))
+}
+
+// writeUint32 writes v to w as a little endian 32-bit integer.
+func writeUint32[T ~uint32 | ~int32](w io.Writer, v T) error {
+	switch bw := w.(type) {
+	case *bytes.Buffer:
+		_, err := bw.Write(binary.LittleEndian.AppendUint32(bw.AvailableBuffer(), uint32(v)))
+		return err
+
+	case *appendWriter:
+		bw.b = binary.LittleEndian.AppendUint32(bw.b, uint32(v))
+		return nil
+	}
+
+	return binarySerializer.PutUint32(w, littleEndian, uint32(v))
+}
+
+// writeUint64 writes v to w as a little endian 64-bit integer.
+func writeUint64[T ~uint64 | ~int64](w io.Writer, v T) error {
+	switch bw := w.(type) {
+	case *bytes.Buffer:
+		_, err := bw.Write(binary.LittleEndian.AppendUint64(bw.AvailableBuffer(), uint64(v)))
+		return err
+
+	case *appendWriter:
+		bw.b = binary.LittleEndian.AppendUint64(bw.b, uint64(v))
+		return nil
+	}
+
+	return binarySerializer.PutUint64(w, littleEndian, uint64(v))
+}
+
+// writePort writes the port number v to w as a big endian 16-bit integer.
+func writePort(w io.Writer, v uint16) error {
+	switch bw := w.(type) {
+	case *bytes.Buffer:
+		_, err := bw.Write(binary.BigEndian.AppendUint16(bw.AvailableBuffer(), v))
+		return err
+
+	case *appendWriter:
+		bw.b = binary.BigEndian.AppendUint16(bw.b, v)
+		return nil
+	}
+
+	return binarySerializer.PutUint16(w, bigEndian, v)
+}
+
+// writeBool writes v to w as a single byte.
+func writeBool(w io.Writer, v bool) error {
+	var b uint8
+	if v {
+		b = 0x01
+	}
+
+	return writeUint8(w, b)
+}
+
+// writeFixed writes the fixed size byte array b to w.
+func writeFixed(w io.Writer, b []byte) error {
+	_, err := w.Write(b)
+	return err
+}
+
+// writeHash writes the hash h to w.
+func writeHash(w io.Writer, h *chainhash.Hash) error {
+	return writeFixed(w, h[:])
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// primitiveReaders returns constructors for each kind of reader the typed
+// primitives handle: the in-memory readers they read from directly and a
+// plain reader they fall back to binarySerializer for.
+func primitiveReaders() map[string]func(b []byte) io.Reader {
+	return map[string]func(b []byte) io.Reader{
+		"sliceReader":  func(b []byte) io.Reader { return &sliceReader{buf: b} },
+		"tracedBuffer": func(b []byte) io.Reader { return &tracedBuffer{Buffer: bytes.NewBuffer(b)} },
+		"bytes.Buffer": func(b []byte) io.Reader { return bytes.NewBuffer(b) },
+		"bytes.Reader": func(b []byte) io.Reader { return bytes.NewReader(b) },
+	}
+}
+
+// TestPrimitives ensures the typed primitives encode and decode like the
+// readElement and writeElement compatibility shims and report short reads like
+// io.ReadFull for every kind of reader.
+func TestPrimitives(t *testing.T) {
+	hash := mainNetGenesisHash
+
+	// Encode via the shims to a writer which is not a *bytes.Buffer.
+	var want bytes.Buffer
+
+	require.NoError(t, writeElements(struct{ io.Writer }{&want},
+		uint8(0x12), uint16(0x3456), int32(-2), uint64(1<<40), true, &hash))
+	require.NoError(t, writePort(struct{ io.Writer }{&want}, 8333))
+
+	for _, buffered := range []bool{false, true} {
+		var got bytes.Buffer
+
+		w := io.Writer(struct{ io.Writer }{&got})
+		if buffered {
+			w = &got
+		}
+
+		require.NoError(t, writeUint8(w, uint8(0x12)))
+		require.NoError(t, writeUint16(w, uint16(0x3456)))
+		require.NoError(t, writeUint32(w, int32(-2)))
+		require.NoError(t, writeUint64(w, uint64(1<<40)))
+		require.NoError(t, writeBool(w, true))
+		require.NoError(t, writeHash(w, &hash))
+		require.NoError(t, writePort(w, 8333))
+		assert.Equal(t, want.Bytes(), got.Bytes())
+	}
+
+	for name, newReader := range primitiveReaders() {
+		t.Run(name, func(t *testing.T) {
+			r := newReader(want.Bytes())
+
+			var (
+				u8   uint8
+				u16  uint16
+				i32  int32
+				u64  uint64
+				flag bool
+				h    chainhash.Hash
+			)
+
+			require.NoError(t, readUint8(r, &u8))
+			require.NoError(t, readUint16(r, &u16))
+			require.NoError(t, readUint32(r, &i32))
+			require.NoError(t, readUint64(r, &u64))
+			require.NoError(t, readBool(r, &flag))
+			require.NoError(t, readHash(r, &h))
+
+			port, err := readPort(r)
+			require.NoError(t, err)
+
+			assert.Equal(t, uint8(0x12), u8)
+			assert.Equal(t, uint16(0x3456), u16)
+			assert.Equal(t, int32(-2), i32)
+			assert.Equal(t, uint64(1<<40), u64)
+			assert.True(t, flag)
+			assert.Equal(t, hash, h)
+			assert.Equal(t, uint16(8333), port)
+
+			// Nothing left to read.
+			require.ErrorIs(t, readUint32(r, &i32), io.EOF)
+
+			// Too little left to read.
+			r = newReader([]byte{0x01, 0x02})
+			require.ErrorIs(t, readUint64(r, &u64), io.ErrUnexpectedEOF)
+
+			r = newReader(hash[:10])
+			require.ErrorIs(t, readHash(r, &h), io.ErrUnexpectedEOF)
+		})
+	}
+}
+
+// TestPrimitivesWriteError ensures the typed primitives return the errors of
+// the writer.
+func TestPrimitivesWriteError(t *testing.T) {
+	w := newFixedWriter(2)
+
+	require.NoError(t, writeUint16(w, uint16(1)))
+	require.ErrorIs(t, writeUint32(w, uint32(1)), io.ErrShortWrite)
+	require.ErrorIs(t, writeUint8(w, uint8(1)), io.ErrShortWrite)
+	require.ErrorIs(t, writeHash(w, &chainhash.Hash{}), io.ErrShortWrite)
+}
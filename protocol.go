@@ -56,6 +56,10 @@ const (
 	// ProtoconfVersion is the protocol version which added a new
 	// protoconf message
 	ProtoconfVersion uint32 = 70013
+
+	// ExtMsgVersion is the protocol version which added the extended
+	// message header, allowing payloads of 4GB and larger.
+	ExtMsgVersion uint32 = 70016
 )
 
 // ServiceFlag identifies services supported by a bitcoin peer.
@@ -0,0 +1,249 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"unicode/utf8"
+
+	"github.com/bsv-blockchain/go-bt/v2/chainhash"
+)
+
+// DefaultResyncBufferSize is the buffer size used by NewResyncReader.  Frames
+// which fit in the buffer are verified before they are consumed, see
+// ResyncReader.ReadMessage.
+const DefaultResyncBufferSize = 1024 * 1024
+
+// ResyncReader is a framing reader which is able to recover from corrupt,
+// truncated or misaligned frames by scanning forward for the next plausible
+// message header on the network of its codec.
+//
+// It is intended for lenient consumers such as capture-file tooling and relays.
+// Peers which should disconnect on the first malformed frame keep using
+// ReadMessageWithEncodingN or Codec.Read.
+//
+// A ResyncReader buffers the underlying reader, so once created it must be the
+// only reader of the stream.  It is not safe for concurrent access.
+type ResyncReader struct {
+	br    *bufio.Reader
+	codec *Codec
+}
+
+// NewResyncReader returns a ResyncReader reading messages from r using the
+// settings of codec and a buffer of DefaultResyncBufferSize bytes.
+func NewResyncReader(r io.Reader, codec *Codec) *ResyncReader {
+	return NewResyncReaderSize(r, codec, DefaultResyncBufferSize)
+}
+
+// NewResyncReaderSize returns a ResyncReader reading messages from r using the
+// settings of codec and a buffer of at least size bytes.  The buffer is never
+// smaller than ExtendedMessageHeaderSize.
+func NewResyncReaderSize(r io.Reader, codec *Codec, size int) *ResyncReader {
+	return &ResyncReader{
+		br:    bufio.NewReaderSize(r, max(size, ExtendedMessageHeaderSize)),
+		codec: codec,
+	}
+}
+
+// Resync discards bytes until the stream is positioned at the start of a
+// plausible message header and returns the number of bytes discarded, which is
+// zero when the stream is already positioned at one.
+//
+// A plausible header carries the network magic of the codec, a command of
+// printable UTF-8 characters padded with zeros and a payload length within the
+// maximum message payload of the codec.  For extended headers the same applies
+// to the command and the 64-bit length of the extended part.  The checksum is
+// not verified since that requires the payload.
+//
+// When the stream ends before a plausible header is found, the remaining bytes
+// are discarded and io.EOF is returned when there were none, or
+// io.ErrUnexpectedEOF otherwise.  Any other read error is returned as is.
+func (rr *ResyncReader) Resync() (int, error) {
+	p := rr.codec.snapshot()
+
+	var magic [4]byte
+	binary.LittleEndian.PutUint32(magic[:], uint32(p.bsvnet))
+
+	skipped := 0
+
+	for {
+		hdr, err := rr.br.Peek(MessageHeaderSize)
+		if len(hdr) < MessageHeaderSize {
+			if !errors.Is(err, io.EOF) {
+				return skipped, err
+			}
+
+			n, _ := rr.br.Discard(len(hdr))
+			skipped += n
+
+			if skipped == 0 {
+				return 0, io.EOF
+			}
+
+			return skipped, io.ErrUnexpectedEOF
+		}
+
+		// Skip straight to the next occurrence of the magic within the
+		// buffered bytes, keeping a possible partial magic at the end.
+		buf, _ := rr.br.Peek(rr.br.Buffered())
+
+		i := bytes.Index(buf, magic[:])
+		if i < 0 {
+			i = len(buf) - (len(magic) - 1)
+		}
+
+		if i > 0 {
+			n, _ := rr.br.Discard(i)
+			skipped += n
+
+			continue
+		}
+
+		if rr.plausibleHeader(p) {
+			return skipped, nil
+		}
+
+		n, _ := rr.br.Discard(1)
+		skipped += n
+	}
+}
+
+// plausibleHeader returns whether the buffered bytes start with a plausible
+// message header for the provided codec settings.
+func (rr *ResyncReader) plausibleHeader(p codecParams) bool {
+	buf, _ := rr.br.Peek(MessageHeaderSize)
+	if len(buf) < MessageHeaderSize || BitcoinNet(binary.LittleEndian.Uint32(buf[0:4])) != p.bsvnet {
+		return false
+	}
+
+	command := buf[4:16]
+	length := uint64(binary.LittleEndian.Uint32(buf[16:20]))
+
+	if isExtendedHeader(buf) {
+		buf, _ = rr.br.Peek(ExtendedMessageHeaderSize)
+		if len(buf) < ExtendedMessageHeaderSize {
+			return false
+		}
+
+		command = buf[24:36]
+		length = binary.LittleEndian.Uint64(buf[36:44])
+	}
+
+	return plausibleCommand(command) && length <= p.maxMessagePayload()
+}
+
+// isExtendedHeader returns whether the common message header in buf
+// introduces an extended header.
+func isExtendedHeader(buf []byte) bool {
+	var command [CommandSize]byte
+
+	copy(command[:], CmdExtMsg)
+
+	return bytes.Equal(buf[4:16], command[:]) &&
+		binary.LittleEndian.Uint32(buf[16:20]) == extLengthMarker &&
+		bytes.Equal(buf[20:24], []byte{0, 0, 0, 0})
+}
+
+// plausibleCommand returns whether the raw command field b holds a non-empty
+// command of printable UTF-8 characters followed only by zero padding.
+func plausibleCommand(b []byte) bool {
+	cmd, padding, _ := bytes.Cut(b, []byte{0})
+	if len(cmd) == 0 || !utf8.Valid(cmd) || bytes.ContainsFunc(cmd, func(r rune) bool { return r < 0x20 || r == 0x7f }) {
+		return false
+	}
+
+	return len(bytes.Trim(padding, "\x00")) == 0
+}
+
+// ReadMessage reads, validates and parses the next message, resynchronising
+// the stream as needed.  It returns the number of bytes skipped to find the
+// message and the number of bytes of the frame itself in addition to the
+// message and payload returned by Codec.Read.
+//
+// Frames which fit in the buffer of the reader are verified before they are
+// consumed: a frame which is truncated by the end of the stream or whose
+// checksum does not match is treated as corruption, and scanning resumes one
+// byte past its start so a message hidden inside the bad frame is still found.
+// A verified frame which then fails to decode, for instance because its
+// command is unknown, is consumed entirely and its error returned, leaving the
+// stream aligned at the next frame.
+//
+// Larger frames are read directly from the stream, so an error reading one
+// leaves the stream at an arbitrary offset.  The next call resynchronises from
+// there.
+//
+// The returned error is io.EOF only when the stream ended cleanly before the
+// next frame, with no bytes skipped.
+func (rr *ResyncReader) ReadMessage() (int, int, Message, []byte, error) {
+	p := rr.codec.snapshot()
+	skipped := 0
+
+	for {
+		n, err := rr.Resync()
+		skipped += n
+
+		if errors.Is(err, io.EOF) && skipped > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+
+		if err != nil {
+			return skipped, 0, nil, nil, err
+		}
+
+		hdrSize, hdr, err := readMessageHeader(rr.peekReader())
+		if err != nil {
+			return skipped, 0, nil, nil, err
+		}
+
+		frameSize := uint64(hdrSize) + hdr.payloadLength()
+		if frameSize > uint64(rr.br.Size()) {
+			n, msg, payload, err := rr.codec.readMessage(rr.br, p)
+			return skipped, n, msg, payload, err
+		}
+
+		frame, err := rr.br.Peek(int(frameSize))
+		if len(frame) < int(frameSize) && !errors.Is(err, io.EOF) {
+			return skipped, 0, nil, nil, err
+		}
+
+		if len(frame) < int(frameSize) || !hdr.extended && !checksumMatches(hdr, frame[hdrSize:]) {
+			n, _ := rr.br.Discard(1)
+			skipped += n
+
+			continue
+		}
+
+		// The frame is intact, so consume it regardless of whether it
+		// decodes.  It is copied first since decoding from a byte slice
+		// aliases it and the buffer of the reader is reused.
+		_, msg, payload, err := rr.codec.readMessage(&sliceReader{buf: bytes.Clone(frame)}, p)
+		n, _ = rr.br.Discard(int(frameSize))
+
+		return skipped, n, msg, payload, err
+	}
+}
+
+// peekReader returns a reader over the message header at the start of the
+// buffered bytes which does not consume them.  Only as many bytes as the header
+// requires are peeked, so reading a short message does not block waiting for
+// data which follows it.
+func (rr *ResyncReader) peekReader() io.Reader {
+	buf, _ := rr.br.Peek(MessageHeaderSize)
+	if len(buf) == MessageHeaderSize && isExtendedHeader(buf) {
+		buf, _ = rr.br.Peek(ExtendedMessageHeaderSize)
+	}
+
+	return bytes.NewReader(buf)
+}
+
+// checksumMatches returns whether the checksum of hdr matches payload.
+func checksumMatches(hdr *messageHeader, payload []byte) bool {
+	checksum := chainhash.DoubleHashB(payload)[0:4]
+	return bytes.Equal(checksum, hdr.checksum[:])
+}
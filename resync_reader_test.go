@@ -0,0 +1,226 @@
+// Copyright (c) 2026 The go-wire developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeFrame returns msg encoded with the provided codec.
+func encodeFrame(t *testing.T, codec *Codec, msg Message) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	_, err := codec.Write(&buf, msg)
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+// concat returns the concatenation of the provided byte slices.
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+// TestResyncReader ensures the resync reader recovers from garbage, corrupt
+// and truncated frames and reports the number of bytes it skipped.
+func TestResyncReader(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+
+	ping := encodeFrame(t, codec, NewMsgPing(1))
+	verack := encodeFrame(t, codec, NewMsgVerAck())
+	block := encodeFrame(t, codec, &blockOne)
+	testNetPing := encodeFrame(t, NewCodec(TestNet, ProtocolVersion), NewMsgPing(2))
+
+	badChecksum := bytes.Clone(ping)
+	badChecksum[MessageHeaderSize] ^= 0xff
+
+	badCommand := bytes.Clone(ping)
+	badCommand[4] = 0x01
+
+	// A magic followed by a command with data after its padding.
+	paddedCommand := bytes.Clone(ping)
+	paddedCommand[14] = 'x'
+
+	garbage := []byte{0xde, 0xad, 0xbe, 0xef, 0xe3, 0xe1, 0xf3, 0x00, 0x01}
+
+	type result struct {
+		skipped int
+		msg     Message
+	}
+
+	tests := []struct {
+		name   string
+		stream []byte
+		want   []result
+	}{
+		{"aligned", concat(ping, verack, block), []result{
+			{0, NewMsgPing(1)}, {0, NewMsgVerAck()}, {0, &blockOne},
+		}},
+		{"leading garbage", concat(garbage, verack), []result{
+			{len(garbage), NewMsgVerAck()},
+		}},
+		{"garbage between frames", concat(ping, garbage, block), []result{
+			{0, NewMsgPing(1)}, {len(garbage), &blockOne},
+		}},
+		{"bad checksum", concat(badChecksum, verack), []result{
+			{len(ping), NewMsgVerAck()},
+		}},
+		{"implausible command", concat(badCommand, verack), []result{
+			{len(ping), NewMsgVerAck()},
+		}},
+		{"data after command padding", concat(paddedCommand, verack), []result{
+			{len(ping), NewMsgVerAck()},
+		}},
+		{"other network", concat(testNetPing, verack), []result{
+			{len(testNetPing), NewMsgVerAck()},
+		}},
+		{"truncated frame swallowing the next", concat(ping[:MessageHeaderSize+3], verack, ping), []result{
+			{MessageHeaderSize + 3, NewMsgVerAck()}, {0, NewMsgPing(1)},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := NewResyncReader(bytes.NewReader(tt.stream), codec)
+
+			for i, want := range tt.want {
+				skipped, n, msg, _, err := rr.ReadMessage()
+				require.NoError(t, err, "message #%d", i)
+				assert.Equal(t, want.skipped, skipped, "message #%d", i)
+				assert.Equal(t, want.msg, msg, "message #%d", i)
+				assert.Positive(t, n)
+			}
+
+			skipped, _, _, _, err := rr.ReadMessage()
+			require.ErrorIs(t, err, io.EOF)
+			assert.Equal(t, 0, skipped)
+		})
+	}
+}
+
+// TestResyncReaderEndOfStream ensures the errors reported at the end of the
+// stream distinguish a clean end from trailing corruption.
+func TestResyncReaderEndOfStream(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	ping := encodeFrame(t, codec, NewMsgPing(1))
+
+	tests := []struct {
+		name    string
+		stream  []byte
+		skipped int
+	}{
+		{"garbage", []byte{0x01, 0x02, 0x03}, 3},
+		{"truncated header", ping[:10], 10},
+		{"truncated payload", ping[:len(ping)-1], len(ping) - 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := NewResyncReader(bytes.NewReader(tt.stream), codec)
+
+			skipped, n, msg, _, err := rr.ReadMessage()
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+			assert.Equal(t, tt.skipped, skipped)
+			assert.Equal(t, 0, n)
+			assert.Nil(t, msg)
+		})
+	}
+
+	// Errors other than the end of the stream are returned as is.
+	readErr := errors.New("read failure")
+	rr := NewResyncReader(io.MultiReader(bytes.NewReader(ping[:10]), iotest.ErrReader(readErr)), codec)
+
+	_, _, _, _, err := rr.ReadMessage()
+	require.ErrorIs(t, err, readErr)
+}
+
+// TestResyncReaderDecodeError ensures an intact frame which fails to decode is
+// consumed, leaving the stream aligned at the next frame.
+func TestResyncReaderDecodeError(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	unknown := encodeFrame(t, codec, &fakeMessage{command: "xunknown", payload: []byte{0x01, 0x02}})
+	verack := encodeFrame(t, codec, NewMsgVerAck())
+
+	rr := NewResyncReader(bytes.NewReader(concat(unknown, verack)), codec)
+
+	var msgErr *MessageError
+
+	skipped, n, _, _, err := rr.ReadMessage()
+	require.ErrorAs(t, err, &msgErr)
+	assert.Equal(t, 0, skipped)
+	assert.Equal(t, len(unknown), n)
+
+	skipped, _, msg, _, err := rr.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, 0, skipped)
+	assert.Equal(t, NewMsgVerAck(), msg)
+}
+
+// TestResyncReaderLargeFrames ensures frames larger than the buffer and
+// extended frames are read.
+func TestResyncReaderLargeFrames(t *testing.T) {
+	codec := NewCodec(MainNet, ProtocolVersion)
+	garbage := []byte{0x01, 0x02, 0x03}
+	block := encodeFrame(t, codec, &blockOne)
+
+	extCodec := NewCodec(MainNet, ProtocolVersion)
+	extCodec.SetExtendedMessages(true)
+	extPing := encodeFrame(t, extCodec, NewMsgPing(3))
+
+	rr := NewResyncReaderSize(bytes.NewReader(concat(garbage, block, extPing)), codec, 64)
+
+	skipped, n, msg, _, err := rr.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, len(garbage), skipped)
+	assert.Equal(t, len(block), n)
+	assert.Equal(t, &blockOne, msg)
+
+	skipped, n, msg, _, err = rr.ReadMessage()
+	require.NoError(t, err)
+	assert.Equal(t, 0, skipped)
+	assert.Equal(t, len(extPing), n)
+	assert.Equal(t, NewMsgPing(3), msg)
+}
+
+// TestPlausibleCommand ensures the command heuristic used while resyncing
+// accepts real commands and rejects garbage.
+func TestPlausibleCommand(t *testing.T) {
+	raw := func(s string) []byte {
+		var b [CommandSize]byte
+
+		copy(b[:], s)
+
+		return b[:]
+	}
+
+	tests := []struct {
+		name string
+		b    []byte
+		want bool
+	}{
+		{"version", raw(CmdVersion), true},
+		{"full length", raw("abcdefghijkl"), true},
+		{"empty", raw(""), false},
+		{"control character", raw("ver\x01ack"), false},
+		{"delete character", raw("ver\x7f"), false},
+		{"invalid utf-8", raw("\xff\xfe"), false},
+		{"data after padding", append(raw("ping")[:5], 'x', 0, 0, 0, 0, 0, 0), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, plausibleCommand(tt.b))
+		})
+	}
+}
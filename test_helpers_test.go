@@ -3,8 +3,10 @@ package wire
 import (
 	"bytes"
 	"errors"
+	"net"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -62,3 +64,104 @@ func assertWireError(t *testing.T, in, out Message, buf []byte, pver uint32,
 		require.ErrorIs(t, err, wantReadErr)
 	}
 }
+
+// sampleMessages returns a populated message of every type implemented by this
+// package, keyed by its command, for tests which exercise every message.  The
+// messages share data with the package-level test fixtures, so they must not
+// be modified.
+func sampleMessages(t *testing.T) map[string]Message {
+	t.Helper()
+
+	hash := mainNetGenesisHash
+	header := blockOne.Header
+	na := NewNetAddressIPPort(net.ParseIP("192.168.0.1"), 8333, SFNodeNetwork)
+	na.Timestamp = time.Unix(0x495fab29, 0)
+
+	addr := NewMsgAddr()
+	require.NoError(t, addr.AddAddress(na))
+
+	inv := NewMsgInv()
+	require.NoError(t, inv.AddInvVect(NewInvVect(InvTypeTx, &hash)))
+
+	getData := NewMsgGetData()
+	require.NoError(t, getData.AddInvVect(NewInvVect(InvTypeBlock, &hash)))
+
+	notFound := NewMsgNotFound()
+	require.NoError(t, notFound.AddInvVect(NewInvVect(InvTypeTx, &hash)))
+
+	getBlocks := NewMsgGetBlocks(&hash)
+	require.NoError(t, getBlocks.AddBlockLocatorHash(&hash))
+
+	getHeaders := NewMsgGetHeaders()
+	require.NoError(t, getHeaders.AddBlockLocatorHash(&hash))
+
+	headers := NewMsgHeaders()
+	require.NoError(t, headers.AddBlockHeader(&header))
+
+	merkleBlock := NewMsgMerkleBlock(&header)
+	merkleBlock.Transactions = 1
+	merkleBlock.Flags = []byte{0x01}
+	require.NoError(t, merkleBlock.AddTxHash(&hash))
+
+	cfHeaders := NewMsgCFHeaders()
+	cfHeaders.StopHash = hash
+	require.NoError(t, cfHeaders.AddCFHash(&hash))
+
+	cfCheckpt := NewMsgCFCheckpt(GCSFilterRegular, &hash, 1)
+	require.NoError(t, cfCheckpt.AddCFHeader(&hash))
+
+	reject := NewMsgReject(CmdBlock, RejectDuplicate, "duplicate block")
+	reject.Hash = hash
+
+	block := blockOne
+
+	version := NewMsgVersion(na, na, 123123, 7)
+	version.Timestamp = time.Unix(0x495fab29, 0)
+	version.AssociationID = []byte{0x01, 0x02}
+
+	msgs := []Message{
+		addr,
+		NewMsgAuthch("challenge"),
+		NewMsgAuthresp(bytes.Repeat([]byte{0x02}, 33), bytes.Repeat([]byte{0x30}, 71)),
+		&block,
+		cfHeaders,
+		cfCheckpt,
+		NewMsgCFilter(GCSFilterRegular, &hash, []byte{0x01, 0x02}),
+		NewMsgCreateStream([]byte{0x01, 0x02}, StreamTypeData1, "BlockPriority"),
+		NewMsgExtMsg(1 << 33),
+		multiExtendedTx,
+		NewMsgFeeFilter(1000),
+		NewMsgFilterAdd([]byte{0x01, 0x02}),
+		NewMsgFilterClear(),
+		NewMsgFilterLoad([]byte{0x01}, 10, 7, BloomUpdateAll),
+		NewMsgGetAddr(),
+		getBlocks,
+		NewMsgGetCFCheckpt(GCSFilterRegular, &hash),
+		NewMsgGetCFHeaders(GCSFilterRegular, 1, &hash),
+		NewMsgGetCFilters(GCSFilterRegular, 1, &hash),
+		getData,
+		getHeaders,
+		headers,
+		inv,
+		NewMsgMemPool(),
+		merkleBlock,
+		notFound,
+		NewMsgPing(123123),
+		NewMsgPong(123123),
+		NewMsgProtoconf(0, true),
+		reject,
+		NewMsgSendcmpct(true),
+		NewMsgSendHeaders(),
+		NewMsgStreamAck([]byte{0x01, 0x02}, StreamTypeData1),
+		multiTx,
+		NewMsgVerAck(),
+		version,
+	}
+
+	samples := make(map[string]Message, len(msgs))
+	for _, msg := range msgs {
+		samples[msg.Command()] = msg
+	}
+
+	return samples
+}
@@ -364,6 +364,19 @@ func BenchmarkSerializeTx(b *testing.B) {
 	}
 }
 
+// BenchmarkAppendSerializeTx performs a benchmark on how long it takes to
+// serialize a transaction into a reused buffer.
+func BenchmarkAppendSerializeTx(b *testing.B) {
+	tx := blockOne.Transactions[0]
+	buf := make([]byte, 0, tx.SerializeSize())
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf, _ = tx.AppendSerialize(buf[:0])
+	}
+}
+
 // BenchmarkReadBlockHeader performs a benchmark on how long it takes to
 // deserialize a block header.
 func BenchmarkReadBlockHeader(b *testing.B) {
@@ -401,6 +414,158 @@ func BenchmarkWriteBlockHeader(b *testing.B) {
 	}
 }
 
+// BenchmarkReadBlockHeaderElements performs a benchmark on how long it takes
+// to deserialize a block header from an in-memory buffer via the readElements
+// compatibility shim, which boxes every field in an interface.
+func BenchmarkReadBlockHeaderElements(b *testing.B) {
+	var buf bytes.Buffer
+	_ = writeBlockHeader(&buf, 0, &blockOne.Header)
+	r := &sliceReader{buf: buf.Bytes()}
+
+	var bh BlockHeader
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		r.off = 0
+		_ = readElements(r, &bh.Version, &bh.PrevBlock, &bh.MerkleRoot,
+			(*uint32Time)(&bh.Timestamp), &bh.Bits, &bh.Nonce)
+	}
+}
+
+// BenchmarkReadBlockHeaderTyped performs a benchmark on how long it takes to
+// deserialize a block header from an in-memory buffer via the typed
+// primitives.
+func BenchmarkReadBlockHeaderTyped(b *testing.B) {
+	var buf bytes.Buffer
+	_ = writeBlockHeader(&buf, 0, &blockOne.Header)
+	r := &sliceReader{buf: buf.Bytes()}
+
+	var bh BlockHeader
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		r.off = 0
+		_ = readBlockHeader(r, 0, &bh)
+	}
+}
+
+// BenchmarkWriteBlockHeaderElements performs a benchmark on how long it takes
+// to serialize a block header to an in-memory buffer via the writeElements
+// compatibility shim.
+func BenchmarkWriteBlockHeaderElements(b *testing.B) {
+	bh := blockOne.Header
+	buf := bytes.NewBuffer(make([]byte, 0, MaxBlockHeaderPayload))
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		_ = writeElements(buf, bh.Version, &bh.PrevBlock, &bh.MerkleRoot,
+			uint32(bh.Timestamp.Unix()), bh.Bits, bh.Nonce)
+	}
+}
+
+// BenchmarkWriteBlockHeaderTyped performs a benchmark on how long it takes to
+// serialize a block header to an in-memory buffer via the typed primitives.
+func BenchmarkWriteBlockHeaderTyped(b *testing.B) {
+	bh := blockOne.Header
+	buf := bytes.NewBuffer(make([]byte, 0, MaxBlockHeaderPayload))
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		_ = writeBlockHeader(buf, 0, &bh)
+	}
+}
+
+// BenchmarkReadInvVectElements performs a benchmark on how long it takes to
+// deserialize an inventory vector from an in-memory buffer via the
+// readElements compatibility shim.
+func BenchmarkReadInvVectElements(b *testing.B) {
+	var buf bytes.Buffer
+	_ = writeInvVect(&buf, 0, NewInvVect(InvTypeTx, &mainNetGenesisHash))
+	r := &sliceReader{buf: buf.Bytes()}
+
+	var iv InvVect
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		r.off = 0
+		_ = readElements(r, &iv.Type, &iv.Hash)
+	}
+}
+
+// BenchmarkReadInvVectTyped performs a benchmark on how long it takes to
+// deserialize an inventory vector from an in-memory buffer via the typed
+// primitives.
+func BenchmarkReadInvVectTyped(b *testing.B) {
+	var buf bytes.Buffer
+	_ = writeInvVect(&buf, 0, NewInvVect(InvTypeTx, &mainNetGenesisHash))
+	r := &sliceReader{buf: buf.Bytes()}
+
+	var iv InvVect
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		r.off = 0
+		_ = readInvVect(r, 0, &iv)
+	}
+}
+
+// BenchmarkReadNetAddressTyped performs a benchmark on how long it takes to
+// deserialize a timestamped network address from an in-memory buffer via the
+// typed primitives.
+func BenchmarkReadNetAddressTyped(b *testing.B) {
+	na := NewNetAddressIPPort(net.ParseIP("127.0.0.1"), 8333, SFNodeNetwork)
+
+	var buf bytes.Buffer
+	_ = writeNetAddress(&buf, ProtocolVersion, na, true)
+	r := &sliceReader{buf: buf.Bytes()}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		r.off = 0
+		_ = readNetAddress(r, ProtocolVersion, na, true)
+	}
+}
+
+// BenchmarkDecodeInvPayload performs a benchmark on how long it takes to
+// decode an in-memory inv payload with the maximum number of entries, as done
+// by Codec.Read.
+func BenchmarkDecodeInvPayload(b *testing.B) {
+	pver := ProtocolVersion
+
+	var m MsgInv
+
+	for i := 0; i < MaxInvPerMsg; i++ {
+		hash := chainhash.Hash{byte(i), byte(i >> 8), byte(i >> 16)}
+		_ = m.AddInvVect(NewInvVect(InvTypeBlock, &hash))
+	}
+
+	var bb bytes.Buffer
+	if err := m.BsvEncode(&bb, pver, LatestEncoding); err != nil {
+		b.Fatalf("MsgInv.BsvEncode: unexpected error: %v", err)
+	}
+
+	buf := bb.Bytes()
+
+	var msg MsgInv
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		msg.Reset()
+		_ = decodePayload(&msg, buf, codecParams{pver: pver, enc: LatestEncoding})
+	}
+}
+
 // BenchmarkDecodeGetHeaders performs a benchmark on how long it takes to
 // decode a getheaders message with the maximum number of block locator hashes.
 func BenchmarkDecodeGetHeaders(b *testing.B) {